@@ -0,0 +1,104 @@
+package repos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/weaviate/contextionary/contextionary/schema"
+	"github.com/weaviate/contextionary/server/config"
+)
+
+// WeaviateSchemaRepo retrieves the schema from the REST API of the weaviate
+// instance at SCHEMA_PROVIDER_URL
+type WeaviateSchemaRepo struct {
+	client        *http.Client
+	logger        logrus.FieldLogger
+	origin        string
+	watchInterval time.Duration
+}
+
+func NewSchemaRepo(logger logrus.FieldLogger,
+	config *config.Config, watchInterval time.Duration) *WeaviateSchemaRepo {
+	return &WeaviateSchemaRepo{
+		client:        &http.Client{},
+		logger:        logger,
+		origin:        config.SchemaProviderURL,
+		watchInterval: watchInterval,
+	}
+}
+
+type weaviateSchema struct {
+	Classes []schema.Class `json:"classes"`
+}
+
+// WatchAll sends the schema as soon as it could be retrieved for the first
+// time and then again whenever it has changed. It polls the schema every
+// watchInterval until stop is closed, then it closes the returned channel.
+func (r *WeaviateSchemaRepo) WatchAll(stop <-chan struct{}) <-chan []schema.Class {
+	returnCh := make(chan []schema.Class)
+
+	go func() {
+		defer close(returnCh)
+
+		ticker := time.NewTicker(r.watchInterval)
+		defer ticker.Stop()
+
+		var last []schema.Class
+		initialized := false
+		for {
+			classes, err := r.retrieve()
+			if err != nil {
+				r.logger.WithField("action", "schema_retrieve").
+					WithError(err).Error()
+			} else if !initialized || !reflect.DeepEqual(classes, last) {
+				select {
+				case returnCh <- classes:
+				case <-stop:
+					return
+				}
+				last = classes
+				initialized = true
+			}
+
+			select {
+			case <-ticker.C:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	return returnCh
+}
+
+func (r *WeaviateSchemaRepo) retrieve() ([]schema.Class, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("%s/v1/schema", r.origin), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+	if res.StatusCode > 399 {
+		return nil, fmt.Errorf("expected status < 399, got %d", res.StatusCode)
+	}
+
+	var s weaviateSchema
+	if err := json.NewDecoder(res.Body).Decode(&s); err != nil {
+		return nil, fmt.Errorf("decode schema: %v", err)
+	}
+
+	return s.Classes, nil
+}
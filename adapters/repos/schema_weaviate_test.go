@@ -0,0 +1,77 @@
+package repos
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weaviate/contextionary/server/config"
+)
+
+func TestWatchingTheSchema(t *testing.T) {
+	var version int32
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"classes": [{"class": "Car%d"}]}`, atomic.LoadInt32(&version))
+	}))
+	defer provider.Close()
+
+	logger, _ := test.NewNullLogger()
+	repo := NewSchemaRepo(logger, &config.Config{SchemaProviderURL: provider.URL}, 10*time.Millisecond)
+
+	t.Run("sending the schema initially and whenever it changes", func(t *testing.T) {
+		stop := make(chan struct{})
+		defer close(stop)
+		updates := repo.WatchAll(stop)
+
+		classes := <-updates
+		require.Len(t, classes, 1)
+		assert.Equal(t, "Car0", classes[0].Name)
+
+		atomic.StoreInt32(&version, 1)
+		classes = <-updates
+		require.Len(t, classes, 1)
+		assert.Equal(t, "Car1", classes[0].Name)
+	})
+
+	t.Run("stopping while nobody receives", func(t *testing.T) {
+		atomic.StoreInt32(&version, 2)
+		stop := make(chan struct{})
+		updates := repo.WatchAll(stop)
+
+		// the schema is never received, so the watcher blocks on sending it
+		time.Sleep(50 * time.Millisecond)
+		close(stop)
+
+		select {
+		case _, ok := <-updates:
+			if ok {
+				// the pending schema may still be received, then the channel
+				// must be closed
+				_, ok = <-updates
+			}
+			assert.False(t, ok, "the updates must be closed once stopped")
+		case <-time.After(5 * time.Second):
+			t.Fatal("the watcher didn't stop")
+		}
+	})
+
+	t.Run("stopping while waiting for the next poll", func(t *testing.T) {
+		stop := make(chan struct{})
+		updates := repo.WatchAll(stop)
+		<-updates
+		close(stop)
+
+		select {
+		case _, ok := <-updates:
+			assert.False(t, ok, "the updates must be closed once stopped")
+		case <-time.After(5 * time.Second):
+			t.Fatal("the watcher didn't stop")
+		}
+	})
+}
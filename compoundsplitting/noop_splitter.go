@@ -1,11 +1,13 @@
 package compoundsplitting
 
+import "context"
+
 type NoopSplitter struct{}
 
 func NewNoopSplitter() NoopSplitter {
 	return NoopSplitter{}
 }
 
-func (n NoopSplitter) Split(words string) ([]string, error) {
+func (n NoopSplitter) Split(ctx context.Context, words string) ([]string, error) {
 	return []string{}, nil
 }
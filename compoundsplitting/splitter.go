@@ -3,6 +3,7 @@ package compoundsplitting
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"time"
 )
 
@@ -29,10 +30,35 @@ type Dictionary interface {
 // Splitter builds a tree of compound splits and selects
 //  the best option based on a scoring mechanism
 type Splitter struct {
+	// counters of Stats, first for their alignment
+	attempts uint64
+	splits   uint64
+	timeouts uint64
+
 	dict Dictionary
 	cancelAfter       time.Duration
 }
 
+// Stats are counted since the splitter was created
+type Stats struct {
+	// Attempts to split a word
+	Attempts uint64
+	// Splits of a word into compounds
+	Splits uint64
+	// Timeouts of attempts which took too long, the best split found until
+	// then is used
+	Timeouts uint64
+}
+
+// Stats of the splitter
+func (sp *Splitter) Stats() Stats {
+	return Stats{
+		Attempts: atomic.LoadUint64(&sp.attempts),
+		Splits:   atomic.LoadUint64(&sp.splits),
+		Timeouts: atomic.LoadUint64(&sp.timeouts),
+	}
+}
+
 // New Splitter recognizing words given by dict and
 //  selecting split phrases based on scoring
 func NewSplitter(dict Dictionary) *Splitter {
@@ -47,18 +73,20 @@ type CompoundSplit struct {
 	combinations      []*Node
 }
 
-// Split a compound word into its compounds
-func (sp *Splitter) Split(word string) ([]string, error) {
+// Split a compound word into its compounds. The splitting gives up after a
+// while, or earlier if the context is cancelled.
+func (sp *Splitter) Split(parent context.Context, word string) ([]string, error) {
 
 	if len(word) > maxWordLength {
 		return []string{}, nil
 	}
 
+	atomic.AddUint64(&sp.attempts, 1)
 	compoundSplit := CompoundSplit{}
 
 	// spawn a new context that cancels the recursion if we are spending too much
 	// time on it
-	ctx, cancel := context.WithTimeout(context.Background(), sp.cancelAfter)
+	ctx, cancel := context.WithTimeout(parent, sp.cancelAfter)
 	defer cancel()
 
 	err := sp.findAllWordCombinations(ctx, word, &compoundSplit)
@@ -66,6 +94,14 @@ func (sp *Splitter) Split(word string) ([]string, error) {
 		return nil, err
 	}
 	combinations := compoundSplit.getAllWordCombinations(ctx)
+	if err := parent.Err(); err != nil {
+		// unlike our own timeout, the caller isn't interested in the
+		// combinations found so far
+		return nil, err
+	}
+	if ctx.Err() != nil {
+		atomic.AddUint64(&sp.timeouts, 1)
+	}
 	maxScore := 0.0
 	maxPhrase := []string{}
 	for _, combination := range combinations {
@@ -80,6 +116,9 @@ func (sp *Splitter) Split(word string) ([]string, error) {
 			maxPhrase = combination
 		}
 	}
+	if len(maxPhrase) > 0 {
+		atomic.AddUint64(&sp.splits, 1)
+	}
 	return maxPhrase, nil
 }
 
@@ -42,16 +42,18 @@ func TestSplitTreeSplitter(t *testing.T) {
 		fmt.Printf("%v\n", combination)
 	}
 
-	splited, err := ts.Split("driehoeksbroodje")
+	splited, err := ts.Split(context.Background(), "driehoeksbroodje")
 	assert.Nil(t, err)
 	require.Equal(t, 2, len(splited))
 	assert.Equal(t, "driehoek", splited[0])
 	assert.Equal(t, "broodje", splited[1])
 
 	// Test no result
-	splited, err = ts.Split("raupenprozessionsspinner")
+	splited, err = ts.Split(context.Background(), "raupenprozessionsspinner")
 	assert.Nil(t, err)
 	assert.Equal(t, 0, len(splited), "Expected no result since no substring is in the dict")
+
+	assert.Equal(t, Stats{Attempts: 2, Splits: 1}, ts.Stats())
 }
 
 func TestNegativeScore(t *testing.T) {
@@ -67,11 +69,19 @@ func TestNegativeScore(t *testing.T) {
 
 	ts := NewSplitter(dictMock)
 
-	splited, err := ts.Split("driehoeksbroodje")
+	splited, err := ts.Split(context.Background(), "driehoeksbroodje")
 	assert.Nil(t, err)
 	assert.Equal(t, 2, len(splited))
 	assert.Equal(t, "driehoek", splited[0])
 	assert.Equal(t, "broodje", splited[1])
+
+	t.Run("with a cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := ts.Split(ctx, "driehoeksbroodje")
+		assert.Equal(t, context.Canceled, err)
+	})
 }
 
 func TestInsertCompound(t *testing.T) {
@@ -268,13 +278,14 @@ func TestSplitVeryLongWords(t *testing.T) {
 
 	t1 := time.Now()
 
-	split, err := ts.Split("aaaabbbbaaaabbbbaaaabbbbaaaabbbbaaaabbbbaaaabbbbaaaabbbbaaaabbbbaaaabbbbaaaabbbbaaaabbbbaaaabbbbaaaa")
+	split, err := ts.Split(context.Background(), "aaaabbbbaaaabbbbaaaabbbbaaaabbbbaaaabbbbaaaabbbbaaaabbbbaaaabbbbaaaabbbbaaaabbbbaaaabbbbaaaabbbbaaaa")
 
 	t2 := time.Now()
 	diff := t2.Sub(t1)
 
 	assert.Nil(t, err)
 	assert.Less(t, 0, len(split))
+	assert.Equal(t, uint64(1), ts.Stats().Timeouts)
 
 	if diff > time.Millisecond*200 {
 		fmt.Errorf("Splitter took too long")
@@ -294,7 +305,7 @@ func TestSplitTooLongWords(t *testing.T) {
 		dict: dictMock,
 	}
 
-	split, err := ts.Split("aaaabbbbaaaabbbbaaaabbbbaaaabbbbaaaabbbbaaaabbbbaaaabbbbaaaabbbbaaaabbbbaaaabbbbaaaabbbbaaaabbbbaaaabbbb")
+	split, err := ts.Split(context.Background(), "aaaabbbbaaaabbbbaaaabbbbaaaabbbbaaaabbbbaaaabbbbaaaabbbbaaaabbbbaaaabbbbaaaabbbbaaaabbbbaaaabbbbaaaabbbb")
 
 	assert.Nil(t, err)
 	assert.Equal(t, 0, len(split))
@@ -315,7 +326,7 @@ func TestUnboundTree(t *testing.T) {
 
 	t1 := time.Now()
 
-	_, err := ts.Split("ql55555555555555555555555555555")
+	_, err := ts.Split(context.Background(), "ql55555555555555555555555555555")
 
 	t2 := time.Now()
 	diff := t2.Sub(t1)
@@ -53,6 +53,7 @@ type ExtensionInput struct {
 	Concept              string   `protobuf:"bytes,1,opt,name=concept,proto3" json:"concept,omitempty"`
 	Definition           string   `protobuf:"bytes,2,opt,name=definition,proto3" json:"definition,omitempty"`
 	Weight               float32  `protobuf:"fixed32,3,opt,name=weight,proto3" json:"weight,omitempty"`
+	Language             string   `protobuf:"bytes,4,opt,name=language,proto3" json:"language,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -104,6 +105,13 @@ func (m *ExtensionInput) GetWeight() float32 {
 	return 0
 }
 
+func (m *ExtensionInput) GetLanguage() string {
+	if m != nil {
+		return m.Language
+	}
+	return ""
+}
+
 type AddExtensionResult struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -136,6 +144,7 @@ func (m *AddExtensionResult) XXX_DiscardUnknown() {
 var xxx_messageInfo_AddExtensionResult proto.InternalMessageInfo
 
 type MetaParams struct {
+	Language             string   `protobuf:"bytes,1,opt,name=language,proto3" json:"language,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -166,9 +175,18 @@ func (m *MetaParams) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_MetaParams proto.InternalMessageInfo
 
+func (m *MetaParams) GetLanguage() string {
+	if m != nil {
+		return m.Language
+	}
+	return ""
+}
+
 type MetaOverview struct {
 	Version              string   `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
 	WordCount            int64    `protobuf:"varint,2,opt,name=wordCount,proto3" json:"wordCount,omitempty"`
+	Metric               string   `protobuf:"bytes,3,opt,name=metric,proto3" json:"metric,omitempty"`
+	CertaintyMaxDistance float32  `protobuf:"fixed32,4,opt,name=certaintyMaxDistance,proto3" json:"certaintyMaxDistance,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -213,8 +231,23 @@ func (m *MetaOverview) GetWordCount() int64 {
 	return 0
 }
 
+func (m *MetaOverview) GetMetric() string {
+	if m != nil {
+		return m.Metric
+	}
+	return ""
+}
+
+func (m *MetaOverview) GetCertaintyMaxDistance() float32 {
+	if m != nil {
+		return m.CertaintyMaxDistance
+	}
+	return 0
+}
+
 type Word struct {
 	Word                 string   `protobuf:"bytes,1,opt,name=word,proto3" json:"word,omitempty"`
+	Language             string   `protobuf:"bytes,2,opt,name=language,proto3" json:"language,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -252,8 +285,16 @@ func (m *Word) GetWord() string {
 	return ""
 }
 
+func (m *Word) GetLanguage() string {
+	if m != nil {
+		return m.Language
+	}
+	return ""
+}
+
 type WordList struct {
 	Words                []*Word  `protobuf:"bytes,1,rep,name=words,proto3" json:"words,omitempty"`
+	Language             string   `protobuf:"bytes,2,opt,name=language,proto3" json:"language,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -291,6 +332,13 @@ func (m *WordList) GetWords() []*Word {
 	return nil
 }
 
+func (m *WordList) GetLanguage() string {
+	if m != nil {
+		return m.Language
+	}
+	return ""
+}
+
 type WordPresent struct {
 	Present              bool     `protobuf:"varint,1,opt,name=present,proto3" json:"present,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -330,6 +378,163 @@ func (m *WordPresent) GetPresent() bool {
 	return false
 }
 
+type SuggestWordsParams struct {
+	Word                 string   `protobuf:"bytes,1,opt,name=word,proto3" json:"word,omitempty"`
+	MaxEdits             int32    `protobuf:"varint,2,opt,name=maxEdits,proto3" json:"maxEdits,omitempty"`
+	Limit                int32    `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Language             string   `protobuf:"bytes,4,opt,name=language,proto3" json:"language,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SuggestWordsParams) Reset()         { *m = SuggestWordsParams{} }
+func (m *SuggestWordsParams) String() string { return proto.CompactTextString(m) }
+func (*SuggestWordsParams) ProtoMessage()    {}
+func (*SuggestWordsParams) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6af9fd695f521f0, []int{7}
+}
+
+func (m *SuggestWordsParams) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SuggestWordsParams.Unmarshal(m, b)
+}
+func (m *SuggestWordsParams) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SuggestWordsParams.Marshal(b, m, deterministic)
+}
+func (m *SuggestWordsParams) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SuggestWordsParams.Merge(m, src)
+}
+func (m *SuggestWordsParams) XXX_Size() int {
+	return xxx_messageInfo_SuggestWordsParams.Size(m)
+}
+func (m *SuggestWordsParams) XXX_DiscardUnknown() {
+	xxx_messageInfo_SuggestWordsParams.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SuggestWordsParams proto.InternalMessageInfo
+
+func (m *SuggestWordsParams) GetWord() string {
+	if m != nil {
+		return m.Word
+	}
+	return ""
+}
+
+func (m *SuggestWordsParams) GetMaxEdits() int32 {
+	if m != nil {
+		return m.MaxEdits
+	}
+	return 0
+}
+
+func (m *SuggestWordsParams) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *SuggestWordsParams) GetLanguage() string {
+	if m != nil {
+		return m.Language
+	}
+	return ""
+}
+
+type WordSuggestions struct {
+	Suggestions          []*WordSuggestion `protobuf:"bytes,1,rep,name=suggestions,proto3" json:"suggestions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *WordSuggestions) Reset()         { *m = WordSuggestions{} }
+func (m *WordSuggestions) String() string { return proto.CompactTextString(m) }
+func (*WordSuggestions) ProtoMessage()    {}
+func (*WordSuggestions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6af9fd695f521f0, []int{8}
+}
+
+func (m *WordSuggestions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WordSuggestions.Unmarshal(m, b)
+}
+func (m *WordSuggestions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WordSuggestions.Marshal(b, m, deterministic)
+}
+func (m *WordSuggestions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WordSuggestions.Merge(m, src)
+}
+func (m *WordSuggestions) XXX_Size() int {
+	return xxx_messageInfo_WordSuggestions.Size(m)
+}
+func (m *WordSuggestions) XXX_DiscardUnknown() {
+	xxx_messageInfo_WordSuggestions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WordSuggestions proto.InternalMessageInfo
+
+func (m *WordSuggestions) GetSuggestions() []*WordSuggestion {
+	if m != nil {
+		return m.Suggestions
+	}
+	return nil
+}
+
+type WordSuggestion struct {
+	Word                 string   `protobuf:"bytes,1,opt,name=word,proto3" json:"word,omitempty"`
+	Distance             int32    `protobuf:"varint,2,opt,name=distance,proto3" json:"distance,omitempty"`
+	Occurrence           uint64   `protobuf:"varint,3,opt,name=occurrence,proto3" json:"occurrence,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WordSuggestion) Reset()         { *m = WordSuggestion{} }
+func (m *WordSuggestion) String() string { return proto.CompactTextString(m) }
+func (*WordSuggestion) ProtoMessage()    {}
+func (*WordSuggestion) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6af9fd695f521f0, []int{9}
+}
+
+func (m *WordSuggestion) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WordSuggestion.Unmarshal(m, b)
+}
+func (m *WordSuggestion) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WordSuggestion.Marshal(b, m, deterministic)
+}
+func (m *WordSuggestion) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WordSuggestion.Merge(m, src)
+}
+func (m *WordSuggestion) XXX_Size() int {
+	return xxx_messageInfo_WordSuggestion.Size(m)
+}
+func (m *WordSuggestion) XXX_DiscardUnknown() {
+	xxx_messageInfo_WordSuggestion.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WordSuggestion proto.InternalMessageInfo
+
+func (m *WordSuggestion) GetWord() string {
+	if m != nil {
+		return m.Word
+	}
+	return ""
+}
+
+func (m *WordSuggestion) GetDistance() int32 {
+	if m != nil {
+		return m.Distance
+	}
+	return 0
+}
+
+func (m *WordSuggestion) GetOccurrence() uint64 {
+	if m != nil {
+		return m.Occurrence
+	}
+	return 0
+}
+
 type Vector struct {
 	Entries              []*VectorEntry  `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
 	Source               []*InputElement `protobuf:"bytes,2,rep,name=source,proto3" json:"source,omitempty"`
@@ -342,7 +547,7 @@ func (m *Vector) Reset()         { *m = Vector{} }
 func (m *Vector) String() string { return proto.CompactTextString(m) }
 func (*Vector) ProtoMessage()    {}
 func (*Vector) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e6af9fd695f521f0, []int{7}
+	return fileDescriptor_e6af9fd695f521f0, []int{10}
 }
 
 func (m *Vector) XXX_Unmarshal(b []byte) error {
@@ -381,6 +586,7 @@ type InputElement struct {
 	Concept              string   `protobuf:"bytes,1,opt,name=concept,proto3" json:"concept,omitempty"`
 	Weight               float32  `protobuf:"fixed32,2,opt,name=weight,proto3" json:"weight,omitempty"`
 	Occurrence           uint64   `protobuf:"varint,3,opt,name=occurrence,proto3" json:"occurrence,omitempty"`
+	Synthesized          bool     `protobuf:"varint,4,opt,name=synthesized,proto3" json:"synthesized,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -390,7 +596,7 @@ func (m *InputElement) Reset()         { *m = InputElement{} }
 func (m *InputElement) String() string { return proto.CompactTextString(m) }
 func (*InputElement) ProtoMessage()    {}
 func (*InputElement) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e6af9fd695f521f0, []int{8}
+	return fileDescriptor_e6af9fd695f521f0, []int{11}
 }
 
 func (m *InputElement) XXX_Unmarshal(b []byte) error {
@@ -432,18 +638,26 @@ func (m *InputElement) GetOccurrence() uint64 {
 	return 0
 }
 
+func (m *InputElement) GetSynthesized() bool {
+	if m != nil {
+		return m.Synthesized
+	}
+	return false
+}
+
 type VectorList struct {
-	Vectors              []*Vector `protobuf:"bytes,1,rep,name=vectors,proto3" json:"vectors,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
-	XXX_unrecognized     []byte    `json:"-"`
-	XXX_sizecache        int32     `json:"-"`
+	Vectors              []*Vector    `protobuf:"bytes,1,rep,name=vectors,proto3" json:"vectors,omitempty"`
+	Errors               []*ItemError `protobuf:"bytes,2,rep,name=errors,proto3" json:"errors,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
 }
 
 func (m *VectorList) Reset()         { *m = VectorList{} }
 func (m *VectorList) String() string { return proto.CompactTextString(m) }
 func (*VectorList) ProtoMessage()    {}
 func (*VectorList) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e6af9fd695f521f0, []int{9}
+	return fileDescriptor_e6af9fd695f521f0, []int{12}
 }
 
 func (m *VectorList) XXX_Unmarshal(b []byte) error {
@@ -471,6 +685,13 @@ func (m *VectorList) GetVectors() []*Vector {
 	return nil
 }
 
+func (m *VectorList) GetErrors() []*ItemError {
+	if m != nil {
+		return m.Errors
+	}
+	return nil
+}
+
 type VectorEntry struct {
 	Entry                float32  `protobuf:"fixed32,1,opt,name=Entry,proto3" json:"Entry,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -482,7 +703,7 @@ func (m *VectorEntry) Reset()         { *m = VectorEntry{} }
 func (m *VectorEntry) String() string { return proto.CompactTextString(m) }
 func (*VectorEntry) ProtoMessage()    {}
 func (*VectorEntry) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e6af9fd695f521f0, []int{10}
+	return fileDescriptor_e6af9fd695f521f0, []int{13}
 }
 
 func (m *VectorEntry) XXX_Unmarshal(b []byte) error {
@@ -514,6 +735,7 @@ type VectorNNParams struct {
 	Vector               *Vector  `protobuf:"bytes,1,opt,name=vector,proto3" json:"vector,omitempty"`
 	K                    int32    `protobuf:"varint,2,opt,name=k,proto3" json:"k,omitempty"`
 	N                    int32    `protobuf:"varint,3,opt,name=n,proto3" json:"n,omitempty"`
+	Language             string   `protobuf:"bytes,4,opt,name=language,proto3" json:"language,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -523,7 +745,7 @@ func (m *VectorNNParams) Reset()         { *m = VectorNNParams{} }
 func (m *VectorNNParams) String() string { return proto.CompactTextString(m) }
 func (*VectorNNParams) ProtoMessage()    {}
 func (*VectorNNParams) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e6af9fd695f521f0, []int{11}
+	return fileDescriptor_e6af9fd695f521f0, []int{14}
 }
 
 func (m *VectorNNParams) XXX_Unmarshal(b []byte) error {
@@ -565,8 +787,16 @@ func (m *VectorNNParams) GetN() int32 {
 	return 0
 }
 
+func (m *VectorNNParams) GetLanguage() string {
+	if m != nil {
+		return m.Language
+	}
+	return ""
+}
+
 type VectorNNParamsList struct {
 	Params               []*VectorNNParams `protobuf:"bytes,1,rep,name=Params,proto3" json:"Params,omitempty"`
+	Language             string            `protobuf:"bytes,2,opt,name=language,proto3" json:"language,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
 	XXX_unrecognized     []byte            `json:"-"`
 	XXX_sizecache        int32             `json:"-"`
@@ -576,7 +806,7 @@ func (m *VectorNNParamsList) Reset()         { *m = VectorNNParamsList{} }
 func (m *VectorNNParamsList) String() string { return proto.CompactTextString(m) }
 func (*VectorNNParamsList) ProtoMessage()    {}
 func (*VectorNNParamsList) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e6af9fd695f521f0, []int{12}
+	return fileDescriptor_e6af9fd695f521f0, []int{15}
 }
 
 func (m *VectorNNParamsList) XXX_Unmarshal(b []byte) error {
@@ -604,9 +834,20 @@ func (m *VectorNNParamsList) GetParams() []*VectorNNParams {
 	return nil
 }
 
+func (m *VectorNNParamsList) GetLanguage() string {
+	if m != nil {
+		return m.Language
+	}
+	return ""
+}
+
 type Corpi struct {
 	Corpi                []string    `protobuf:"bytes,1,rep,name=corpi,proto3" json:"corpi,omitempty"`
 	Overrides            []*Override `protobuf:"bytes,2,rep,name=overrides,proto3" json:"overrides,omitempty"`
+	Language             string      `protobuf:"bytes,3,opt,name=language,proto3" json:"language,omitempty"`
+	Tokenizer            string      `protobuf:"bytes,4,opt,name=tokenizer,proto3" json:"tokenizer,omitempty"`
+	UseSuggestions       bool        `protobuf:"varint,5,opt,name=useSuggestions,proto3" json:"useSuggestions,omitempty"`
+	Weights              []float32   `protobuf:"fixed32,6,rep,packed,name=weights,proto3" json:"weights,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
 	XXX_unrecognized     []byte      `json:"-"`
 	XXX_sizecache        int32       `json:"-"`
@@ -616,7 +857,7 @@ func (m *Corpi) Reset()         { *m = Corpi{} }
 func (m *Corpi) String() string { return proto.CompactTextString(m) }
 func (*Corpi) ProtoMessage()    {}
 func (*Corpi) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e6af9fd695f521f0, []int{13}
+	return fileDescriptor_e6af9fd695f521f0, []int{16}
 }
 
 func (m *Corpi) XXX_Unmarshal(b []byte) error {
@@ -651,6 +892,191 @@ func (m *Corpi) GetOverrides() []*Override {
 	return nil
 }
 
+func (m *Corpi) GetLanguage() string {
+	if m != nil {
+		return m.Language
+	}
+	return ""
+}
+
+func (m *Corpi) GetTokenizer() string {
+	if m != nil {
+		return m.Tokenizer
+	}
+	return ""
+}
+
+func (m *Corpi) GetUseSuggestions() bool {
+	if m != nil {
+		return m.UseSuggestions
+	}
+	return false
+}
+
+func (m *Corpi) GetWeights() []float32 {
+	if m != nil {
+		return m.Weights
+	}
+	return nil
+}
+
+type VectorizeRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Corpi                *Corpi   `protobuf:"bytes,2,opt,name=corpi,proto3" json:"corpi,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VectorizeRequest) Reset()         { *m = VectorizeRequest{} }
+func (m *VectorizeRequest) String() string { return proto.CompactTextString(m) }
+func (*VectorizeRequest) ProtoMessage()    {}
+func (*VectorizeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6af9fd695f521f0, []int{17}
+}
+
+func (m *VectorizeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VectorizeRequest.Unmarshal(m, b)
+}
+func (m *VectorizeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VectorizeRequest.Marshal(b, m, deterministic)
+}
+func (m *VectorizeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VectorizeRequest.Merge(m, src)
+}
+func (m *VectorizeRequest) XXX_Size() int {
+	return xxx_messageInfo_VectorizeRequest.Size(m)
+}
+func (m *VectorizeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_VectorizeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VectorizeRequest proto.InternalMessageInfo
+
+func (m *VectorizeRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *VectorizeRequest) GetCorpi() *Corpi {
+	if m != nil {
+		return m.Corpi
+	}
+	return nil
+}
+
+type VectorizeResponse struct {
+	Id                   string     `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Vector               *Vector    `protobuf:"bytes,2,opt,name=vector,proto3" json:"vector,omitempty"`
+	Error                *ItemError `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
+}
+
+func (m *VectorizeResponse) Reset()         { *m = VectorizeResponse{} }
+func (m *VectorizeResponse) String() string { return proto.CompactTextString(m) }
+func (*VectorizeResponse) ProtoMessage()    {}
+func (*VectorizeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6af9fd695f521f0, []int{18}
+}
+
+func (m *VectorizeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VectorizeResponse.Unmarshal(m, b)
+}
+func (m *VectorizeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VectorizeResponse.Marshal(b, m, deterministic)
+}
+func (m *VectorizeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VectorizeResponse.Merge(m, src)
+}
+func (m *VectorizeResponse) XXX_Size() int {
+	return xxx_messageInfo_VectorizeResponse.Size(m)
+}
+func (m *VectorizeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_VectorizeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VectorizeResponse proto.InternalMessageInfo
+
+func (m *VectorizeResponse) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *VectorizeResponse) GetVector() *Vector {
+	if m != nil {
+		return m.Vector
+	}
+	return nil
+}
+
+func (m *VectorizeResponse) GetError() *ItemError {
+	if m != nil {
+		return m.Error
+	}
+	return nil
+}
+
+type ItemError struct {
+	Code                 uint32   `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
+	Message              string   `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Index                uint32   `protobuf:"varint,3,opt,name=index,proto3" json:"index,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ItemError) Reset()         { *m = ItemError{} }
+func (m *ItemError) String() string { return proto.CompactTextString(m) }
+func (*ItemError) ProtoMessage()    {}
+func (*ItemError) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e6af9fd695f521f0, []int{19}
+}
+
+func (m *ItemError) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ItemError.Unmarshal(m, b)
+}
+func (m *ItemError) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ItemError.Marshal(b, m, deterministic)
+}
+func (m *ItemError) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ItemError.Merge(m, src)
+}
+func (m *ItemError) XXX_Size() int {
+	return xxx_messageInfo_ItemError.Size(m)
+}
+func (m *ItemError) XXX_DiscardUnknown() {
+	xxx_messageInfo_ItemError.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ItemError proto.InternalMessageInfo
+
+func (m *ItemError) GetCode() uint32 {
+	if m != nil {
+		return m.Code
+	}
+	return 0
+}
+
+func (m *ItemError) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *ItemError) GetIndex() uint32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
 type Override struct {
 	Word                 string   `protobuf:"bytes,1,opt,name=word,proto3" json:"word,omitempty"`
 	Expression           string   `protobuf:"bytes,2,opt,name=expression,proto3" json:"expression,omitempty"`
@@ -663,7 +1089,7 @@ func (m *Override) Reset()         { *m = Override{} }
 func (m *Override) String() string { return proto.CompactTextString(m) }
 func (*Override) ProtoMessage()    {}
 func (*Override) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e6af9fd695f521f0, []int{14}
+	return fileDescriptor_e6af9fd695f521f0, []int{20}
 }
 
 func (m *Override) XXX_Unmarshal(b []byte) error {
@@ -709,7 +1135,7 @@ func (m *WordStopword) Reset()         { *m = WordStopword{} }
 func (m *WordStopword) String() string { return proto.CompactTextString(m) }
 func (*WordStopword) ProtoMessage()    {}
 func (*WordStopword) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e6af9fd695f521f0, []int{15}
+	return fileDescriptor_e6af9fd695f521f0, []int{21}
 }
 
 func (m *WordStopword) XXX_Unmarshal(b []byte) error {
@@ -740,6 +1166,7 @@ func (m *WordStopword) GetStopword() bool {
 type SimilarWordsParams struct {
 	Word                 string   `protobuf:"bytes,1,opt,name=word,proto3" json:"word,omitempty"`
 	Certainty            float32  `protobuf:"fixed32,2,opt,name=certainty,proto3" json:"certainty,omitempty"`
+	Language             string   `protobuf:"bytes,3,opt,name=language,proto3" json:"language,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -749,7 +1176,7 @@ func (m *SimilarWordsParams) Reset()         { *m = SimilarWordsParams{} }
 func (m *SimilarWordsParams) String() string { return proto.CompactTextString(m) }
 func (*SimilarWordsParams) ProtoMessage()    {}
 func (*SimilarWordsParams) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e6af9fd695f521f0, []int{16}
+	return fileDescriptor_e6af9fd695f521f0, []int{22}
 }
 
 func (m *SimilarWordsParams) XXX_Unmarshal(b []byte) error {
@@ -784,6 +1211,13 @@ func (m *SimilarWordsParams) GetCertainty() float32 {
 	return 0
 }
 
+func (m *SimilarWordsParams) GetLanguage() string {
+	if m != nil {
+		return m.Language
+	}
+	return ""
+}
+
 type SimilarWordsResults struct {
 	Words                []*Word  `protobuf:"bytes,1,rep,name=words,proto3" json:"words,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -795,7 +1229,7 @@ func (m *SimilarWordsResults) Reset()         { *m = SimilarWordsResults{} }
 func (m *SimilarWordsResults) String() string { return proto.CompactTextString(m) }
 func (*SimilarWordsResults) ProtoMessage()    {}
 func (*SimilarWordsResults) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e6af9fd695f521f0, []int{17}
+	return fileDescriptor_e6af9fd695f521f0, []int{23}
 }
 
 func (m *SimilarWordsResults) XXX_Unmarshal(b []byte) error {
@@ -836,7 +1270,7 @@ func (m *NearestWords) Reset()         { *m = NearestWords{} }
 func (m *NearestWords) String() string { return proto.CompactTextString(m) }
 func (*NearestWords) ProtoMessage()    {}
 func (*NearestWords) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e6af9fd695f521f0, []int{18}
+	return fileDescriptor_e6af9fd695f521f0, []int{24}
 }
 
 func (m *NearestWords) XXX_Unmarshal(b []byte) error {
@@ -880,6 +1314,7 @@ func (m *NearestWords) GetVectors() *VectorList {
 
 type NearestWordsList struct {
 	Words                []*NearestWords `protobuf:"bytes,1,rep,name=words,proto3" json:"words,omitempty"`
+	Errors               []*ItemError    `protobuf:"bytes,2,rep,name=errors,proto3" json:"errors,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
 	XXX_unrecognized     []byte          `json:"-"`
 	XXX_sizecache        int32           `json:"-"`
@@ -889,7 +1324,7 @@ func (m *NearestWordsList) Reset()         { *m = NearestWordsList{} }
 func (m *NearestWordsList) String() string { return proto.CompactTextString(m) }
 func (*NearestWordsList) ProtoMessage()    {}
 func (*NearestWordsList) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e6af9fd695f521f0, []int{19}
+	return fileDescriptor_e6af9fd695f521f0, []int{25}
 }
 
 func (m *NearestWordsList) XXX_Unmarshal(b []byte) error {
@@ -917,6 +1352,13 @@ func (m *NearestWordsList) GetWords() []*NearestWords {
 	return nil
 }
 
+func (m *NearestWordsList) GetErrors() []*ItemError {
+	if m != nil {
+		return m.Errors
+	}
+	return nil
+}
+
 type Keyword struct {
 	Keyword              string   `protobuf:"bytes,1,opt,name=keyword,proto3" json:"keyword,omitempty"`
 	Weight               float32  `protobuf:"fixed32,2,opt,name=weight,proto3" json:"weight,omitempty"`
@@ -929,7 +1371,7 @@ func (m *Keyword) Reset()         { *m = Keyword{} }
 func (m *Keyword) String() string { return proto.CompactTextString(m) }
 func (*Keyword) ProtoMessage()    {}
 func (*Keyword) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e6af9fd695f521f0, []int{20}
+	return fileDescriptor_e6af9fd695f521f0, []int{26}
 }
 
 func (m *Keyword) XXX_Unmarshal(b []byte) error {
@@ -969,6 +1411,7 @@ type SchemaSearchParams struct {
 	Name                 string     `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
 	Keywords             []*Keyword `protobuf:"bytes,3,rep,name=keywords,proto3" json:"keywords,omitempty"`
 	Certainty            float32    `protobuf:"fixed32,5,opt,name=certainty,proto3" json:"certainty,omitempty"`
+	Language             string     `protobuf:"bytes,6,opt,name=language,proto3" json:"language,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
 	XXX_unrecognized     []byte     `json:"-"`
 	XXX_sizecache        int32      `json:"-"`
@@ -978,7 +1421,7 @@ func (m *SchemaSearchParams) Reset()         { *m = SchemaSearchParams{} }
 func (m *SchemaSearchParams) String() string { return proto.CompactTextString(m) }
 func (*SchemaSearchParams) ProtoMessage()    {}
 func (*SchemaSearchParams) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e6af9fd695f521f0, []int{21}
+	return fileDescriptor_e6af9fd695f521f0, []int{27}
 }
 
 func (m *SchemaSearchParams) XXX_Unmarshal(b []byte) error {
@@ -1027,6 +1470,13 @@ func (m *SchemaSearchParams) GetCertainty() float32 {
 	return 0
 }
 
+func (m *SchemaSearchParams) GetLanguage() string {
+	if m != nil {
+		return m.Language
+	}
+	return ""
+}
+
 type SchemaSearchResults struct {
 	Type                 SearchType            `protobuf:"varint,1,opt,name=type,proto3,enum=contextionary.SearchType" json:"type,omitempty"`
 	Results              []*SchemaSearchResult `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`
@@ -1039,7 +1489,7 @@ func (m *SchemaSearchResults) Reset()         { *m = SchemaSearchResults{} }
 func (m *SchemaSearchResults) String() string { return proto.CompactTextString(m) }
 func (*SchemaSearchResults) ProtoMessage()    {}
 func (*SchemaSearchResults) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e6af9fd695f521f0, []int{22}
+	return fileDescriptor_e6af9fd695f521f0, []int{28}
 }
 
 func (m *SchemaSearchResults) XXX_Unmarshal(b []byte) error {
@@ -1086,7 +1536,7 @@ func (m *SchemaSearchResult) Reset()         { *m = SchemaSearchResult{} }
 func (m *SchemaSearchResult) String() string { return proto.CompactTextString(m) }
 func (*SchemaSearchResult) ProtoMessage()    {}
 func (*SchemaSearchResult) Descriptor() ([]byte, []int) {
-	return fileDescriptor_e6af9fd695f521f0, []int{23}
+	return fileDescriptor_e6af9fd695f521f0, []int{29}
 }
 
 func (m *SchemaSearchResult) XXX_Unmarshal(b []byte) error {
@@ -1130,6 +1580,9 @@ func init() {
 	proto.RegisterType((*Word)(nil), "contextionary.Word")
 	proto.RegisterType((*WordList)(nil), "contextionary.WordList")
 	proto.RegisterType((*WordPresent)(nil), "contextionary.WordPresent")
+	proto.RegisterType((*SuggestWordsParams)(nil), "contextionary.SuggestWordsParams")
+	proto.RegisterType((*WordSuggestions)(nil), "contextionary.WordSuggestions")
+	proto.RegisterType((*WordSuggestion)(nil), "contextionary.WordSuggestion")
 	proto.RegisterType((*Vector)(nil), "contextionary.Vector")
 	proto.RegisterType((*InputElement)(nil), "contextionary.InputElement")
 	proto.RegisterType((*VectorList)(nil), "contextionary.VectorList")
@@ -1137,6 +1590,9 @@ func init() {
 	proto.RegisterType((*VectorNNParams)(nil), "contextionary.VectorNNParams")
 	proto.RegisterType((*VectorNNParamsList)(nil), "contextionary.VectorNNParamsList")
 	proto.RegisterType((*Corpi)(nil), "contextionary.Corpi")
+	proto.RegisterType((*VectorizeRequest)(nil), "contextionary.VectorizeRequest")
+	proto.RegisterType((*VectorizeResponse)(nil), "contextionary.VectorizeResponse")
+	proto.RegisterType((*ItemError)(nil), "contextionary.ItemError")
 	proto.RegisterType((*Override)(nil), "contextionary.Override")
 	proto.RegisterType((*WordStopword)(nil), "contextionary.WordStopword")
 	proto.RegisterType((*SimilarWordsParams)(nil), "contextionary.SimilarWordsParams")
@@ -1152,69 +1608,92 @@ func init() {
 func init() { proto.RegisterFile("contextionary.proto", fileDescriptor_e6af9fd695f521f0) }
 
 var fileDescriptor_e6af9fd695f521f0 = []byte{
-	// 980 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x56, 0xdd, 0x6f, 0x1c, 0x35,
-	0x10, 0xdf, 0xbd, 0xef, 0x9b, 0x5c, 0xa2, 0x68, 0x12, 0xda, 0xeb, 0xb5, 0x40, 0x6a, 0x84, 0x08,
-	0x95, 0x5a, 0x44, 0x42, 0x1e, 0x50, 0x55, 0x68, 0x7b, 0x5c, 0x50, 0x69, 0x9b, 0x44, 0xbe, 0x88,
-	0x08, 0xf1, 0xc2, 0x76, 0xcf, 0x21, 0xab, 0xe4, 0xbc, 0x27, 0xdb, 0x97, 0xe4, 0x1e, 0xf9, 0xa3,
-	0xf8, 0xe7, 0x78, 0x42, 0xf6, 0x7a, 0x6f, 0xbd, 0x1f, 0x39, 0xe8, 0x9b, 0x67, 0xfc, 0x9b, 0xf1,
-	0xfc, 0xe6, 0xc3, 0x36, 0x6c, 0x85, 0x31, 0x57, 0xec, 0x56, 0x45, 0x31, 0x0f, 0xc4, 0xe2, 0xd9,
-	0x4c, 0xc4, 0x2a, 0xc6, 0xf5, 0x9c, 0x92, 0x7c, 0x80, 0x8d, 0xd1, 0xad, 0x62, 0x5c, 0x46, 0x31,
-	0x7f, 0xc3, 0x67, 0x73, 0x85, 0x7d, 0x68, 0x87, 0x31, 0x0f, 0xd9, 0x4c, 0xf5, 0xfd, 0x1d, 0x7f,
-	0xb7, 0x4b, 0x53, 0x11, 0x3f, 0x03, 0x98, 0xb0, 0xf3, 0x88, 0x47, 0xda, 0xb8, 0x5f, 0x33, 0x9b,
-	0x8e, 0x06, 0xef, 0x41, 0xeb, 0x86, 0x45, 0x7f, 0x5e, 0xa8, 0x7e, 0x7d, 0xc7, 0xdf, 0xad, 0x51,
-	0x2b, 0x91, 0x6d, 0xc0, 0x57, 0x93, 0xc9, 0xf2, 0x18, 0xca, 0xe4, 0xfc, 0x4a, 0x91, 0x1e, 0xc0,
-	0x7b, 0xa6, 0x82, 0x93, 0x40, 0x04, 0x53, 0x49, 0x0e, 0xa1, 0xa7, 0xa5, 0xe3, 0x6b, 0x26, 0xae,
-	0x23, 0x76, 0xa3, 0xa3, 0xb8, 0x66, 0x42, 0xc3, 0xd3, 0x28, 0xac, 0x88, 0x8f, 0xa0, 0x7b, 0x13,
-	0x8b, 0xc9, 0x30, 0x9e, 0x73, 0x65, 0x82, 0xa8, 0xd3, 0x4c, 0x41, 0x06, 0xd0, 0x38, 0x8b, 0xc5,
-	0x04, 0x11, 0x1a, 0x5a, 0x69, 0x8d, 0xcd, 0x9a, 0x1c, 0x40, 0x47, 0xef, 0xbd, 0x8b, 0xa4, 0xc2,
-	0xaf, 0xa1, 0xa9, 0x75, 0xb2, 0xef, 0xef, 0xd4, 0x77, 0xd7, 0xf6, 0xb6, 0x9e, 0xe5, 0x73, 0xa5,
-	0x71, 0x34, 0x41, 0x90, 0xaf, 0x60, 0x4d, 0x8b, 0x27, 0x82, 0x49, 0xc6, 0x4d, 0x7e, 0x66, 0xc9,
-	0xd2, 0x38, 0xef, 0xd0, 0x54, 0x24, 0x12, 0x5a, 0xbf, 0xb2, 0x50, 0xc5, 0x02, 0xbf, 0x83, 0x36,
-	0xe3, 0x4a, 0x44, 0x2c, 0xf5, 0x3f, 0x28, 0xf8, 0x4f, 0x70, 0x23, 0xae, 0xc4, 0x82, 0xa6, 0x50,
-	0xdc, 0x87, 0x96, 0x8c, 0xe7, 0x22, 0x64, 0xfd, 0x9a, 0x31, 0x7a, 0x58, 0x30, 0x32, 0xf5, 0x19,
-	0x5d, 0xb1, 0x29, 0xe3, 0x8a, 0x5a, 0x28, 0xf9, 0x03, 0x7a, 0xae, 0x7e, 0x45, 0xf9, 0xb2, 0xf2,
-	0xd4, 0xdc, 0xf2, 0xe8, 0xb2, 0xc6, 0x61, 0x38, 0x17, 0x82, 0xf1, 0x90, 0x99, 0xd2, 0x35, 0xa8,
-	0xa3, 0x21, 0x2f, 0x00, 0x92, 0x70, 0x4d, 0xe2, 0xbe, 0xd1, 0x85, 0xd1, 0x52, 0x4a, 0xed, 0x93,
-	0x4a, 0x6a, 0x34, 0x45, 0x91, 0x2f, 0x60, 0xcd, 0x61, 0x8b, 0xdb, 0xd0, 0x34, 0x0b, 0x13, 0x5d,
-	0x8d, 0x26, 0x02, 0xf9, 0x1d, 0x36, 0x12, 0xd0, 0xd1, 0x51, 0xd2, 0x10, 0xf8, 0x14, 0x5a, 0x89,
-	0x07, 0x03, 0xbc, 0xf3, 0x18, 0x0b, 0xc2, 0x1e, 0xf8, 0x97, 0x86, 0x57, 0x93, 0xfa, 0x97, 0x5a,
-	0xe2, 0x86, 0x49, 0x93, 0xfa, 0x9c, 0xbc, 0x05, 0xcc, 0x3b, 0x37, 0x44, 0x0e, 0xa0, 0x95, 0x48,
-	0x96, 0xc7, 0xa7, 0x95, 0x07, 0xa4, 0x26, 0xd4, 0x82, 0xc9, 0x29, 0x34, 0x87, 0xb1, 0x98, 0x45,
-	0x9a, 0x48, 0xa8, 0x17, 0xc6, 0xbc, 0x4b, 0x13, 0x01, 0x0f, 0xa0, 0x1b, 0x5f, 0x33, 0x21, 0xa2,
-	0x09, 0x93, 0xb6, 0x8c, 0xf7, 0x0b, 0x8e, 0x8f, 0xed, 0x3e, 0xcd, 0x90, 0xe4, 0x07, 0xe8, 0xa4,
-	0xea, 0xaa, 0xd6, 0xd5, 0x35, 0x62, 0xb7, 0xba, 0xcf, 0xa4, 0x33, 0x7a, 0x99, 0x86, 0x3c, 0x81,
-	0x9e, 0xee, 0xd1, 0xb1, 0x8a, 0x67, 0x06, 0x3f, 0x80, 0x8e, 0xb4, 0x6b, 0xdb, 0xa5, 0x4b, 0x99,
-	0x1c, 0x02, 0x8e, 0xa3, 0x69, 0x74, 0x15, 0x08, 0x6d, 0x22, 0x6d, 0xbe, 0xab, 0x4e, 0x7d, 0x04,
-	0xdd, 0x90, 0x09, 0x15, 0x44, 0x5c, 0x2d, 0x6c, 0xd3, 0x64, 0x0a, 0xf2, 0x12, 0xb6, 0x5c, 0x3f,
-	0xc9, 0x58, 0xcb, 0x8f, 0x99, 0xac, 0x1b, 0xe8, 0x1d, 0xb1, 0x40, 0x30, 0xa9, 0x8c, 0x07, 0x9d,
-	0xd2, 0xcc, 0xb4, 0x6b, 0x51, 0x3a, 0x8a, 0x49, 0x24, 0x55, 0xc0, 0x43, 0x9b, 0xd2, 0x1a, 0xcd,
-	0x14, 0xb8, 0x9f, 0xf5, 0x63, 0xdd, 0x34, 0xca, 0x83, 0xca, 0x3a, 0xea, 0x92, 0x67, 0x3d, 0x39,
-	0x82, 0x4d, 0xf7, 0x60, 0xd3, 0x0f, 0xdf, 0xe6, 0xe3, 0x2e, 0x0e, 0x9f, 0x8b, 0x4f, 0xe3, 0x7f,
-	0x0e, 0xed, 0xb7, 0x6c, 0x61, 0x52, 0xd5, 0x87, 0xf6, 0x65, 0xb2, 0x4c, 0xc7, 0xce, 0x8a, 0x77,
-	0x8d, 0x1d, 0xf9, 0xdb, 0x07, 0x1c, 0x87, 0x17, 0x6c, 0x1a, 0x8c, 0x59, 0x20, 0xc2, 0x0b, 0x5b,
-	0x87, 0xef, 0x01, 0xa4, 0x91, 0x4f, 0x17, 0x33, 0x66, 0x7c, 0x6d, 0x94, 0x28, 0x8d, 0x97, 0x00,
-	0xea, 0x80, 0x75, 0x09, 0x79, 0x30, 0x65, 0xb6, 0x3d, 0xcc, 0x1a, 0xf7, 0xa0, 0x63, 0x03, 0xd1,
-	0xf9, 0xd1, 0xc4, 0xee, 0x15, 0x9c, 0x59, 0x06, 0x74, 0x89, 0xcb, 0x97, 0xbd, 0x59, 0x2c, 0xfb,
-	0x5f, 0x3e, 0x6c, 0xb9, 0x71, 0xa7, 0x75, 0x7f, 0x0a, 0x0d, 0xf5, 0xbf, 0x42, 0x36, 0x30, 0x7c,
-	0x0e, 0x6d, 0x91, 0x58, 0xda, 0x31, 0x79, 0x5c, 0xb4, 0x28, 0x9d, 0x41, 0x53, 0x0b, 0xd3, 0xc2,
-	0xa5, 0xed, 0x25, 0x7f, 0xdf, 0xe1, 0x9f, 0xe3, 0x52, 0x2f, 0x70, 0x79, 0xf2, 0x25, 0x40, 0x16,
-	0x18, 0x76, 0xa1, 0x39, 0x7c, 0xf7, 0x6a, 0x3c, 0xde, 0xf4, 0xb0, 0x07, 0x9d, 0x13, 0x7a, 0x7c,
-	0x32, 0xa2, 0xa7, 0xbf, 0x6d, 0xfa, 0x7b, 0xff, 0xb4, 0x60, 0x7d, 0xe8, 0x06, 0x87, 0x3f, 0xc1,
-	0xc6, 0x1b, 0x99, 0x9b, 0xb8, 0xaa, 0x3e, 0x1f, 0x3c, 0xac, 0x50, 0xa6, 0x16, 0xc4, 0xc3, 0xd7,
-	0xb0, 0x9e, 0x78, 0x49, 0xdf, 0x96, 0x4a, 0x27, 0x83, 0x0a, 0xa5, 0x35, 0x20, 0x1e, 0x9e, 0x41,
-	0xcf, 0x4d, 0x05, 0xae, 0x4a, 0x63, 0xd2, 0x62, 0x03, 0xf2, 0x9f, 0x99, 0x96, 0xc4, 0xc3, 0x4b,
-	0xd8, 0x19, 0x07, 0xe7, 0xec, 0x67, 0xa6, 0xdc, 0x29, 0x3f, 0x8b, 0xd4, 0xc5, 0x30, 0xcd, 0x5f,
-	0xf9, 0xb0, 0xd2, 0xbd, 0x52, 0x3e, 0xac, 0x7c, 0x65, 0x10, 0x0f, 0x5f, 0xc0, 0x7a, 0x32, 0xa7,
-	0x87, 0xb1, 0xd9, 0xaa, 0xce, 0x44, 0xf5, 0x1b, 0x40, 0x3c, 0xfc, 0x05, 0xf0, 0xfd, 0xfc, 0x4a,
-	0x45, 0x79, 0x1f, 0xf7, 0x2b, 0x7c, 0xe8, 0x51, 0x1f, 0xdc, 0x7d, 0x45, 0x10, 0x0f, 0x7f, 0x4c,
-	0x9f, 0xa2, 0xc3, 0x58, 0xd8, 0x9b, 0xbe, 0x00, 0x37, 0xda, 0xbb, 0x83, 0x39, 0x85, 0x6d, 0xf7,
-	0xb2, 0x78, 0xbd, 0xb0, 0x9f, 0x82, 0xd5, 0x0f, 0xcc, 0x60, 0xd5, 0x85, 0x43, 0x3c, 0x0c, 0xe0,
-	0x81, 0xa1, 0x58, 0xe9, 0xfa, 0xf1, 0x4a, 0xd7, 0x86, 0xf3, 0xe7, 0x2b, 0xdc, 0x5b, 0xe6, 0x2f,
-	0xa1, 0xa1, 0xff, 0x60, 0x58, 0x4c, 0x4f, 0xf6, 0x4d, 0x2b, 0x05, 0xe9, 0xfe, 0xd9, 0x88, 0x87,
-	0x14, 0x7a, 0xee, 0x4f, 0xaf, 0x44, 0x39, 0xff, 0xd5, 0x1c, 0x14, 0xc3, 0xae, 0xf8, 0x25, 0x7a,
-	0x1f, 0x5a, 0xe6, 0xdf, 0xba, 0xff, 0x6f, 0x00, 0x00, 0x00, 0xff, 0xff, 0x3f, 0x9f, 0x4b, 0x76,
-	0xce, 0x0a, 0x00, 0x00,
+	// 1345 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x57, 0xeb, 0x72, 0x1b, 0x35,
+	0x14, 0xf6, 0x3a, 0xb1, 0x63, 0x9f, 0x38, 0x6e, 0x50, 0x42, 0xeb, 0xba, 0xa5, 0x75, 0xc5, 0x00,
+	0xa1, 0x33, 0x2d, 0x25, 0xa5, 0xcc, 0x30, 0x1d, 0xe8, 0x25, 0x4d, 0x99, 0x42, 0x9b, 0x04, 0xb9,
+	0x43, 0x07, 0x7e, 0xb1, 0xdd, 0x55, 0x13, 0x4d, 0xe2, 0x5d, 0x23, 0xc9, 0x89, 0xdd, 0x1f, 0xcc,
+	0xc0, 0x23, 0xf0, 0x1a, 0xbc, 0x0c, 0xbc, 0x11, 0xa3, 0xdb, 0xde, 0xbc, 0xde, 0xb4, 0xff, 0xf6,
+	0x48, 0xdf, 0xb9, 0x7f, 0x3a, 0x5a, 0xc1, 0x46, 0x10, 0x47, 0x92, 0x4e, 0x25, 0x8b, 0x23, 0x9f,
+	0xcf, 0x6e, 0x8f, 0x79, 0x2c, 0x63, 0xb4, 0x96, 0x5b, 0xc4, 0x7f, 0x40, 0x77, 0x77, 0x2a, 0x69,
+	0x24, 0x58, 0x1c, 0x3d, 0x8b, 0xc6, 0x13, 0x89, 0x7a, 0xb0, 0x12, 0xc4, 0x51, 0x40, 0xc7, 0xb2,
+	0xe7, 0x0d, 0xbc, 0xad, 0x36, 0x71, 0x22, 0xba, 0x06, 0x10, 0xd2, 0x37, 0x2c, 0x62, 0x4a, 0xb9,
+	0x57, 0xd7, 0x9b, 0x99, 0x15, 0x74, 0x11, 0x9a, 0x67, 0x94, 0x1d, 0x1e, 0xc9, 0xde, 0xd2, 0xc0,
+	0xdb, 0xaa, 0x13, 0x2b, 0xa1, 0x3e, 0xb4, 0x4e, 0xfc, 0xe8, 0x70, 0xe2, 0x1f, 0xd2, 0xde, 0xb2,
+	0xd6, 0x4a, 0x64, 0xbc, 0x09, 0xe8, 0x51, 0x18, 0x26, 0x21, 0x10, 0x2a, 0x26, 0x27, 0x12, 0x6f,
+	0x01, 0xbc, 0xa0, 0xd2, 0x3f, 0xf0, 0xb9, 0x3f, 0x12, 0x39, 0x7d, 0xaf, 0xa0, 0xff, 0xb7, 0x07,
+	0x1d, 0x05, 0xdd, 0x3f, 0xa5, 0xfc, 0x94, 0xd1, 0x33, 0x15, 0xfe, 0x29, 0xe5, 0xca, 0x96, 0x0b,
+	0xdf, 0x8a, 0xe8, 0x2a, 0xb4, 0xcf, 0x62, 0x1e, 0xee, 0xc4, 0x93, 0x48, 0xea, 0xe8, 0x97, 0x48,
+	0xba, 0xa0, 0x82, 0x1f, 0x51, 0xc9, 0x59, 0xa0, 0x83, 0x6f, 0x13, 0x2b, 0xa1, 0x6d, 0xd8, 0x0c,
+	0x28, 0x97, 0x3e, 0x8b, 0xe4, 0xec, 0x85, 0x3f, 0x7d, 0xc2, 0x84, 0xf4, 0xa3, 0xc0, 0x24, 0x52,
+	0x27, 0xa5, 0x7b, 0xf8, 0x6b, 0x58, 0x7e, 0x15, 0xf3, 0x10, 0x21, 0x58, 0x56, 0x0e, 0x6c, 0x20,
+	0xfa, 0x3b, 0x97, 0x4c, 0xbd, 0x90, 0xcc, 0x4f, 0xd0, 0x52, 0x7a, 0xcf, 0x99, 0x90, 0xe8, 0x73,
+	0x68, 0x28, 0xbc, 0xe8, 0x79, 0x83, 0xa5, 0xad, 0xd5, 0xed, 0x8d, 0xdb, 0xf9, 0x66, 0x2a, 0x1c,
+	0x31, 0x88, 0x4a, 0x93, 0x9f, 0xc1, 0xaa, 0x82, 0x1e, 0x70, 0x2a, 0x68, 0xa4, 0x9b, 0x3b, 0x36,
+	0x9f, 0x3a, 0xa8, 0x16, 0x71, 0x22, 0x3e, 0x05, 0x34, 0x9c, 0x1c, 0x1e, 0x52, 0x21, 0x15, 0x5e,
+	0xd8, 0xd2, 0x2f, 0xc8, 0x60, 0xe4, 0x4f, 0x77, 0x43, 0x26, 0x85, 0x76, 0xd7, 0x20, 0x89, 0x8c,
+	0x36, 0xa1, 0x71, 0xc2, 0x46, 0xcc, 0x30, 0xa0, 0x41, 0x8c, 0x50, 0x49, 0x00, 0x02, 0x17, 0x94,
+	0x43, 0xeb, 0x9b, 0xc5, 0x91, 0x40, 0x0f, 0x60, 0x55, 0xa4, 0xa2, 0x2d, 0xc0, 0x47, 0x25, 0x05,
+	0x48, 0x95, 0x48, 0x56, 0x03, 0xff, 0x06, 0xdd, 0xfc, 0xf6, 0xa2, 0x3c, 0x42, 0xd7, 0x4d, 0x9b,
+	0x87, 0x93, 0x15, 0xd5, 0xe3, 0x20, 0x98, 0x70, 0x4e, 0xd5, 0xae, 0x4a, 0x66, 0x99, 0x64, 0x56,
+	0xb0, 0x80, 0xe6, 0xcf, 0x34, 0x90, 0x31, 0x47, 0x5f, 0xc1, 0x0a, 0x8d, 0x24, 0x67, 0xd4, 0x05,
+	0xda, 0x2f, 0x04, 0x6a, 0x70, 0xbb, 0x91, 0xe4, 0x33, 0xe2, 0xa0, 0xe8, 0x2e, 0x34, 0x45, 0x3c,
+	0xe1, 0xda, 0xb3, 0x52, 0xba, 0x52, 0x50, 0xd2, 0x47, 0x71, 0xf7, 0x84, 0x8e, 0x68, 0x24, 0x89,
+	0x85, 0xe2, 0xbf, 0x3c, 0xe8, 0x64, 0x37, 0x2a, 0x8e, 0x6a, 0x7a, 0x14, 0xeb, 0xb9, 0xa3, 0x78,
+	0x4e, 0x5e, 0x68, 0x00, 0xab, 0x62, 0x16, 0xc9, 0x23, 0x2a, 0xd8, 0x5b, 0x1a, 0xea, 0x66, 0xb5,
+	0x48, 0x76, 0x09, 0xc7, 0x00, 0x26, 0x23, 0xcd, 0xd2, 0x2f, 0xd4, 0x69, 0x53, 0x92, 0xcb, 0xfe,
+	0xc3, 0xd2, 0xec, 0x89, 0x43, 0xa1, 0x3b, 0xd0, 0xa4, 0x9c, 0x2b, 0xbc, 0x49, 0xbc, 0x57, 0x4c,
+	0x5c, 0xd2, 0xd1, 0xae, 0x02, 0x10, 0x8b, 0xc3, 0x1f, 0xc3, 0x6a, 0xa6, 0x84, 0x8a, 0x61, 0xfa,
+	0x43, 0x67, 0x5c, 0x27, 0x46, 0xc0, 0x67, 0xd0, 0x35, 0xa0, 0xbd, 0x3d, 0xcb, 0xdc, 0x5b, 0xd0,
+	0x34, 0x3e, 0x35, 0x70, 0x61, 0x60, 0x16, 0x84, 0x3a, 0xe0, 0x1d, 0x5b, 0x16, 0x78, 0xc7, 0x4a,
+	0x8a, 0x2c, 0x85, 0xbd, 0xa8, 0x92, 0xbe, 0x87, 0x80, 0xf2, 0x8e, 0x75, 0x59, 0xee, 0x41, 0xd3,
+	0x48, 0x0b, 0xc8, 0x9b, 0x57, 0x21, 0xcd, 0x92, 0x41, 0x57, 0x3c, 0xc8, 0xff, 0x7a, 0xd0, 0xd8,
+	0x89, 0xf9, 0x98, 0xa9, 0x0a, 0x04, 0xea, 0x43, 0xdb, 0x6e, 0x13, 0x23, 0xa0, 0x7b, 0xd0, 0x8e,
+	0x4f, 0x29, 0xe7, 0x2c, 0xa4, 0xae, 0xb6, 0x97, 0x0a, 0x5e, 0xf7, 0xed, 0x3e, 0x49, 0x91, 0x39,
+	0x97, 0x4b, 0x79, 0x97, 0x6a, 0x60, 0xca, 0xf8, 0x98, 0x46, 0xec, 0x2d, 0xe5, 0x36, 0xf1, 0x74,
+	0x01, 0x7d, 0x0a, 0xdd, 0x89, 0xa0, 0x99, 0x73, 0xdb, 0x6b, 0x68, 0xb6, 0x14, 0x56, 0x15, 0x49,
+	0x0d, 0xf9, 0x44, 0xaf, 0x39, 0x58, 0xda, 0xaa, 0x13, 0x27, 0xe2, 0x3d, 0x58, 0x37, 0x85, 0x60,
+	0x6f, 0x29, 0xa1, 0xbf, 0x4f, 0xa8, 0x90, 0xa8, 0x0b, 0x75, 0xe6, 0x8e, 0x69, 0x9d, 0x85, 0xe8,
+	0xa6, 0x4b, 0xb6, 0xae, 0xbb, 0xb8, 0x59, 0x48, 0x49, 0x57, 0xc4, 0x96, 0x40, 0x9d, 0x8f, 0x0f,
+	0x32, 0x06, 0xc5, 0x38, 0x8e, 0x04, 0x9d, 0xb3, 0x98, 0x12, 0xa3, 0xfe, 0x2e, 0xc4, 0xb8, 0x0d,
+	0x0d, 0x4d, 0x44, 0x5d, 0x9d, 0x2a, 0xbe, 0x1a, 0x18, 0xde, 0x87, 0x76, 0xb2, 0xa6, 0xc6, 0x4e,
+	0x10, 0x87, 0xe6, 0xd6, 0x5a, 0x23, 0xfa, 0x5b, 0xd5, 0x63, 0x44, 0x85, 0x48, 0x7b, 0xec, 0x44,
+	0xd5, 0x58, 0x16, 0x85, 0x74, 0xaa, 0x5d, 0xad, 0x11, 0x23, 0xe0, 0xef, 0xa0, 0xe5, 0x1a, 0x57,
+	0x3a, 0xc6, 0xae, 0x01, 0xd0, 0xa9, 0x9a, 0xe2, 0x22, 0x73, 0x2b, 0xa7, 0x2b, 0xf8, 0x26, 0x74,
+	0xf4, 0x30, 0x94, 0xf1, 0xd8, 0x8d, 0x3d, 0x61, 0xbf, 0xed, 0x1d, 0x90, 0xc8, 0xf8, 0x35, 0xa0,
+	0x21, 0x1b, 0xb1, 0x13, 0x9f, 0x9f, 0x77, 0x09, 0x5c, 0x85, 0x76, 0x72, 0xf5, 0xd9, 0x19, 0x93,
+	0x2e, 0x54, 0xb1, 0x0a, 0x3f, 0x84, 0x8d, 0xac, 0x0f, 0x73, 0xe3, 0x8b, 0xf7, 0xb8, 0xef, 0xf0,
+	0x19, 0x74, 0xf6, 0xa8, 0xcf, 0xdd, 0x55, 0xa5, 0xea, 0x96, 0xaa, 0xb6, 0x2d, 0x4a, 0x45, 0xe8,
+	0xc6, 0xb9, 0x39, 0x10, 0x75, 0x92, 0x2e, 0xa0, 0xbb, 0xe9, 0xe0, 0x32, 0x8d, 0xbd, 0x5c, 0x4a,
+	0x03, 0x75, 0x9a, 0x93, 0xe1, 0x85, 0xcf, 0x60, 0x3d, 0xeb, 0x58, 0x6d, 0xa2, 0x2f, 0xf3, 0x71,
+	0x17, 0x07, 0x79, 0x16, 0xef, 0x22, 0x7b, 0xff, 0x19, 0x78, 0x1f, 0x56, 0x7e, 0xa4, 0x33, 0x5d,
+	0xf8, 0x1e, 0xac, 0x1c, 0xd3, 0x59, 0xa6, 0x1f, 0x4e, 0x5c, 0x34, 0xf3, 0xf1, 0x7f, 0x1e, 0xa0,
+	0x61, 0x70, 0x44, 0x47, 0xfe, 0x90, 0xfa, 0x3c, 0x38, 0xb2, 0x5d, 0xfd, 0x06, 0x40, 0x68, 0xf9,
+	0xe5, 0x6c, 0x6c, 0x18, 0xda, 0x9d, 0x2b, 0xc2, 0x30, 0x01, 0x90, 0x0c, 0x58, 0x11, 0x22, 0xf2,
+	0x47, 0x8e, 0xbf, 0xfa, 0x1b, 0x6d, 0x43, 0xcb, 0x06, 0xa2, 0x2a, 0xaa, 0xd2, 0xba, 0x58, 0x30,
+	0x66, 0x33, 0x20, 0x09, 0x2e, 0x4f, 0xa2, 0x46, 0x15, 0x89, 0x9a, 0x05, 0x12, 0xfd, 0xe9, 0xc1,
+	0x46, 0x36, 0x27, 0xc7, 0xa2, 0x5b, 0xb0, 0x2c, 0xdf, 0x29, 0x1d, 0x0d, 0x43, 0xf7, 0x61, 0x85,
+	0x1b, 0x4d, 0xdb, 0x8a, 0x1b, 0x45, 0x8d, 0x39, 0x1f, 0xc4, 0x69, 0xe0, 0xa7, 0x80, 0xe6, 0xb7,
+	0x93, 0xda, 0x78, 0x99, 0xda, 0xe4, 0xf2, 0x5c, 0x2a, 0xe4, 0x79, 0xf3, 0x13, 0x80, 0x34, 0x30,
+	0xd4, 0x86, 0xc6, 0xce, 0xf3, 0x47, 0xc3, 0xe1, 0x7a, 0x0d, 0x75, 0xa0, 0x75, 0x40, 0xf6, 0x0f,
+	0x76, 0xc9, 0xcb, 0x5f, 0xd6, 0xbd, 0xed, 0x7f, 0x5a, 0xb0, 0xb6, 0x93, 0x0d, 0x0e, 0x3d, 0x81,
+	0xee, 0x33, 0x91, 0x3b, 0xdb, 0x65, 0xa7, 0xa6, 0x7f, 0xa5, 0x64, 0xd1, 0x69, 0xe0, 0x1a, 0x7a,
+	0x0c, 0x6b, 0xcf, 0x44, 0xf6, 0x1f, 0xb1, 0xd4, 0x48, 0xbf, 0x64, 0xd1, 0x2a, 0xe0, 0x1a, 0x1a,
+	0x42, 0x27, 0xfb, 0xf3, 0x88, 0xe6, 0xca, 0x38, 0xf7, 0x67, 0xd9, 0xbf, 0x56, 0xf9, 0x3f, 0x27,
+	0x70, 0x0d, 0xbd, 0x82, 0x4e, 0xb6, 0xbe, 0xa8, 0xaa, 0x37, 0xd6, 0x28, 0x3e, 0xb7, 0x7d, 0xca,
+	0xf0, 0x31, 0x0c, 0x86, 0xfe, 0x1b, 0xfa, 0x3d, 0x95, 0xd9, 0x41, 0xf4, 0x8a, 0xc9, 0xa3, 0x9d,
+	0x84, 0x7c, 0x73, 0xce, 0xe6, 0xc6, 0x62, 0x1f, 0x57, 0x40, 0x52, 0x67, 0xdf, 0xc2, 0x9a, 0x19,
+	0x25, 0x4f, 0x63, 0xbd, 0x55, 0x5e, 0xde, 0xf2, 0x4b, 0x08, 0xd7, 0xd0, 0x0f, 0x80, 0x5e, 0x4c,
+	0x4e, 0x24, 0xcb, 0xdb, 0xb8, 0x54, 0x62, 0x43, 0x4d, 0xa3, 0xfe, 0xe2, 0x29, 0x86, 0x6b, 0xe8,
+	0x81, 0xfb, 0x49, 0x7a, 0x1a, 0x73, 0xfb, 0x2b, 0x51, 0x76, 0x9d, 0x2e, 0x0e, 0xe6, 0x57, 0xb8,
+	0x90, 0xdc, 0xaf, 0x43, 0xc9, 0xa9, 0x3f, 0x42, 0xd7, 0x4b, 0xb1, 0xe9, 0x85, 0xde, 0x1f, 0x2c,
+	0x06, 0x98, 0x0b, 0x1a, 0xd7, 0xb6, 0xbc, 0x3b, 0x1e, 0x7a, 0x09, 0x9b, 0xd9, 0x59, 0xf9, 0x78,
+	0x66, 0x80, 0xa8, 0xfa, 0xd7, 0xa9, 0x5f, 0x35, 0x6f, 0x71, 0x0d, 0xf9, 0x70, 0x59, 0x97, 0xaf,
+	0xd4, 0xf4, 0x8d, 0x4a, 0xd3, 0xba, 0x9e, 0xd7, 0x2b, 0xcc, 0xdb, 0xaa, 0x3e, 0x84, 0x65, 0xf5,
+	0x00, 0x45, 0xc5, 0xd2, 0xa7, 0x0f, 0xd8, 0xfe, 0x95, 0x92, 0x2d, 0xf7, 0x60, 0xc5, 0x35, 0x44,
+	0xa0, 0x93, 0x7d, 0x03, 0xcf, 0xa5, 0x9c, 0x7f, 0xa0, 0xf7, 0x8b, 0x61, 0x97, 0xbc, 0x9f, 0x6b,
+	0xaf, 0x9b, 0xfa, 0xb5, 0x7f, 0xf7, 0xff, 0x01, 0x00, 0x59, 0x2e, 0x1a, 0x18, 0x04, 0x10, 0x00,
+	0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1231,11 +1710,13 @@ const _ = grpc.SupportPackageIsVersion4
 type ContextionaryClient interface {
 	IsWordStopword(ctx context.Context, in *Word, opts ...grpc.CallOption) (*WordStopword, error)
 	IsWordPresent(ctx context.Context, in *Word, opts ...grpc.CallOption) (*WordPresent, error)
+	SuggestWords(ctx context.Context, in *SuggestWordsParams, opts ...grpc.CallOption) (*WordSuggestions, error)
 	SchemaSearch(ctx context.Context, in *SchemaSearchParams, opts ...grpc.CallOption) (*SchemaSearchResults, error)
 	SafeGetSimilarWordsWithCertainty(ctx context.Context, in *SimilarWordsParams, opts ...grpc.CallOption) (*SimilarWordsResults, error)
 	VectorForWord(ctx context.Context, in *Word, opts ...grpc.CallOption) (*Vector, error)
 	MultiVectorForWord(ctx context.Context, in *WordList, opts ...grpc.CallOption) (*VectorList, error)
 	VectorForCorpi(ctx context.Context, in *Corpi, opts ...grpc.CallOption) (*Vector, error)
+	VectorizeStream(ctx context.Context, opts ...grpc.CallOption) (Contextionary_VectorizeStreamClient, error)
 	NearestWordsByVector(ctx context.Context, in *VectorNNParams, opts ...grpc.CallOption) (*NearestWords, error)
 	MultiNearestWordsByVector(ctx context.Context, in *VectorNNParamsList, opts ...grpc.CallOption) (*NearestWordsList, error)
 	Meta(ctx context.Context, in *MetaParams, opts ...grpc.CallOption) (*MetaOverview, error)
@@ -1268,6 +1749,15 @@ func (c *contextionaryClient) IsWordPresent(ctx context.Context, in *Word, opts
 	return out, nil
 }
 
+func (c *contextionaryClient) SuggestWords(ctx context.Context, in *SuggestWordsParams, opts ...grpc.CallOption) (*WordSuggestions, error) {
+	out := new(WordSuggestions)
+	err := c.cc.Invoke(ctx, "/contextionary.Contextionary/SuggestWords", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *contextionaryClient) SchemaSearch(ctx context.Context, in *SchemaSearchParams, opts ...grpc.CallOption) (*SchemaSearchResults, error) {
 	out := new(SchemaSearchResults)
 	err := c.cc.Invoke(ctx, "/contextionary.Contextionary/SchemaSearch", in, out, opts...)
@@ -1313,6 +1803,37 @@ func (c *contextionaryClient) VectorForCorpi(ctx context.Context, in *Corpi, opt
 	return out, nil
 }
 
+func (c *contextionaryClient) VectorizeStream(ctx context.Context, opts ...grpc.CallOption) (Contextionary_VectorizeStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Contextionary_serviceDesc.Streams[0], "/contextionary.Contextionary/VectorizeStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &contextionaryVectorizeStreamClient{stream}
+	return x, nil
+}
+
+type Contextionary_VectorizeStreamClient interface {
+	Send(*VectorizeRequest) error
+	Recv() (*VectorizeResponse, error)
+	grpc.ClientStream
+}
+
+type contextionaryVectorizeStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *contextionaryVectorizeStreamClient) Send(m *VectorizeRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *contextionaryVectorizeStreamClient) Recv() (*VectorizeResponse, error) {
+	m := new(VectorizeResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *contextionaryClient) NearestWordsByVector(ctx context.Context, in *VectorNNParams, opts ...grpc.CallOption) (*NearestWords, error) {
 	out := new(NearestWords)
 	err := c.cc.Invoke(ctx, "/contextionary.Contextionary/NearestWordsByVector", in, out, opts...)
@@ -1353,11 +1874,13 @@ func (c *contextionaryClient) AddExtension(ctx context.Context, in *ExtensionInp
 type ContextionaryServer interface {
 	IsWordStopword(context.Context, *Word) (*WordStopword, error)
 	IsWordPresent(context.Context, *Word) (*WordPresent, error)
+	SuggestWords(context.Context, *SuggestWordsParams) (*WordSuggestions, error)
 	SchemaSearch(context.Context, *SchemaSearchParams) (*SchemaSearchResults, error)
 	SafeGetSimilarWordsWithCertainty(context.Context, *SimilarWordsParams) (*SimilarWordsResults, error)
 	VectorForWord(context.Context, *Word) (*Vector, error)
 	MultiVectorForWord(context.Context, *WordList) (*VectorList, error)
 	VectorForCorpi(context.Context, *Corpi) (*Vector, error)
+	VectorizeStream(Contextionary_VectorizeStreamServer) error
 	NearestWordsByVector(context.Context, *VectorNNParams) (*NearestWords, error)
 	MultiNearestWordsByVector(context.Context, *VectorNNParamsList) (*NearestWordsList, error)
 	Meta(context.Context, *MetaParams) (*MetaOverview, error)
@@ -1374,6 +1897,9 @@ func (*UnimplementedContextionaryServer) IsWordStopword(ctx context.Context, req
 func (*UnimplementedContextionaryServer) IsWordPresent(ctx context.Context, req *Word) (*WordPresent, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method IsWordPresent not implemented")
 }
+func (*UnimplementedContextionaryServer) SuggestWords(ctx context.Context, req *SuggestWordsParams) (*WordSuggestions, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SuggestWords not implemented")
+}
 func (*UnimplementedContextionaryServer) SchemaSearch(ctx context.Context, req *SchemaSearchParams) (*SchemaSearchResults, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SchemaSearch not implemented")
 }
@@ -1389,6 +1915,9 @@ func (*UnimplementedContextionaryServer) MultiVectorForWord(ctx context.Context,
 func (*UnimplementedContextionaryServer) VectorForCorpi(ctx context.Context, req *Corpi) (*Vector, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method VectorForCorpi not implemented")
 }
+func (*UnimplementedContextionaryServer) VectorizeStream(srv Contextionary_VectorizeStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method VectorizeStream not implemented")
+}
 func (*UnimplementedContextionaryServer) NearestWordsByVector(ctx context.Context, req *VectorNNParams) (*NearestWords, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method NearestWordsByVector not implemented")
 }
@@ -1442,6 +1971,24 @@ func _Contextionary_IsWordPresent_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Contextionary_SuggestWords_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SuggestWordsParams)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContextionaryServer).SuggestWords(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/contextionary.Contextionary/SuggestWords",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContextionaryServer).SuggestWords(ctx, req.(*SuggestWordsParams))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Contextionary_SchemaSearch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SchemaSearchParams)
 	if err := dec(in); err != nil {
@@ -1532,6 +2079,32 @@ func _Contextionary_VectorForCorpi_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Contextionary_VectorizeStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ContextionaryServer).VectorizeStream(&contextionaryVectorizeStreamServer{stream})
+}
+
+type Contextionary_VectorizeStreamServer interface {
+	Send(*VectorizeResponse) error
+	Recv() (*VectorizeRequest, error)
+	grpc.ServerStream
+}
+
+type contextionaryVectorizeStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *contextionaryVectorizeStreamServer) Send(m *VectorizeResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *contextionaryVectorizeStreamServer) Recv() (*VectorizeRequest, error) {
+	m := new(VectorizeRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func _Contextionary_NearestWordsByVector_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(VectorNNParams)
 	if err := dec(in); err != nil {
@@ -1616,6 +2189,10 @@ var _Contextionary_serviceDesc = grpc.ServiceDesc{
 			MethodName: "IsWordPresent",
 			Handler:    _Contextionary_IsWordPresent_Handler,
 		},
+		{
+			MethodName: "SuggestWords",
+			Handler:    _Contextionary_SuggestWords_Handler,
+		},
 		{
 			MethodName: "SchemaSearch",
 			Handler:    _Contextionary_SchemaSearch_Handler,
@@ -1653,6 +2230,13 @@ var _Contextionary_serviceDesc = grpc.ServiceDesc{
 			Handler:    _Contextionary_AddExtension_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "VectorizeStream",
+			Handler:       _Contextionary_VectorizeStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
 	Metadata: "contextionary.proto",
 }
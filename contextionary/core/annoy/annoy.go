@@ -0,0 +1,320 @@
+/*                          _       _
+ *__      _____  __ ___   ___  __ _| |_ ___
+ *\ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+ * \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+ *  \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+ *
+ * Copyright © 2016 - 2019 Weaviate. All rights reserved.
+ * LICENSE: https://github.com/weaviate/weaviate/blob/master/LICENSE
+ * DESIGN & CONCEPT: Bob van Luijt (@bobvanluijt)
+ * CONTACT: hello@weaviate.io
+ */
+
+// Package annoy is a native Go implementation of the Annoy approximate
+// nearest neighbor index. It reads and writes the exact same file format as
+// the C++ library (wrapped in the annoyindex package) and answers queries with
+// the same results, but does not require cgo.
+package annoy
+
+import (
+	"container/heap"
+	"fmt"
+	"os"
+	"sort"
+	"syscall"
+)
+
+// AnnoyIndex mirrors the API of the SWIG-generated annoyindex.AnnoyIndex, so
+// it can be used as a drop-in replacement.
+type AnnoyIndex interface {
+	AddItem(item int, w []float32)
+	Build(q int) error
+	Save(filename string) error
+	Unload() error
+	Load(filename string) error
+	LoadBytes(raw []byte) error
+	GetDistance(i, j int) float32
+	GetNnsByItem(item int, n int, searchK int, result *[]int, distances *[]float32)
+	GetNnsByVector(w []float32, n int, searchK int, result *[]int, distances *[]float32) error
+	GetNItems() int
+	GetItem(item int, v *[]float32)
+}
+
+type annoyIndex struct {
+	f int
+	d metric
+
+	// s is the size of each node, k is the max number of descendants that fit
+	// into a node
+	s int
+	k int
+
+	nodes     []byte
+	nodesSize int
+	nItems    int
+	nNodes    int
+	roots     []int32
+
+	loaded  bool
+	mmapped bool
+	random  *kiss64Random
+}
+
+// NewAnnoyIndexEuclidean creates an empty index for vectors of length f
+// using the euclidean distance
+func NewAnnoyIndexEuclidean(f int) AnnoyIndex {
+	return newAnnoyIndex(f, euclidean{})
+}
+
+// NewAnnoyIndexAngular creates an empty index for vectors of length f using
+// the angular distance, which is sqrt(2 - 2cos(x, y))
+func NewAnnoyIndexAngular(f int) AnnoyIndex {
+	return newAnnoyIndex(f, angular{})
+}
+
+func newAnnoyIndex(f int, d metric) *annoyIndex {
+	s := d.vectorOffset() + f*4
+	return &annoyIndex{
+		f:      f,
+		d:      d,
+		s:      s,
+		k:      (s - d.childrenOffset()) / 4,
+		random: newKiss64Random(),
+	}
+}
+
+func (a *annoyIndex) get(i int32) node {
+	begin := int(i) * a.s
+	return node(a.nodes[begin : begin+a.s])
+}
+
+func (a *annoyIndex) child(n node, nr int) int32 {
+	return n.int32At(a.d.childrenOffset() + 4*nr)
+}
+
+func (a *annoyIndex) setChild(n node, nr int, value int32) {
+	n.setInt32At(a.d.childrenOffset()+4*nr, value)
+}
+
+// Load mmaps an index file that was previously written by Save (or by the C++
+// library)
+func (a *annoyIndex) Load(filename string) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return fmt.Errorf("open annoy index at %s: %v", filename, err)
+	}
+	defer file.Close()
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("stat annoy index at %s: %v", filename, err)
+	}
+
+	mmap, err := syscall.Mmap(int(file.Fd()), 0, int(fileInfo.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return fmt.Errorf("mmap annoy index at %s: %v", filename, err)
+	}
+
+	if err := a.LoadBytes(mmap); err != nil {
+		syscall.Munmap(mmap)
+		return err
+	}
+
+	a.mmapped = true
+	return nil
+}
+
+// LoadBytes uses raw as the index. raw is not copied, so this can be used with
+// memory which was already mmapped by the caller. Ownership of the memory stays
+// with the caller, Unload will not munmap it.
+func (a *annoyIndex) LoadBytes(raw []byte) error {
+	if len(raw) == 0 || len(raw)%a.s != 0 {
+		return fmt.Errorf("annoy index has size %d, which is not a multiple of the node size %d "+
+			"for vectors of length %d", len(raw), a.s, a.f)
+	}
+
+	a.nodes = raw
+	a.nNodes = len(raw) / a.s
+	a.nodesSize = a.nNodes
+
+	// Find the roots by scanning the end of the file and taking the nodes with
+	// most descendants
+	a.roots = nil
+	m := int32(-1)
+	for i := int32(a.nNodes - 1); i >= 0; i-- {
+		k := a.get(i).nDescendants()
+		if m == -1 || k == m {
+			a.roots = append(a.roots, i)
+			m = k
+		} else {
+			break
+		}
+	}
+
+	// since the last root precedes the copy of all roots, delete it
+	if len(a.roots) > 1 && a.child(a.get(a.roots[0]), 0) == a.child(a.get(a.roots[len(a.roots)-1]), 0) {
+		a.roots = a.roots[:len(a.roots)-1]
+	}
+
+	a.loaded = true
+	a.nItems = int(m)
+	return nil
+}
+
+// Unload releases the index, it can be reused to build or load another one
+// afterwards
+func (a *annoyIndex) Unload() error {
+	if a.mmapped {
+		if err := syscall.Munmap(a.nodes); err != nil {
+			return fmt.Errorf("munmap annoy index: %v", err)
+		}
+	}
+
+	a.nodes = nil
+	a.nodesSize = 0
+	a.nItems = 0
+	a.nNodes = 0
+	a.roots = nil
+	a.loaded = false
+	a.mmapped = false
+	return nil
+}
+
+func (a *annoyIndex) GetNItems() int {
+	return a.nItems
+}
+
+func (a *annoyIndex) GetItem(item int, v *[]float32) {
+	n := a.get(int32(item))
+	vo := a.d.vectorOffset()
+	out := make([]float32, a.f)
+	for z := range out {
+		out[z] = n.float32At(vo + 4*z)
+	}
+	*v = out
+}
+
+func (a *annoyIndex) GetDistance(i, j int) float32 {
+	return a.d.normalizedDistance(a.d.distance(a.get(int32(i)), a.get(int32(j)), a.f))
+}
+
+func (a *annoyIndex) GetNnsByItem(item int, n int, searchK int, result *[]int, distances *[]float32) {
+	m := a.get(int32(item))
+	vo := a.d.vectorOffset()
+	a.getAllNns(m[vo:vo+4*a.f], n, searchK, result, distances)
+}
+
+// GetNnsByVector fails if w doesn't have the dimensions of the index, unlike
+// in the C++ library
+func (a *annoyIndex) GetNnsByVector(w []float32, n int, searchK int, result *[]int, distances *[]float32) error {
+	if len(w) != a.f {
+		return fmt.Errorf("vector has %d dimensions, the annoy index has %d", len(w), a.f)
+	}
+
+	raw := make(node, 4*a.f)
+	for z, value := range w {
+		raw.setFloat32At(4*z, value)
+	}
+	a.getAllNns(raw, n, searchK, result, distances)
+	return nil
+}
+
+func (a *annoyIndex) getAllNns(v []byte, n int, searchK int, result *[]int, distances *[]float32) {
+	vo := a.d.vectorOffset()
+	vNode := make(node, a.s)
+	copy(vNode[vo:], v)
+	a.d.initNode(vNode, a.f)
+
+	if searchK < 0 {
+		searchK = n * len(a.roots) // slightly arbitrary default value
+	}
+
+	q := &priorityQueue{}
+	for _, root := range a.roots {
+		heap.Push(q, pqItem{distance: a.d.pqInitialValue(), index: root})
+	}
+
+	var nns []int32
+	for len(nns) < searchK && q.Len() > 0 {
+		top := heap.Pop(q).(pqItem)
+		nd := a.get(top.index)
+		descendants := nd.nDescendants()
+
+		if descendants == 1 && int(top.index) < a.nItems {
+			nns = append(nns, top.index)
+		} else if int(descendants) <= a.k {
+			for c := 0; c < int(descendants); c++ {
+				nns = append(nns, a.child(nd, c))
+			}
+		} else {
+			margin := a.d.margin(nd, vNode, a.f)
+			heap.Push(q, pqItem{distance: a.d.pqDistance(top.distance, margin, 1), index: a.child(nd, 1)})
+			heap.Push(q, pqItem{distance: a.d.pqDistance(top.distance, margin, 0), index: a.child(nd, 0)})
+		}
+	}
+
+	// Get distances for all items. To avoid calculating distance multiple times
+	// for any items, sort by id
+	sort.Slice(nns, func(i, j int) bool { return nns[i] < nns[j] })
+	nnsDist := make([]pqItem, 0, len(nns))
+	last := int32(-1)
+	for _, j := range nns {
+		if j == last {
+			continue
+		}
+		last = j
+		if a.get(j).nDescendants() == 1 {
+			nnsDist = append(nnsDist, pqItem{distance: a.d.distance(vNode, a.get(j), a.f), index: j})
+		}
+	}
+
+	sort.Slice(nnsDist, func(i, j int) bool { return nnsDist[i].less(nnsDist[j]) })
+	p := n
+	if len(nnsDist) < p {
+		p = len(nnsDist)
+	}
+
+	res := make([]int, p)
+	var dists []float32
+	if distances != nil {
+		dists = make([]float32, p)
+	}
+	for i := 0; i < p; i++ {
+		res[i] = int(nnsDist[i].index)
+		if distances != nil {
+			dists[i] = a.d.normalizedDistance(nnsDist[i].distance)
+		}
+	}
+
+	*result = res
+	if distances != nil {
+		*distances = dists
+	}
+}
+
+type pqItem struct {
+	distance float32
+	index    int32
+}
+
+// less compares like std::pair<T, S>, i.e. by distance first, then by index
+func (p pqItem) less(other pqItem) bool {
+	if p.distance != other.distance {
+		return p.distance < other.distance
+	}
+	return p.index < other.index
+}
+
+// priorityQueue is a max-heap, just like std::priority_queue
+type priorityQueue []pqItem
+
+func (q priorityQueue) Len() int            { return len(q) }
+func (q priorityQueue) Less(i, j int) bool  { return q[j].less(q[i]) }
+func (q priorityQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *priorityQueue) Push(x interface{}) { *q = append(*q, x.(pqItem)) }
+func (q *priorityQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
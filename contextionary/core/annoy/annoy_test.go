@@ -0,0 +1,174 @@
+/*                          _       _
+ *__      _____  __ ___   ___  __ _| |_ ___
+ *\ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+ * \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+ *  \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+ *
+ * Copyright © 2016 - 2019 Weaviate. All rights reserved.
+ * LICENSE: https://github.com/weaviate/weaviate/blob/master/LICENSE
+ * DESIGN & CONCEPT: Bob van Luijt (@bobvanluijt)
+ * CONTACT: hello@weaviate.io
+ */
+package annoy
+
+import (
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEuclideanIndex(t *testing.T) {
+	newIndex := func() AnnoyIndex {
+		index := NewAnnoyIndexEuclidean(3)
+		index.AddItem(0, []float32{0, 0, 1})
+		index.AddItem(1, []float32{0, 1, 0})
+		index.AddItem(2, []float32{1, 0, 0})
+		require.Nil(t, index.Build(10))
+		return index
+	}
+
+	t.Run("getting nearest neighbors by vector", func(t *testing.T) {
+		index := newIndex()
+
+		var result []int
+		var distances []float32
+		require.Nil(t, index.GetNnsByVector([]float32{3, 2, 1}, 3, -1, &result, &distances))
+		assert.Equal(t, []int{2, 1, 0}, result)
+		assert.InDeltaSlice(t, []float32{3, 3.3166248, 3.6055512}, distances, 1e-6)
+
+		require.Nil(t, index.GetNnsByVector([]float32{1, 2, 3}, 3, -1, &result, nil))
+		assert.Equal(t, []int{0, 1, 2}, result)
+	})
+
+	t.Run("getting nearest neighbors by a vector with the wrong dimensions", func(t *testing.T) {
+		index := newIndex()
+
+		var result []int
+		assert.NotNil(t, index.GetNnsByVector([]float32{1, 2, 3, 4}, 3, -1, &result, nil))
+		assert.NotNil(t, index.GetNnsByVector([]float32{1, 2}, 3, -1, &result, nil))
+	})
+
+	t.Run("getting nearest neighbors by item", func(t *testing.T) {
+		index := newIndex()
+
+		var result []int
+		var distances []float32
+		index.GetNnsByItem(0, 2, -1, &result, &distances)
+		assert.Equal(t, []int{0, 1}, result)
+		assert.InDeltaSlice(t, []float32{0, 1.4142135}, distances, 1e-6)
+	})
+
+	t.Run("getting items and distances", func(t *testing.T) {
+		index := newIndex()
+
+		var result []float32
+		index.GetItem(1, &result)
+		assert.Equal(t, []float32{0, 1, 0}, result)
+		assert.InDelta(t, 1.4142135, index.GetDistance(0, 1), 1e-6)
+		assert.Equal(t, 3, index.GetNItems())
+	})
+
+	t.Run("building a loaded index", func(t *testing.T) {
+		index := newIndex()
+		require.Nil(t, index.LoadBytes(make([]byte, 28*3)))
+		assert.NotNil(t, index.Build(10))
+	})
+}
+
+func TestAngularIndex(t *testing.T) {
+	index := NewAnnoyIndexAngular(3)
+	index.AddItem(0, []float32{0, 0, 1})
+	index.AddItem(1, []float32{0, 1, 0})
+	index.AddItem(2, []float32{2, 0, 0})
+	index.AddItem(3, []float32{0, 0, 5})
+	require.Nil(t, index.Build(10))
+
+	t.Run("the length of the vectors doesn't matter", func(t *testing.T) {
+		var result []int
+		var distances []float32
+		require.Nil(t, index.GetNnsByVector([]float32{0, 0.1, 3}, 4, -1, &result, &distances))
+		assert.ElementsMatch(t, []int{0, 3}, result[:2])
+		assert.Equal(t, []int{1, 2}, result[2:])
+		assert.InDelta(t, distances[0], distances[1], 1e-6)
+	})
+
+	t.Run("distances are sqrt(2-2cos)", func(t *testing.T) {
+		assert.InDelta(t, 0, index.GetDistance(0, 3), 1e-3)
+		assert.InDelta(t, 1.4142135, index.GetDistance(0, 2), 1e-6)
+
+		var item []float32
+		index.GetItem(2, &item)
+		assert.Equal(t, []float32{2, 0, 0}, item)
+	})
+}
+
+func TestSavingAndLoading(t *testing.T) {
+	dir, err := ioutil.TempDir("", "annoy-test")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	fileName := filepath.Join(dir, "test.knn")
+
+	built := NewAnnoyIndexEuclidean(10)
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		v := make([]float32, 10)
+		for j := range v {
+			v[j] = r.Float32()
+		}
+		built.AddItem(i, v)
+	}
+	require.Nil(t, built.Build(5))
+	require.Nil(t, built.Save(fileName))
+
+	loaded := NewAnnoyIndexEuclidean(10)
+	require.Nil(t, loaded.Load(fileName))
+	defer loaded.Unload()
+
+	assert.Equal(t, 1000, loaded.GetNItems())
+	for i := 0; i < 1000; i += 50 {
+		var expected, actual []int
+		built.GetNnsByItem(i, 10, -1, &expected, nil)
+		loaded.GetNnsByItem(i, 10, -1, &actual, nil)
+		assert.Equal(t, expected, actual)
+	}
+
+	t.Run("with a file that doesn't match the dimensions", func(t *testing.T) {
+		wrongDims := NewAnnoyIndexEuclidean(11)
+		assert.NotNil(t, wrongDims.Load(fileName))
+	})
+}
+
+func TestLargeEuclideanIndex(t *testing.T) {
+	index := NewAnnoyIndexEuclidean(10)
+	r := rand.New(rand.NewSource(2))
+
+	for j := 0; j < 10000; j += 2 {
+		p := make([]float32, 10)
+		for i := range p {
+			p[i] = r.Float32()
+		}
+		x := make([]float32, 10)
+		y := make([]float32, 10)
+		for i := range p {
+			x[i] = 1 + p[i] + r.Float32()*1e-2
+			y[i] = 1 + p[i] + r.Float32()*1e-2
+		}
+		index.AddItem(j, x)
+		index.AddItem(j+1, y)
+	}
+	require.Nil(t, index.Build(10))
+
+	for j := 0; j < 10000; j += 2 {
+		var result []int
+		index.GetNnsByItem(j, 2, -1, &result, nil)
+		assert.Equal(t, []int{j, j + 1}, result)
+
+		index.GetNnsByItem(j+1, 2, -1, &result, nil)
+		assert.Equal(t, []int{j + 1, j}, result)
+	}
+}
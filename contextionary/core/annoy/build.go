@@ -0,0 +1,191 @@
+/*                          _       _
+ *__      _____  __ ___   ___  __ _| |_ ___
+ *\ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+ * \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+ *  \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+ *
+ * Copyright © 2016 - 2019 Weaviate. All rights reserved.
+ * LICENSE: https://github.com/weaviate/weaviate/blob/master/LICENSE
+ * DESIGN & CONCEPT: Bob van Luijt (@bobvanluijt)
+ * CONTACT: hello@weaviate.io
+ */
+package annoy
+
+import (
+	"fmt"
+	"io/ioutil"
+)
+
+// AddItem adds the vector w at position item. Items don't have to be added in
+// order, but the index will allocate memory for all positions up to item.
+func (a *annoyIndex) AddItem(item int, w []float32) {
+	a.allocateSize(item + 1)
+	n := a.get(int32(item))
+
+	a.setChild(n, 0, 0)
+	a.setChild(n, 1, 0)
+	n.setNDescendants(1)
+
+	vo := a.d.vectorOffset()
+	for z := 0; z < a.f; z++ {
+		n.setFloat32At(vo+4*z, w[z])
+	}
+	a.d.initNode(n, a.f)
+
+	if item >= a.nItems {
+		a.nItems = item + 1
+	}
+}
+
+// Build builds a forest of q trees. More trees give higher precision when
+// querying. With q=-1 trees are built until the index has twice as many nodes
+// as items.
+func (a *annoyIndex) Build(q int) error {
+	if a.loaded {
+		return fmt.Errorf("can't build a loaded index")
+	}
+
+	a.nNodes = a.nItems
+	for {
+		if q == -1 && a.nNodes >= a.nItems*2 {
+			break
+		}
+		if q != -1 && len(a.roots) >= q {
+			break
+		}
+
+		var indices []int32
+		for i := 0; i < a.nItems; i++ {
+			if a.get(int32(i)).nDescendants() >= 1 { // Issue #223
+				indices = append(indices, int32(i))
+			}
+		}
+
+		a.roots = append(a.roots, a.makeTree(indices, true))
+	}
+
+	// Also, copy the roots into the last segment of the array. This way they
+	// can be loaded faster without reading the whole file
+	a.allocateSize(a.nNodes + len(a.roots))
+	for i, root := range a.roots {
+		copy(a.get(int32(a.nNodes+i)), a.get(root))
+	}
+	a.nNodes += len(a.roots)
+
+	return nil
+}
+
+// Save writes the index to disk. Afterwards the index is considered loaded,
+// so it can be queried, but no longer be built.
+func (a *annoyIndex) Save(filename string) error {
+	raw := a.nodes[:a.nNodes*a.s]
+	if err := ioutil.WriteFile(filename, raw, 0644); err != nil {
+		return fmt.Errorf("save annoy index to %s: %v", filename, err)
+	}
+
+	return a.LoadBytes(raw)
+}
+
+func (a *annoyIndex) allocateSize(n int) {
+	if n <= a.nodesSize {
+		return
+	}
+
+	const reallocationFactor = 1.3
+	newNodesSize := int(float64(a.nodesSize+1) * reallocationFactor)
+	if n > newNodesSize {
+		newNodesSize = n
+	}
+
+	nodes := make([]byte, newNodesSize*a.s)
+	copy(nodes, a.nodes)
+	a.nodes = nodes
+	a.nodesSize = newNodesSize
+}
+
+// makeTree recursively splits indices into two sides until each side is small
+// enough to fit into a single node. Root nodes are special, they are
+// identified by having nItems descendants regardless of how many descendants
+// they actually have.
+func (a *annoyIndex) makeTree(indices []int32, isRoot bool) int32 {
+	if len(indices) == 1 && !isRoot {
+		return indices[0]
+	}
+
+	descendants := int32(len(indices))
+	if isRoot {
+		descendants = int32(a.nItems)
+	}
+
+	if len(indices) <= a.k && (!isRoot || a.nItems <= a.k || len(indices) == 1) {
+		a.allocateSize(a.nNodes + 1)
+		item := int32(a.nNodes)
+		a.nNodes++
+		m := a.get(item)
+		m.setNDescendants(descendants)
+		for i, index := range indices {
+			a.setChild(m, i, index)
+		}
+		return item
+	}
+
+	children := make([]node, len(indices))
+	for i, j := range indices {
+		children[i] = a.get(j)
+	}
+
+	m := make(node, a.s)
+	a.d.createSplit(children, a.f, a.s, a.random, m)
+
+	var childrenIndices [2][]int32
+	for i, j := range indices {
+		side := a.side(m, children[i])
+		childrenIndices[side] = append(childrenIndices[side], j)
+	}
+
+	// If we didn't find a hyperplane, just randomize sides as a last option
+	for len(childrenIndices[0]) == 0 || len(childrenIndices[1]) == 0 {
+		childrenIndices[0] = nil
+		childrenIndices[1] = nil
+
+		vo := a.d.vectorOffset()
+		for z := 0; z < a.f; z++ {
+			m.setFloat32At(vo+4*z, 0)
+		}
+
+		for _, j := range indices {
+			side := a.random.flip()
+			childrenIndices[side] = append(childrenIndices[side], j)
+		}
+	}
+
+	flip := 0
+	if len(childrenIndices[0]) > len(childrenIndices[1]) {
+		flip = 1
+	}
+
+	m.setNDescendants(descendants)
+	for side := 0; side < 2; side++ {
+		// build the smallest child first (for cache locality)
+		a.setChild(m, side^flip, a.makeTree(childrenIndices[side^flip], false))
+	}
+
+	a.allocateSize(a.nNodes + 1)
+	item := int32(a.nNodes)
+	a.nNodes++
+	copy(a.get(item), m)
+
+	return item
+}
+
+func (a *annoyIndex) side(n, y node) int {
+	margin := a.d.margin(n, y, a.f)
+	if margin != 0 {
+		if margin > 0 {
+			return 1
+		}
+		return 0
+	}
+
+	return a.random.flip()
+}
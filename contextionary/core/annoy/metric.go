@@ -0,0 +1,230 @@
+/*                          _       _
+ *__      _____  __ ___   ___  __ _| |_ ___
+ *\ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+ * \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+ *  \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+ *
+ * Copyright © 2016 - 2019 Weaviate. All rights reserved.
+ * LICENSE: https://github.com/weaviate/weaviate/blob/master/LICENSE
+ * DESIGN & CONCEPT: Bob van Luijt (@bobvanluijt)
+ * CONTACT: hello@weaviate.io
+ */
+package annoy
+
+import (
+	"math"
+)
+
+// metric is the Go equivalent of the Distance template parameter of the C++
+// AnnoyIndex. Besides the distance itself it defines the node layout.
+type metric interface {
+	// offset of the children array (and the norm, which shares its memory)
+	childrenOffset() int
+	// offset of the vector
+	vectorOffset() int
+
+	distance(x, y node, f int) float32
+	margin(n, y node, f int) float32
+	pqDistance(distance, margin float32, childNr int) float32
+	pqInitialValue() float32
+	normalizedDistance(distance float32) float32
+	initNode(n node, f int)
+	createSplit(nodes []node, f int, s int, random *kiss64Random, n node)
+}
+
+// euclidean mirrors the Euclidean (and Minkowski) struct from annoylib.h:
+//
+//	int32   n_descendants
+//	float32 a               offset of the split plane
+//	int32   children[2]     shares its memory with float32 norm
+//	float32 v[f]
+type euclidean struct{}
+
+func (euclidean) childrenOffset() int { return 8 }
+func (euclidean) vectorOffset() int   { return 16 }
+
+func (e euclidean) norm(n node) float32 {
+	return n.float32At(e.childrenOffset())
+}
+
+func (e euclidean) distance(x, y node, f int) float32 {
+	// For backwards compatibility reasons, annoy falls back to computing the
+	// norm if it wasn't stored
+	pp := e.norm(x)
+	if pp == 0 {
+		pp = dot(x, e.vectorOffset(), x, e.vectorOffset(), f)
+	}
+	qq := e.norm(y)
+	if qq == 0 {
+		qq = dot(y, e.vectorOffset(), y, e.vectorOffset(), f)
+	}
+	pq := dot(x, e.vectorOffset(), y, e.vectorOffset(), f)
+	return pp + qq - 2*pq
+}
+
+func (e euclidean) margin(n, y node, f int) float32 {
+	return n.float32At(4) + dot(n, e.vectorOffset(), y, e.vectorOffset(), f)
+}
+
+func (euclidean) pqDistance(distance, margin float32, childNr int) float32 {
+	if childNr == 0 {
+		margin = -margin
+	}
+	return min32(distance, margin)
+}
+
+func (euclidean) pqInitialValue() float32 {
+	return float32(math.Inf(1))
+}
+
+func (euclidean) normalizedDistance(distance float32) float32 {
+	return float32(math.Sqrt(float64(max32(distance, 0))))
+}
+
+func (e euclidean) initNode(n node, f int) {
+	n.setFloat32At(e.childrenOffset(), dot(n, e.vectorOffset(), n, e.vectorOffset(), f))
+}
+
+func (e euclidean) createSplit(nodes []node, f int, s int, random *kiss64Random, n node) {
+	p := make(node, s)
+	q := make(node, s)
+	twoMeans(e, nodes, f, random, false, p, q)
+
+	vo := e.vectorOffset()
+	for z := 0; z < f; z++ {
+		n.setFloat32At(vo+4*z, p.float32At(vo+4*z)-q.float32At(vo+4*z))
+	}
+	normalize(n, vo, f)
+
+	var a float32
+	for z := 0; z < f; z++ {
+		a += -n.float32At(vo+4*z) * (p.float32At(vo+4*z) + q.float32At(vo+4*z)) / 2
+	}
+	n.setFloat32At(4, a)
+}
+
+const twoMeansIterationSteps = 200
+
+// twoMeans is a port of the two_means heuristic of annoylib.h: it keeps two
+// centroids and assigns random points to the closer one, weighting each
+// centroid by the number of points assigned to it.
+func twoMeans(d metric, nodes []node, f int, random *kiss64Random, cosine bool, p, q node) {
+	vo := d.vectorOffset()
+	count := len(nodes)
+
+	i := random.index(count)
+	j := random.index(count - 1)
+	if j >= i {
+		j++ // ensure that i != j
+	}
+
+	copy(p[vo:vo+4*f], nodes[i][vo:vo+4*f])
+	copy(q[vo:vo+4*f], nodes[j][vo:vo+4*f])
+	if cosine {
+		normalize(p, vo, f)
+		normalize(q, vo, f)
+	}
+	d.initNode(p, f)
+	d.initNode(q, f)
+
+	ic, jc := 1, 1
+	for l := 0; l < twoMeansIterationSteps; l++ {
+		k := random.index(count)
+		di := float32(ic) * d.distance(p, nodes[k], f)
+		dj := float32(jc) * d.distance(q, nodes[k], f)
+		var norm float32 = 1
+		if cosine {
+			norm = getNorm(nodes[k], vo, f)
+		}
+		if !(norm > 0) {
+			continue
+		}
+
+		if di < dj {
+			for z := 0; z < f; z++ {
+				p.setFloat32At(vo+4*z, (p.float32At(vo+4*z)*float32(ic)+nodes[k].float32At(vo+4*z)/norm)/float32(ic+1))
+			}
+			d.initNode(p, f)
+			ic++
+		} else if dj < di {
+			for z := 0; z < f; z++ {
+				q.setFloat32At(vo+4*z, (q.float32At(vo+4*z)*float32(jc)+nodes[k].float32At(vo+4*z)/norm)/float32(jc+1))
+			}
+			d.initNode(q, f)
+			jc++
+		}
+	}
+}
+
+// angular mirrors the Angular struct from annoylib.h, the distance is
+// sqrt(2 - 2cos(x, y)), i.e. the euclidean distance of the normalized vectors:
+//
+//	int32   n_descendants
+//	int32   children[2]     shares its memory with float32 norm
+//	float32 v[f]
+type angular struct{}
+
+func (angular) childrenOffset() int { return 4 }
+func (angular) vectorOffset() int   { return 12 }
+
+func (a angular) norm(n node) float32 {
+	return n.float32At(a.childrenOffset())
+}
+
+func (a angular) distance(x, y node, f int) float32 {
+	// For backwards compatibility reasons, annoy falls back to computing the
+	// norm if it wasn't stored
+	pp := a.norm(x)
+	if pp == 0 {
+		pp = dot(x, a.vectorOffset(), x, a.vectorOffset(), f)
+	}
+	qq := a.norm(y)
+	if qq == 0 {
+		qq = dot(y, a.vectorOffset(), y, a.vectorOffset(), f)
+	}
+	pq := dot(x, a.vectorOffset(), y, a.vectorOffset(), f)
+	ppqq := pp * qq
+	if ppqq > 0 {
+		// annoylib.h takes the square root in float, but uses double literals,
+		// so the rest of the calculation happens in double precision
+		sqrtPPQQ := float32(math.Sqrt(float64(ppqq)))
+		return float32(2.0 - 2.0*float64(pq)/float64(sqrtPPQQ))
+	}
+
+	return 2 // cos is 0
+}
+
+func (a angular) margin(n, y node, f int) float32 {
+	return dot(n, a.vectorOffset(), y, a.vectorOffset(), f)
+}
+
+func (angular) pqDistance(distance, margin float32, childNr int) float32 {
+	if childNr == 0 {
+		margin = -margin
+	}
+	return min32(distance, margin)
+}
+
+func (angular) pqInitialValue() float32 {
+	return float32(math.Inf(1))
+}
+
+func (angular) normalizedDistance(distance float32) float32 {
+	return float32(math.Sqrt(float64(max32(distance, 0))))
+}
+
+func (a angular) initNode(n node, f int) {
+	n.setFloat32At(a.childrenOffset(), dot(n, a.vectorOffset(), n, a.vectorOffset(), f))
+}
+
+func (a angular) createSplit(nodes []node, f int, s int, random *kiss64Random, n node) {
+	p := make(node, s)
+	q := make(node, s)
+	twoMeans(a, nodes, f, random, true, p, q)
+
+	vo := a.vectorOffset()
+	for z := 0; z < f; z++ {
+		n.setFloat32At(vo+4*z, p.float32At(vo+4*z)-q.float32At(vo+4*z))
+	}
+	normalize(n, vo, f)
+}
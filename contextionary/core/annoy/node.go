@@ -0,0 +1,86 @@
+/*                          _       _
+ *__      _____  __ ___   ___  __ _| |_ ___
+ *\ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+ * \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+ *  \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+ *
+ * Copyright © 2016 - 2019 Weaviate. All rights reserved.
+ * LICENSE: https://github.com/weaviate/weaviate/blob/master/LICENSE
+ * DESIGN & CONCEPT: Bob van Luijt (@bobvanluijt)
+ * CONTACT: hello@weaviate.io
+ */
+package annoy
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// node is a view on the raw bytes of a single Annoy node. The layout depends
+// on the metric, see the Node structs in annoylib.h. All nodes have the same
+// size, a node either holds a data point (n_descendants == 1), a list of
+// descendants (2 <= n_descendants <= K) or a split plane (n_descendants > K).
+type node []byte
+
+func (n node) int32At(offset int) int32 {
+	return int32(binary.LittleEndian.Uint32(n[offset : offset+4]))
+}
+
+func (n node) setInt32At(offset int, value int32) {
+	binary.LittleEndian.PutUint32(n[offset:offset+4], uint32(value))
+}
+
+func (n node) float32At(offset int) float32 {
+	return math.Float32frombits(binary.LittleEndian.Uint32(n[offset : offset+4]))
+}
+
+func (n node) setFloat32At(offset int, value float32) {
+	binary.LittleEndian.PutUint32(n[offset:offset+4], math.Float32bits(value))
+}
+
+func (n node) nDescendants() int32 {
+	return n.int32At(0)
+}
+
+func (n node) setNDescendants(value int32) {
+	n.setInt32At(0, value)
+}
+
+// dot product of the vectors of two nodes, accumulated in float32 in the
+// same order as annoylib.h does, so that distances match the C++ library.
+func dot(x node, xOffset int, y node, yOffset int, f int) float32 {
+	var s float32
+	for z := 0; z < f; z++ {
+		s += x.float32At(xOffset+4*z) * y.float32At(yOffset+4*z)
+	}
+	return s
+}
+
+func getNorm(n node, offset int, f int) float32 {
+	return float32(math.Sqrt(float64(dot(n, offset, n, offset, f))))
+}
+
+func normalize(n node, offset int, f int) {
+	norm := getNorm(n, offset, f)
+	if norm > 0 {
+		for z := 0; z < f; z++ {
+			n.setFloat32At(offset+4*z, n.float32At(offset+4*z)/norm)
+		}
+	}
+}
+
+// min32 mirrors std::min, which returns the first argument unless the second
+// one is strictly smaller.
+func min32(a, b float32) float32 {
+	if b < a {
+		return b
+	}
+	return a
+}
+
+func max32(a, b float32) float32 {
+	if a < b {
+		return b
+	}
+	return a
+}
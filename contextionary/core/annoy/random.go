@@ -0,0 +1,56 @@
+/*                          _       _
+ *__      _____  __ ___   ___  __ _| |_ ___
+ *\ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+ * \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+ *  \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+ *
+ * Copyright © 2016 - 2019 Weaviate. All rights reserved.
+ * LICENSE: https://github.com/weaviate/weaviate/blob/master/LICENSE
+ * DESIGN & CONCEPT: Bob van Luijt (@bobvanluijt)
+ * CONTACT: hello@weaviate.io
+ */
+package annoy
+
+// kiss64Random is a port of Kiss64Random from kissrandom.h. Using the exact
+// same generator (and seed) as the C++ library means that building the same
+// items in the same order produces the same trees.
+type kiss64Random struct {
+	x uint64
+	y uint64
+	z uint64
+	c uint64
+}
+
+func newKiss64Random() *kiss64Random {
+	return &kiss64Random{
+		x: 1234567890987654321,
+		y: 362436362436362436,
+		z: 1066149217761810,
+		c: 123456123456123456,
+	}
+}
+
+func (r *kiss64Random) kiss() uint64 {
+	r.z = 6906969069*r.z + 1234567
+
+	r.y ^= r.y << 13
+	r.y ^= r.y >> 17
+	r.y ^= r.y << 43
+
+	t := (r.x << 58) + r.c
+	r.c = r.x >> 6
+	r.x += t
+	if r.x < t {
+		r.c++
+	}
+
+	return r.x + r.y + r.z
+}
+
+func (r *kiss64Random) flip() int {
+	return int(r.kiss() & 1)
+}
+
+func (r *kiss64Random) index(n int) int {
+	return int(r.kiss() % uint64(n))
+}
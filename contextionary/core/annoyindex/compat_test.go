@@ -0,0 +1,137 @@
+/*                          _       _
+ *__      _____  __ ___   ___  __ _| |_ ___
+ *\ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+ * \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+ *  \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+ *
+ * Copyright © 2016 - 2019 Weaviate. All rights reserved.
+ * LICENSE: https://github.com/weaviate/weaviate/blob/master/LICENSE
+ * DESIGN & CONCEPT: Bob van Luijt (@bobvanluijt)
+ * CONTACT: hello@weaviate.io
+ */
+
+package annoyindex_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weaviate/contextionary/contextionary/core/annoy"
+	"github.com/weaviate/contextionary/contextionary/core/annoyindex"
+)
+
+type compatTestCase struct {
+	name      string
+	newCpp    func(f int) annoyindex.AnnoyIndex
+	deleteCpp func(index annoyindex.AnnoyIndex)
+	newNative func(f int) annoy.AnnoyIndex
+}
+
+// The native Go port in the annoy package must be a drop-in replacement for
+// this library, so both are compared against each other here.
+func TestNativePortCompatibility(t *testing.T) {
+	tests := []compatTestCase{
+		{
+			name:   "euclidean",
+			newCpp: func(f int) annoyindex.AnnoyIndex { return annoyindex.NewAnnoyIndexEuclidean(f) },
+			deleteCpp: func(index annoyindex.AnnoyIndex) {
+				annoyindex.DeleteAnnoyIndexEuclidean(index.(annoyindex.AnnoyIndexEuclidean))
+			},
+			newNative: annoy.NewAnnoyIndexEuclidean,
+		},
+		{
+			name:   "angular",
+			newCpp: func(f int) annoyindex.AnnoyIndex { return annoyindex.NewAnnoyIndexAngular(f) },
+			deleteCpp: func(index annoyindex.AnnoyIndex) {
+				annoyindex.DeleteAnnoyIndexAngular(index.(annoyindex.AnnoyIndexAngular))
+			},
+			newNative: annoy.NewAnnoyIndexAngular,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) { testNativePortCompatibility(t, test) })
+	}
+}
+
+func testNativePortCompatibility(t *testing.T, test compatTestCase) {
+	dir, err := ioutil.TempDir("", "annoy-compat")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	dims := 20
+	r := rand.New(rand.NewSource(7))
+	vectors := make([][]float32, 2000)
+	for i := range vectors {
+		vectors[i] = make([]float32, dims)
+		for j := range vectors[i] {
+			vectors[i][j] = r.Float32()*2 - 1
+		}
+	}
+
+	cppFile := filepath.Join(dir, "cpp.knn")
+	goFile := filepath.Join(dir, "go.knn")
+
+	cpp := test.newCpp(dims)
+	native := test.newNative(dims)
+	for i, v := range vectors {
+		cpp.AddItem(i, v)
+		native.AddItem(i, v)
+	}
+	cpp.Build(10)
+	require.Nil(t, native.Build(10))
+	require.True(t, cpp.Save(cppFile))
+	require.Nil(t, native.Save(goFile))
+	test.deleteCpp(cpp)
+
+	t.Run("building produces identical files", func(t *testing.T) {
+		cppBytes, err := ioutil.ReadFile(cppFile)
+		require.Nil(t, err)
+		goBytes, err := ioutil.ReadFile(goFile)
+		require.Nil(t, err)
+		assert.True(t, bytes.Equal(cppBytes, goBytes))
+	})
+
+	t.Run("querying a file built by the C++ library", func(t *testing.T) {
+		cpp := test.newCpp(dims)
+		require.True(t, cpp.Load(cppFile))
+		defer test.deleteCpp(cpp)
+
+		native := test.newNative(dims)
+		require.Nil(t, native.Load(cppFile))
+		defer native.Unload()
+
+		assert.Equal(t, cpp.GetNItems(), native.GetNItems())
+
+		for _, searchK := range []int{-1, 3, 50, 1000} {
+			for i := 0; i < 100; i++ {
+				var expected, actual []int
+				var expectedDist, actualDist []float32
+				cpp.GetNnsByItem(i, 10, searchK, &expected, &expectedDist)
+				native.GetNnsByItem(i, 10, searchK, &actual, &actualDist)
+				assert.Equal(t, expected, actual)
+				assert.InDeltaSlice(t, expectedDist, actualDist, 1e-5)
+
+				query := vectors[(i*7)%len(vectors)]
+				cpp.GetNnsByVector(query, 10, searchK, &expected, &expectedDist)
+				native.GetNnsByVector(query, 10, searchK, &actual, &actualDist)
+				assert.Equal(t, expected, actual)
+				assert.InDeltaSlice(t, expectedDist, actualDist, 1e-5)
+			}
+		}
+
+		for i := 0; i < 10; i++ {
+			var expected, actual []float32
+			cpp.GetItem(i, &expected)
+			native.GetItem(i, &actual)
+			assert.Equal(t, expected, actual)
+			assert.InDelta(t, cpp.GetDistance(i, i+1), native.GetDistance(i, i+1), 1e-5)
+		}
+	})
+}
@@ -10,8 +10,99 @@
  * CONTACT: hello@weaviate.io
  */package contextionary
 
-// DistanceToCertainty converts a vector distance to a certainty. For now it's
-// a linear scale but will most likely change at some point.
+import (
+	"math/rand"
+	"sort"
+)
+
+// DistanceToCertainty converts a euclidean vector distance to a certainty
+// assuming a max distance of 12, regardless of the model.
+//
+// Deprecated: use the CertaintyCalibration of the Contextionary instead,
+// which is tuned to the distances of the specific model.
 func DistanceToCertainty(d float32) float32 {
 	return 1 - d/12
 }
+
+// CertaintyCalibration translates the distances of a specific model to
+// certainties between 0 and 1. Every model has a different distribution of
+// distances, so a fixed scale would produce negative certainties for some
+// models and squash everything near 1 for others.
+type CertaintyCalibration struct {
+	// MaxDistance is the distance which corresponds to a certainty of 0.
+	// The certainty scales linearly in between.
+	MaxDistance float32
+}
+
+const (
+	// calibrationSamples is the number of random pairs of words whose
+	// distances are sampled to calibrate a model
+	calibrationSamples = 10000
+
+	// calibrationPercentile of the sampled distances is used as the max
+	// distance, so only the 1% most distant random pairs of words get a
+	// certainty of 0. A typical random pair, at the median distance, ends up
+	// at 1 - median/p99, which is still around 0.2 for most models, so
+	// related words need a clearly higher certainty than that.
+	calibrationPercentile = 99
+)
+
+// DistanceToCertainty converts a distance to a certainty between 0 and 1
+func (c CertaintyCalibration) DistanceToCertainty(d float32) float32 {
+	certainty := 1 - d/c.MaxDistance
+	if certainty < 0 {
+		return 0
+	}
+
+	if certainty > 1 {
+		return 1
+	}
+
+	return certainty
+}
+
+// CertaintyToDistance is the inverse of DistanceToCertainty, it returns the
+// max distance a result may have to match the certainty.
+func (c CertaintyCalibration) CertaintyToDistance(certainty float32) float32 {
+	return (1 - certainty) * c.MaxDistance
+}
+
+// defaultCalibration is used when the distances of a model can't be sampled,
+// e.g. because it contains fewer than two words.
+func defaultCalibration(m Metric) CertaintyCalibration {
+	switch m {
+	case MetricCosine:
+		return CertaintyCalibration{MaxDistance: 2}
+	default:
+		return CertaintyCalibration{MaxDistance: 12}
+	}
+}
+
+// sampleCalibration calibrates a model from the distribution of distances
+// between random pairs of its words. The pairs are drawn from a seeded
+// source, so the same model always gets the same calibration.
+func sampleCalibration(m Metric, size int, vectorForItem func(item int) []float32) CertaintyCalibration {
+	if size < 2 {
+		return defaultCalibration(m)
+	}
+
+	random := rand.New(rand.NewSource(1))
+	distances := make([]float32, calibrationSamples)
+	for s := range distances {
+		a := random.Intn(size)
+		b := random.Intn(size - 1)
+		if b >= a {
+			b++ // ensure that a != b
+		}
+
+		distances[s] = m.distance(vectorForItem(a), vectorForItem(b))
+	}
+
+	sort.Slice(distances, func(a, b int) bool { return distances[a] < distances[b] })
+	max := distances[(len(distances)-1)*calibrationPercentile/100]
+	if !(max > 0) {
+		return defaultCalibration(m)
+	}
+
+	return CertaintyCalibration{MaxDistance: max}
+}
@@ -0,0 +1,67 @@
+/*                          _       _
+ *__      _____  __ ___   ___  __ _| |_ ___
+ *\ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+ * \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+ *  \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+ *
+ * Copyright © 2016 - 2019 Weaviate. All rights reserved.
+ * LICENSE: https://github.com/weaviate/weaviate/blob/master/LICENSE
+ * DESIGN & CONCEPT: Bob van Luijt (@bobvanluijt)
+ * CONTACT: hello@weaviate.io
+ */
+package contextionary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCertaintyCalibration(t *testing.T) {
+	calibration := CertaintyCalibration{MaxDistance: 4}
+
+	t.Run("converting distances to certainties", func(t *testing.T) {
+		assert.Equal(t, float32(1), calibration.DistanceToCertainty(0))
+		assert.Equal(t, float32(0.75), calibration.DistanceToCertainty(1))
+		assert.Equal(t, float32(0), calibration.DistanceToCertainty(4))
+	})
+
+	t.Run("certainties are clamped between 0 and 1", func(t *testing.T) {
+		assert.Equal(t, float32(0), calibration.DistanceToCertainty(7))
+		assert.Equal(t, float32(1), calibration.DistanceToCertainty(-0.1))
+	})
+
+	t.Run("converting certainties back to distances", func(t *testing.T) {
+		assert.Equal(t, float32(1), calibration.CertaintyToDistance(0.75))
+		assert.Equal(t, float32(4), calibration.CertaintyToDistance(0))
+	})
+}
+
+func TestSamplingCalibrations(t *testing.T) {
+	vectors := [][]float32{{0, 0}, {3, 4}, {0, 1}, {1, 0}}
+	vectorForItem := func(i int) []float32 { return vectors[i] }
+
+	t.Run("the largest distances determine the max distance", func(t *testing.T) {
+		calibration := sampleCalibration(MetricEuclidean, len(vectors), vectorForItem)
+		assert.InDelta(t, 5, calibration.MaxDistance, 0.0001)
+	})
+
+	t.Run("the calibration is deterministic", func(t *testing.T) {
+		assert.Equal(t,
+			sampleCalibration(MetricCosine, len(vectors), vectorForItem),
+			sampleCalibration(MetricCosine, len(vectors), vectorForItem))
+	})
+
+	t.Run("too few words fall back to the default", func(t *testing.T) {
+		assert.Equal(t, CertaintyCalibration{MaxDistance: 12},
+			sampleCalibration(MetricEuclidean, 1, vectorForItem))
+		assert.Equal(t, CertaintyCalibration{MaxDistance: 2},
+			sampleCalibration(MetricCosine, 0, vectorForItem))
+	})
+
+	t.Run("identical words fall back to the default", func(t *testing.T) {
+		same := func(i int) []float32 { return []float32{1, 1} }
+		assert.Equal(t, CertaintyCalibration{MaxDistance: 12},
+			sampleCalibration(MetricEuclidean, 10, same))
+	})
+}
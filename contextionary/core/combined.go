@@ -12,6 +12,7 @@
 package contextionary
 
 import (
+	"context"
 	"fmt"
 	"sort"
 
@@ -22,6 +23,9 @@ type CombinedIndex struct {
 	indices       []combinedIndex
 	total_size    int
 	vector_length int
+	metric        Metric
+	calibration   CertaintyCalibration
+	sif           SIFParameters
 }
 
 type combinedIndex struct {
@@ -46,6 +50,7 @@ func CombineVectorIndices(indices []Contextionary) (*CombinedIndex, error) {
 	var offset int = 0
 
 	vector_length := indices[0].GetVectorLength()
+	metric := indices[0].Metric()
 
 	for i := 0; i < len(indices); i++ {
 		size := indices[i].GetNumberOfItems()
@@ -63,9 +68,32 @@ func CombineVectorIndices(indices []Contextionary) (*CombinedIndex, error) {
 		if my_length != vector_length {
 			return nil, fmt.Errorf("vector length not equal")
 		}
+
+		// distances of different metrics can't be compared when merging the
+		// results of the individual indices
+		if my_metric := indices[i].Metric(); my_metric != metric {
+			return nil, fmt.Errorf("distance metric not equal: %s vs %s", metric, my_metric)
+		}
+	}
+
+	// the largest index is the most representative of the distance
+	// distribution, the others are usually small additions to it
+	largest := 0
+	for i := range indices {
+		if indices[i].GetNumberOfItems() > indices[largest].GetNumberOfItems() {
+			largest = i
+		}
+	}
+
+	// the occurrences of all indices count towards the probabilities of the
+	// words, the principal component is again that of the largest index
+	sif := SIFParameters{PrincipalComponent: indices[largest].SIFParameters().PrincipalComponent}
+	for i := range indices {
+		sif.TotalOccurrence += indices[i].SIFParameters().TotalOccurrence
 	}
 
-	return &CombinedIndex{indices: combined_indices, total_size: offset, vector_length: vector_length}, nil
+	return &CombinedIndex{indices: combined_indices, total_size: offset, vector_length: vector_length,
+		metric: metric, calibration: indices[largest].CertaintyCalibration(), sif: sif}, nil
 }
 
 // Verify that all the indices are disjoint
@@ -100,6 +128,18 @@ func (ci *CombinedIndex) GetVectorLength() int {
 	return ci.vector_length
 }
 
+func (ci *CombinedIndex) Metric() Metric {
+	return ci.metric
+}
+
+func (ci *CombinedIndex) CertaintyCalibration() CertaintyCalibration {
+	return ci.calibration
+}
+
+func (ci *CombinedIndex) SIFParameters() SIFParameters {
+	return ci.sif
+}
+
 func (ci *CombinedIndex) WordToItemIndex(word string) ItemIndex {
 	for _, item := range ci.indices {
 		item_index := (*item.index).WordToItemIndex(word)
@@ -185,7 +225,7 @@ func (ci *CombinedIndex) GetDistance(a ItemIndex, b ItemIndex) (float32, error)
 		return 0.0, err
 	}
 
-	dist, err := v1.Distance(v2)
+	dist, err := ci.metric.VectorDistance(v1, v2)
 	if err != nil {
 		return 0.0, err
 	}
@@ -261,13 +301,22 @@ func (a *combined_nn_search_results) Remove(i int) {
 // Get the n nearest neighbours of item, examining k trees.
 // Returns an array of indices, and of distances between item and the n-nearest neighbors.
 func (ci *CombinedIndex) GetNnsByVector(vector Vector, n int, k int) ([]ItemIndex, []float32, error) {
+	return ci.GetNnsByVectorContext(context.Background(), vector, n, k)
+}
+
+// GetNnsByVectorContext is GetNnsByVector, but stops with ctx.Err() once
+// ctx is done, no matter which of the combined indices was searching.
+func (ci *CombinedIndex) GetNnsByVectorContext(ctx context.Context, vector Vector, n int, k int) ([]ItemIndex, []float32, error) {
 	results := combined_nn_search_results{
 		items: make([]combined_nn_search_result, 0),
 		ci:    ci,
 	}
 
 	for _, item := range ci.indices {
-		indices, floats, err := (*item.index).GetNnsByVector(vector, n, k)
+		indices, floats, err := (*item.index).GetNnsByVectorContext(ctx, vector, n, k)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, nil, ctxErr
+		}
 		if err != nil {
 			return nil, nil, errors.NewInternalf(err.Error())
 		} else {
@@ -13,6 +13,8 @@
 // Package contextionary provides the toolset to add context to words.
 package contextionary
 
+import "context"
+
 // ItemIndex is an opaque type that models an index number used to identify a
 // word.
 type ItemIndex int
@@ -33,6 +35,18 @@ type Contextionary interface {
 	// Returns the length of the used vectors.
 	GetVectorLength() int
 
+	// Returns the distance metric of the model. All distances returned by
+	// the contextionary are in this metric.
+	Metric() Metric
+
+	// Returns the calibration to translate the distances of the model into
+	// certainties.
+	CertaintyCalibration() CertaintyCalibration
+
+	// Returns the parameters of the model to weigh the words of sentences
+	// by their smooth inverse frequency.
+	SIFParameters() SIFParameters
+
 	// Look up a word, return an index.
 	// Check for presence of the index with index.IsPresent()
 	WordToItemIndex(word string) ItemIndex
@@ -60,6 +74,11 @@ type Contextionary interface {
 	// Returns an array of indices, and of distances between item and the n-nearest neighbors.
 	GetNnsByVector(vector Vector, n, k int) ([]ItemIndex, []float32, error)
 
+	// GetNnsByVectorContext is GetNnsByVector, but stops with ctx.Err() once
+	// ctx is done. The annoy trees can't be interrupted, so annoy indices
+	// only check ctx before they search.
+	GetNnsByVectorContext(ctx context.Context, vector Vector, n, k int) ([]ItemIndex, []float32, error)
+
 	// SafeGetSimilarWords returns n similar words in the contextionary,
 	// examining k trees. It is guaratueed to have results, even if the word is
 	// not in the contextionary. In this case the list only contains the word
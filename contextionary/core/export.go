@@ -0,0 +1,231 @@
+/*                          _       _
+ *__      _____  __ ___   ___  __ _| |_ ___
+ *\ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+ * \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+ *  \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+ *
+ * Copyright © 2016 - 2019 Weaviate. All rights reserved.
+ * LICENSE: https://github.com/weaviate/weaviate/blob/master/LICENSE
+ * DESIGN & CONCEPT: Bob van Luijt (@bobvanluijt)
+ * CONTACT: hello@weaviate.io
+ */
+package contextionary
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ExportFilter selects the words which are exported by ExportVectors
+type ExportFilter struct {
+	// OccurrencePercentile excludes all words which occur less often than the
+	// specified percentile of all occurrences. 0 exports all words.
+	OccurrencePercentile int
+
+	// Prefix only exports words starting with it
+	Prefix string
+}
+
+// VectorExporter writes exported vectors in a specific file format
+type VectorExporter interface {
+	// Begin is called once before the first vector. Formats with a header
+	// need to know the number of vectors up front.
+	Begin(count, dimensions int) error
+	Write(word string, occurrence uint64, vector []float32) error
+	// End is called once after the last vector, it flushes all buffered output
+	End() error
+}
+
+// ExportVectors streams all words of the contextionary, which match the
+// filter, into the exporter. It returns the number of exported words. The
+// words are iterated twice, first to count them and then to write them, so
+// that nothing but the current vector needs to be kept in memory.
+func ExportVectors(c Contextionary, exporter VectorExporter, filter ExportFilter) (int, error) {
+	if filter.OccurrencePercentile < 0 || filter.OccurrencePercentile > 100 {
+		return 0, fmt.Errorf("occurrence percentile must be between 0 and 100, got %d", filter.OccurrencePercentile)
+	}
+
+	var minOccurrence uint64
+	if filter.OccurrencePercentile > 0 {
+		minOccurrence = c.OccurrencePercentile(filter.OccurrencePercentile)
+	}
+
+	matches := func(item ItemIndex) (string, uint64, bool, error) {
+		word, err := c.ItemIndexToWord(item)
+		if err != nil {
+			return "", 0, false, fmt.Errorf("word of item %d: %v", item, err)
+		}
+
+		if !strings.HasPrefix(word, filter.Prefix) {
+			return word, 0, false, nil
+		}
+
+		occurrence, err := c.ItemIndexToOccurrence(item)
+		if err != nil {
+			return "", 0, false, fmt.Errorf("occurrence of item %d: %v", item, err)
+		}
+
+		return word, occurrence, occurrence >= minOccurrence, nil
+	}
+
+	size := c.GetNumberOfItems()
+	count := 0
+	for item := ItemIndex(0); int(item) < size; item++ {
+		_, _, ok, err := matches(item)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			count++
+		}
+	}
+
+	if err := exporter.Begin(count, c.GetVectorLength()); err != nil {
+		return 0, err
+	}
+
+	for item := ItemIndex(0); int(item) < size; item++ {
+		word, occurrence, ok, err := matches(item)
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			continue
+		}
+
+		vector, err := c.GetVectorForItemIndex(item)
+		if err != nil {
+			return 0, fmt.Errorf("vector of item %d: %v", item, err)
+		}
+
+		if err := exporter.Write(word, occurrence, vector.vector); err != nil {
+			return 0, err
+		}
+	}
+
+	return count, exporter.End()
+}
+
+type gloveExporter struct {
+	w *bufio.Writer
+}
+
+// NewGloveExporter writes 'word occurrence v1 ... vn' per line. This is the
+// GloVe text format with a count column, which the generator can read back
+// with --count-column.
+func NewGloveExporter(w io.Writer) VectorExporter {
+	return &gloveExporter{w: bufio.NewWriter(w)}
+}
+
+func (g *gloveExporter) Begin(count, dimensions int) error {
+	return nil
+}
+
+func (g *gloveExporter) Write(word string, occurrence uint64, vector []float32) error {
+	if _, err := fmt.Fprintf(g.w, "%s %d", word, occurrence); err != nil {
+		return err
+	}
+
+	for _, v := range vector {
+		if _, err := fmt.Fprintf(g.w, " %g", v); err != nil {
+			return err
+		}
+	}
+
+	return g.w.WriteByte('\n')
+}
+
+func (g *gloveExporter) End() error {
+	return g.w.Flush()
+}
+
+type word2VecExporter struct {
+	w *bufio.Writer
+}
+
+// NewWord2VecExporter writes the binary word2vec format. The format has no
+// place for the occurrences, they are dropped.
+func NewWord2VecExporter(w io.Writer) VectorExporter {
+	return &word2VecExporter{w: bufio.NewWriter(w)}
+}
+
+func (e *word2VecExporter) Begin(count, dimensions int) error {
+	_, err := fmt.Fprintf(e.w, "%d %d\n", count, dimensions)
+	return err
+}
+
+func (e *word2VecExporter) Write(word string, occurrence uint64, vector []float32) error {
+	if _, err := e.w.WriteString(word + " "); err != nil {
+		return err
+	}
+
+	if err := binary.Write(e.w, binary.LittleEndian, vector); err != nil {
+		return err
+	}
+
+	return e.w.WriteByte('\n')
+}
+
+func (e *word2VecExporter) End() error {
+	return e.w.Flush()
+}
+
+type numpyExporter struct {
+	vectors *bufio.Writer
+	vocab   *bufio.Writer
+}
+
+// NewNumpyExporter writes the vectors as a float32 matrix in the NumPy .npy
+// format and the words to vocab, one 'word occurrence' pair per line in the
+// same order as the rows of the matrix.
+func NewNumpyExporter(vectors io.Writer, vocab io.Writer) VectorExporter {
+	return &numpyExporter{vectors: bufio.NewWriter(vectors), vocab: bufio.NewWriter(vocab)}
+}
+
+// npyHeaderAlignment is the alignment of the data in .npy files, see
+// https://numpy.org/doc/stable/reference/generated/numpy.lib.format.html
+const npyHeaderAlignment = 64
+
+func (n *numpyExporter) Begin(count, dimensions int) error {
+	header := fmt.Sprintf("{'descr': '<f4', 'fortran_order': False, 'shape': (%d, %d), }", count, dimensions)
+
+	// magic (6 bytes), version (2 bytes) and header length (2 bytes) precede
+	// the header, which is padded with spaces and terminated by a newline
+	prefixLength := 10
+	padding := npyHeaderAlignment - (prefixLength+len(header)+1)%npyHeaderAlignment
+	if padding == npyHeaderAlignment {
+		padding = 0
+	}
+	header += strings.Repeat(" ", padding) + "\n"
+
+	if _, err := n.vectors.WriteString("\x93NUMPY\x01\x00"); err != nil {
+		return err
+	}
+
+	if err := binary.Write(n.vectors, binary.LittleEndian, uint16(len(header))); err != nil {
+		return err
+	}
+
+	_, err := n.vectors.WriteString(header)
+	return err
+}
+
+func (n *numpyExporter) Write(word string, occurrence uint64, vector []float32) error {
+	if err := binary.Write(n.vectors, binary.LittleEndian, vector); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintf(n.vocab, "%s %d\n", word, occurrence)
+	return err
+}
+
+func (n *numpyExporter) End() error {
+	if err := n.vectors.Flush(); err != nil {
+		return err
+	}
+
+	return n.vocab.Flush()
+}
@@ -0,0 +1,96 @@
+/*                          _       _
+ *__      _____  __ ___   ___  __ _| |_ ___
+ *\ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+ * \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+ *  \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+ *
+ * Copyright © 2016 - 2019 Weaviate. All rights reserved.
+ * LICENSE: https://github.com/weaviate/weaviate/blob/master/LICENSE
+ * DESIGN & CONCEPT: Bob van Luijt (@bobvanluijt)
+ * CONTACT: hello@weaviate.io
+ */
+package main
+
+import (
+	"io"
+	"log"
+	"os"
+
+	flags "github.com/jessevdk/go-flags"
+	contextionary "github.com/weaviate/contextionary/contextionary/core"
+)
+
+type Options struct {
+	KNNFile              string `short:"k" long:"knn-file" description:"Path to the .knn file of the contextionary" required:"true"`
+	IDXFile              string `short:"i" long:"idx-file" description:"Path to the .idx file of the contextionary" required:"true"`
+	Format               string `short:"f" long:"format" description:"The format of the export" choice:"glove" choice:"word2vec" choice:"npy" default:"glove"`
+	Output               string `short:"o" long:"output" description:"Path of the exported vectors, - for stdout" default:"-"`
+	VocabOutput          string `long:"vocab-output" description:"Path of the vocab file of the npy format, defaults to the output path with a .vocab extension"`
+	OccurrencePercentile int    `long:"occurrence-percentile" description:"Only export words which occur at least as often as this percentile of all words" default:"0"`
+	Prefix               string `long:"prefix" description:"Only export words starting with this prefix"`
+}
+
+func main() {
+	var options Options
+	var parser = flags.NewParser(&options, flags.Default)
+
+	if _, err := parser.Parse(); err != nil {
+		if flagsErr, ok := err.(*flags.Error); ok && flagsErr.Type == flags.ErrHelp {
+			os.Exit(0)
+		} else {
+			os.Exit(1)
+		}
+	}
+
+	c11y, err := contextionary.LoadVectorFromDisk(options.KNNFile, options.IDXFile)
+	if err != nil {
+		log.Fatalf("Could not load contextionary: %v", err)
+	}
+
+	output, err := create(options.Output)
+	if err != nil {
+		log.Fatalf("Could not create output file: %v", err)
+	}
+	defer output.Close()
+
+	var exporter contextionary.VectorExporter
+	switch options.Format {
+	case "word2vec":
+		exporter = contextionary.NewWord2VecExporter(output)
+	case "npy":
+		vocabPath := options.VocabOutput
+		if vocabPath == "" {
+			if options.Output == "-" {
+				log.Fatal("The npy format needs a --vocab-output if the vectors are written to stdout")
+			}
+			vocabPath = options.Output + ".vocab"
+		}
+
+		vocab, err := create(vocabPath)
+		if err != nil {
+			log.Fatalf("Could not create vocab file: %v", err)
+		}
+		defer vocab.Close()
+		exporter = contextionary.NewNumpyExporter(output, vocab)
+	default:
+		exporter = contextionary.NewGloveExporter(output)
+	}
+
+	count, err := contextionary.ExportVectors(c11y, exporter, contextionary.ExportFilter{
+		OccurrencePercentile: options.OccurrencePercentile,
+		Prefix:               options.Prefix,
+	})
+	if err != nil {
+		log.Fatalf("Could not export vectors: %v", err)
+	}
+
+	log.Printf("Exported %d words", count)
+}
+
+func create(path string) (io.WriteCloser, error) {
+	if path == "-" {
+		return os.Stdout, nil
+	}
+
+	return os.Create(path)
+}
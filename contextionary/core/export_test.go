@@ -0,0 +1,98 @@
+/*                          _       _
+ *__      _____  __ ___   ___  __ _| |_ ___
+ *\ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+ * \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+ *  \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+ *
+ * Copyright © 2016 - 2019 Weaviate. All rights reserved.
+ * LICENSE: https://github.com/weaviate/weaviate/blob/master/LICENSE
+ * DESIGN & CONCEPT: Bob van Luijt (@bobvanluijt)
+ * CONTACT: hello@weaviate.io
+ */
+package contextionary
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weaviate/contextionary/contextionary/core/generator"
+)
+
+func TestExportingVectors(t *testing.T) {
+	tempdir, err := ioutil.TempDir("", "weaviate-vector-test")
+	require.Nil(t, err)
+	defer os.RemoveAll(tempdir)
+
+	dataset := "apple 30 1 0.5\napplication 10 0 1\npie 20 -1 2\n"
+	c11y := generateFromDataset(t, tempdir, dataset, generator.Options{CountColumn: true})
+
+	t.Run("glove with all words", func(t *testing.T) {
+		var buf bytes.Buffer
+		count, err := ExportVectors(c11y, NewGloveExporter(&buf), ExportFilter{})
+		require.Nil(t, err)
+		assert.Equal(t, 3, count)
+		assert.Equal(t, dataset, buf.String())
+	})
+
+	t.Run("glove with a prefix", func(t *testing.T) {
+		var buf bytes.Buffer
+		count, err := ExportVectors(c11y, NewGloveExporter(&buf), ExportFilter{Prefix: "app"})
+		require.Nil(t, err)
+		assert.Equal(t, 2, count)
+		assert.Equal(t, "apple 30 1 0.5\napplication 10 0 1\n", buf.String())
+	})
+
+	t.Run("glove with an occurrence percentile", func(t *testing.T) {
+		var buf bytes.Buffer
+		count, err := ExportVectors(c11y, NewGloveExporter(&buf), ExportFilter{OccurrencePercentile: 100})
+		require.Nil(t, err)
+		assert.Equal(t, 1, count)
+		assert.Equal(t, "apple 30 1 0.5\n", buf.String())
+	})
+
+	t.Run("an invalid occurrence percentile", func(t *testing.T) {
+		_, err := ExportVectors(c11y, NewGloveExporter(&bytes.Buffer{}), ExportFilter{OccurrencePercentile: 101})
+		assert.NotNil(t, err)
+	})
+
+	t.Run("word2vec can be read by the generator", func(t *testing.T) {
+		var buf bytes.Buffer
+		_, err := ExportVectors(c11y, NewWord2VecExporter(&buf), ExportFilter{})
+		require.Nil(t, err)
+
+		otherdir, err := ioutil.TempDir("", "weaviate-vector-test")
+		require.Nil(t, err)
+		defer os.RemoveAll(otherdir)
+
+		imported := generateFromDataset(t, otherdir, buf.String(), generator.Options{})
+		require.Equal(t, 3, imported.GetNumberOfItems())
+		for item := ItemIndex(0); item < 3; item++ {
+			expected, _ := c11y.GetVectorForItemIndex(item)
+			actual, _ := imported.GetVectorForItemIndex(item)
+			assert.Equal(t, expected.ToArray(), actual.ToArray())
+		}
+	})
+
+	t.Run("npy", func(t *testing.T) {
+		var vectors, vocab bytes.Buffer
+		_, err := ExportVectors(c11y, NewNumpyExporter(&vectors, &vocab), ExportFilter{})
+		require.Nil(t, err)
+
+		assert.Equal(t, "apple 30\napplication 10\npie 20\n", vocab.String())
+
+		content := vectors.Bytes()
+		require.True(t, bytes.HasPrefix(content, []byte("\x93NUMPY\x01\x00")))
+		headerLength := int(binary.LittleEndian.Uint16(content[8:10]))
+		assert.Equal(t, 0, (10+headerLength)%64, "data must be aligned")
+		assert.Contains(t, string(content[10:10+headerLength]), "'shape': (3, 2)")
+
+		data := make([]float32, 6)
+		require.Nil(t, binary.Read(bytes.NewReader(content[10+headerLength:]), binary.LittleEndian, data))
+		assert.Equal(t, []float32{1, 0.5, 0, 1, -1, 2}, data)
+	})
+}
@@ -0,0 +1,45 @@
+/*                          _       _
+ *__      _____  __ ___   ___  __ _| |_ ___
+ *\ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+ * \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+ *  \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+ *
+ * Copyright © 2016 - 2019 Weaviate. All rights reserved.
+ * LICENSE: https://github.com/weaviate/weaviate/blob/master/LICENSE
+ * DESIGN & CONCEPT: Bob van Luijt (@bobvanluijt)
+ * CONTACT: hello@weaviate.io
+ */
+
+// Package fingerprint identifies the files of a model, so that sidecar files
+// which are derived from them, such as the HNSW graph or the quantized
+// vectors, can tell whether they were built for the same files.
+package fingerprint
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"os"
+)
+
+// Files fingerprints the files by their sizes and modification times, so it
+// is cheap even for large files. A file which is replaced, e.g. by a
+// retrained model, changes the fingerprint even if it has the same size.
+// Copying a file without preserving its modification time changes it as
+// well, which only causes an unnecessary rebuild of the sidecar files.
+func Files(paths ...string) (uint64, error) {
+	h := fnv.New64a()
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return 0, fmt.Errorf("fingerprint %s: %v", path, err)
+		}
+
+		var buf [16]byte
+		binary.LittleEndian.PutUint64(buf[0:8], uint64(info.Size()))
+		binary.LittleEndian.PutUint64(buf[8:16], uint64(info.ModTime().UnixNano()))
+		h.Write(buf[:])
+	}
+
+	return h.Sum64(), nil
+}
@@ -0,0 +1,58 @@
+/*                          _       _
+ *__      _____  __ ___   ___  __ _| |_ ___
+ *\ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+ * \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+ *  \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+ *
+ * Copyright © 2016 - 2019 Weaviate. All rights reserved.
+ * LICENSE: https://github.com/weaviate/weaviate/blob/master/LICENSE
+ * DESIGN & CONCEPT: Bob van Luijt (@bobvanluijt)
+ * CONTACT: hello@weaviate.io
+ */
+package fingerprint
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFingerprintingFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fingerprint")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	knn := filepath.Join(dir, "contextionary.knn")
+	idx := filepath.Join(dir, "contextionary.idx")
+	require.Nil(t, ioutil.WriteFile(knn, []byte("vectors"), 0644))
+	require.Nil(t, ioutil.WriteFile(idx, []byte("words"), 0644))
+
+	before, err := Files(knn, idx)
+	require.Nil(t, err)
+
+	t.Run("unchanged files", func(t *testing.T) {
+		again, err := Files(knn, idx)
+		require.Nil(t, err)
+		assert.Equal(t, before, again)
+	})
+
+	t.Run("a replaced file of the same size", func(t *testing.T) {
+		require.Nil(t, ioutil.WriteFile(knn, []byte("VECTORS"), 0644))
+		later := time.Now().Add(time.Minute)
+		require.Nil(t, os.Chtimes(knn, later, later))
+
+		after, err := Files(knn, idx)
+		require.Nil(t, err)
+		assert.NotEqual(t, before, after)
+	})
+
+	t.Run("a missing file", func(t *testing.T) {
+		_, err := Files(knn, filepath.Join(dir, "missing"))
+		assert.NotNil(t, err)
+	})
+}
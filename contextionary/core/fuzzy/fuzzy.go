@@ -0,0 +1,234 @@
+/*                          _       _
+ *__      _____  __ ___   ___  __ _| |_ ___
+ *\ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+ * \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+ *  \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+ *
+ * Copyright © 2016 - 2019 Weaviate. All rights reserved.
+ * LICENSE: https://github.com/weaviate/weaviate/blob/master/LICENSE
+ * DESIGN & CONCEPT: Bob van Luijt (@bobvanluijt)
+ * CONTACT: hello@weaviate.io
+ */
+
+// Package fuzzy finds the words of a wordlist within a maximum edit
+// distance of a possibly misspelled word.
+//
+// The index maps every character bigram to the words which contain it. The
+// words are padded with a start and an end marker, so that the first and last
+// characters have bigrams of their own. A single insertion, deletion or
+// substitution removes at most two bigrams of a word, so a word within k
+// edits of the query shares at least all but 2k of the query's distinct
+// bigrams. Only the words which pass this count filter and whose length is
+// within k of the query's are compared with the exact edit distance. A
+// BK-tree or a deletion index would be the textbook alternatives, but the
+// former visits a large part of the tree for two edits and the latter
+// needs several times the memory of the wordlist.
+package fuzzy
+
+import (
+	"sort"
+	"sync"
+)
+
+const (
+	startMarker rune = 0
+	endMarker   rune = 1
+)
+
+// Match is a word of the index within the maximum edit distance of a query
+type Match struct {
+	// ID is the position of the word in the list the index was built from
+	ID       int
+	Word     string
+	Distance int
+}
+
+type bigram [2]rune
+
+// Index over a wordlist, it is safe for concurrent searches
+type Index struct {
+	words    []string
+	lengths  []int32
+	postings map[bigram][]int32
+
+	// words which are too short for the count filter are found by length
+	byLength map[int32][]int32
+
+	// counts of shared bigrams per word, one per concurrent search
+	counts sync.Pool
+}
+
+// NewIndex builds the index over all words, the IDs of the matches are the
+// positions of the words in the list
+func NewIndex(words []string) *Index {
+	i := &Index{
+		words:    words,
+		lengths:  make([]int32, len(words)),
+		postings: map[bigram][]int32{},
+		byLength: map[int32][]int32{},
+	}
+	i.counts.New = func() interface{} {
+		return make([]int32, len(words))
+	}
+
+	for id, word := range words {
+		runes := []rune(word)
+		i.lengths[id] = int32(len(runes))
+		i.byLength[int32(len(runes))] = append(i.byLength[int32(len(runes))], int32(id))
+		for _, b := range bigrams(runes) {
+			i.postings[b] = append(i.postings[b], int32(id))
+		}
+	}
+
+	return i
+}
+
+// bigrams returns the distinct bigrams of the padded word
+func bigrams(word []rune) []bigram {
+	padded := make([]rune, 0, len(word)+2)
+	padded = append(padded, startMarker)
+	padded = append(padded, word...)
+	padded = append(padded, endMarker)
+
+	seen := make(map[bigram]struct{}, len(padded)-1)
+	out := make([]bigram, 0, len(padded)-1)
+	for j := 0; j+1 < len(padded); j++ {
+		b := bigram{padded[j], padded[j+1]}
+		if _, ok := seen[b]; ok {
+			continue
+		}
+		seen[b] = struct{}{}
+		out = append(out, b)
+	}
+
+	return out
+}
+
+// Len is the number of words in the index
+func (i *Index) Len() int {
+	return len(i.words)
+}
+
+// Search returns all words within maxEdits insertions, deletions or
+// substitutions of word, sorted by ascending distance
+func (i *Index) Search(word string, maxEdits int) []Match {
+	query := []rune(word)
+	d := &distancer{}
+
+	var matches []Match
+	for _, id := range i.candidates(query, maxEdits) {
+		distance := d.levenshtein(query, []rune(i.words[id]), maxEdits)
+		if distance <= maxEdits {
+			matches = append(matches, Match{ID: int(id), Word: i.words[id], Distance: distance})
+		}
+	}
+
+	sort.Slice(matches, func(a, b int) bool {
+		if matches[a].Distance != matches[b].Distance {
+			return matches[a].Distance < matches[b].Distance
+		}
+		return matches[a].ID < matches[b].ID
+	})
+
+	return matches
+}
+
+func (i *Index) candidates(query []rune, maxEdits int) []int32 {
+	minLength, maxLength := int32(len(query)-maxEdits), int32(len(query)+maxEdits)
+
+	grams := bigrams(query)
+	threshold := int32(len(grams) - 2*maxEdits)
+	if threshold <= 0 {
+		// every word of a suitable length passes the count filter
+		var out []int32
+		for length := minLength; length <= maxLength; length++ {
+			out = append(out, i.byLength[length]...)
+		}
+		return out
+	}
+
+	counts := i.counts.Get().([]int32)
+	defer i.counts.Put(counts)
+
+	var touched []int32
+	for _, b := range grams {
+		for _, id := range i.postings[b] {
+			if i.lengths[id] < minLength || i.lengths[id] > maxLength {
+				continue
+			}
+
+			if counts[id] == 0 {
+				touched = append(touched, id)
+			}
+			counts[id]++
+		}
+	}
+
+	var out []int32
+	for _, id := range touched {
+		if counts[id] >= threshold {
+			out = append(out, id)
+		}
+		counts[id] = 0
+	}
+
+	return out
+}
+
+// distancer reuses the row of the distance matrix across computations, it
+// must not be shared between goroutines
+type distancer struct {
+	row []int
+}
+
+// levenshtein is the number of single character insertions, deletions and
+// substitutions to turn a into b. The computation stops early once the
+// distance is known to exceed max, any value above max is returned then.
+func (d *distancer) levenshtein(a, b []rune, max int) int {
+	if len(a) < len(b) {
+		a, b = b, a
+	}
+
+	if len(a)-len(b) > max {
+		return max + 1
+	}
+
+	if cap(d.row) < len(b)+1 {
+		d.row = make([]int, len(b)+1)
+	}
+	row := d.row[:len(b)+1]
+	for j := range row {
+		row[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		diagonal := row[0]
+		row[0] = i
+		rowMin := row[0]
+		for j := 1; j <= len(b); j++ {
+			above := row[j]
+			value := diagonal
+			if a[i-1] != b[j-1] {
+				value++
+			}
+			if row[j]+1 < value {
+				value = row[j] + 1
+			}
+			if row[j-1]+1 < value {
+				value = row[j-1] + 1
+			}
+
+			row[j] = value
+			diagonal = above
+			if value < rowMin {
+				rowMin = value
+			}
+		}
+
+		if rowMin > max {
+			return max + 1
+		}
+	}
+
+	return row[len(b)]
+}
@@ -0,0 +1,116 @@
+/*                          _       _
+ *__      _____  __ ___   ___  __ _| |_ ___
+ *\ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+ * \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+ *  \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+ *
+ * Copyright © 2016 - 2019 Weaviate. All rights reserved.
+ * LICENSE: https://github.com/weaviate/weaviate/blob/master/LICENSE
+ * DESIGN & CONCEPT: Bob van Luijt (@bobvanluijt)
+ * CONTACT: hello@weaviate.io
+ */
+package fuzzy
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func levenshtein(a, b []rune) int {
+	return (&distancer{}).levenshtein(a, b, len(a)+len(b))
+}
+
+func TestLevenshtein(t *testing.T) {
+	for _, test := range []struct {
+		a, b     string
+		distance int
+	}{
+		{"", "", 0},
+		{"car", "", 3},
+		{"car", "car", 0},
+		{"car", "cat", 1},
+		{"car", "cart", 1},
+		{"mercedes", "mrecedes", 2},
+		{"kitten", "sitting", 3},
+		{"café", "cafe", 1},
+	} {
+		assert.Equal(t, test.distance, levenshtein([]rune(test.a), []rune(test.b)), "%s - %s", test.a, test.b)
+		assert.Equal(t, test.distance, levenshtein([]rune(test.b), []rune(test.a)), "%s - %s", test.b, test.a)
+	}
+
+	t.Run("stopping early", func(t *testing.T) {
+		d := &distancer{}
+		assert.Equal(t, 2, d.levenshtein([]rune("kitten"), []rune("sitting"), 1))
+		assert.Equal(t, 2, d.levenshtein([]rune("car"), []rune("carrier"), 1))
+	})
+}
+
+func TestSearch(t *testing.T) {
+	words := []string{"car", "cart", "cat", "mercedes", "city", "carrier"}
+	index := NewIndex(words)
+	assert.Equal(t, 6, index.Len())
+
+	t.Run("a typo", func(t *testing.T) {
+		assert.Equal(t, []Match{{ID: 3, Word: "mercedes", Distance: 1}}, index.Search("mercedez", 2))
+	})
+
+	t.Run("sorted by distance", func(t *testing.T) {
+		assert.Equal(t, []Match{
+			{ID: 0, Word: "car", Distance: 0},
+			{ID: 1, Word: "cart", Distance: 1},
+			{ID: 2, Word: "cat", Distance: 1},
+		}, index.Search("car", 1))
+	})
+
+	t.Run("nothing within the maximum distance", func(t *testing.T) {
+		assert.Len(t, index.Search("elephant", 2), 0)
+	})
+
+	t.Run("a word which is too short for the count filter", func(t *testing.T) {
+		assert.Equal(t, []Match{
+			{ID: 0, Word: "car", Distance: 1},
+			{ID: 2, Word: "cat", Distance: 1},
+			{ID: 1, Word: "cart", Distance: 2},
+		}, index.Search("ca", 2))
+	})
+
+	t.Run("an empty index", func(t *testing.T) {
+		assert.Len(t, NewIndex(nil).Search("car", 2), 0)
+	})
+}
+
+func TestSearchFindsTheSameAsAScan(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	randomWord := func() string {
+		out := make([]rune, 2+r.Intn(6))
+		for i := range out {
+			out[i] = rune('a' + r.Intn(5))
+		}
+		return string(out)
+	}
+
+	words := make([]string, 2000)
+	for i := range words {
+		words[i] = randomWord()
+	}
+	index := NewIndex(words)
+
+	for i := 0; i < 50; i++ {
+		query := randomWord()
+		found := map[string]bool{}
+		for _, match := range index.Search(query, 2) {
+			found[match.Word] = true
+		}
+
+		expected := map[string]bool{}
+		for _, word := range words {
+			if levenshtein([]rune(query), []rune(word)) <= 2 {
+				expected[word] = true
+			}
+		}
+
+		assert.Equal(t, expected, found, "query %s", query)
+	}
+}
@@ -0,0 +1,348 @@
+/*                          _       _
+ *__      _____  __ ___   ___  __ _| |_ ___
+ *\ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+ * \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+ *  \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+ *
+ * Copyright © 2016 - 2019 Weaviate. All rights reserved.
+ * LICENSE: https://github.com/weaviate/weaviate/blob/master/LICENSE
+ * DESIGN & CONCEPT: Bob van Luijt (@bobvanluijt)
+ * CONTACT: hello@weaviate.io
+ */
+package generator
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// The supported formats of vector files
+const (
+	// FormatAuto detects the format from the content of the file
+	FormatAuto = "auto"
+
+	// FormatGlove is the text output of GloVe: 'word v1 v2 ...' per line,
+	// without a header
+	FormatGlove = "glove"
+
+	// FormatFastText is the .vec text format of fastText. It is the same as
+	// FormatGlove, but starts with a 'count dimensions' header line.
+	FormatFastText = "fasttext"
+
+	// FormatWord2Vec is the binary format of word2vec: a 'count dimensions'
+	// header line followed by the words, each terminated by a space and
+	// followed by the vector as little endian float32s.
+	FormatWord2Vec = "word2vec"
+)
+
+// InputError is a malformed record in a vector file. The record is skipped,
+// reading continues with the next one.
+type InputError struct {
+	File string `json:"file"`
+	// Line is the line of the record in text files and the number of the
+	// record in binary files, both starting at 1
+	Line   int    `json:"line"`
+	Word   string `json:"word,omitempty"`
+	Reason string `json:"reason"`
+}
+
+func (e *InputError) Error() string {
+	if e.Word == "" {
+		return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Reason)
+	}
+
+	return fmt.Sprintf("%s:%d: word '%s': %s", e.File, e.Line, e.Word, e.Reason)
+}
+
+type vectorRecord struct {
+	word   string
+	vector []float32
+
+	// count is only set if the file has a count column
+	count uint64
+}
+
+// vectorReader reads the records of a vector file one by one
+type vectorReader interface {
+	// next returns the next record. It returns io.EOF at the end of the file
+	// and an *InputError for a malformed record, after which reading can
+	// continue. Any other error is fatal.
+	next() (vectorRecord, error)
+}
+
+// newVectorReader opens a reader for file in the specified format. If
+// countColumn is set, the second column of text formats is read as the count
+// of the word.
+func newVectorReader(file *os.File, format string, countColumn bool) (vectorReader, string, error) {
+	r := bufio.NewReaderSize(file, 1024*1024)
+
+	if format == "" || format == FormatAuto {
+		detected, err := detectFormat(r)
+		if err != nil {
+			return nil, "", fmt.Errorf("detect format of %s: %v", file.Name(), err)
+		}
+		format = detected
+	}
+
+	switch format {
+	case FormatGlove:
+		return newTextReader(r, file.Name(), false, countColumn)
+	case FormatFastText:
+		return newTextReader(r, file.Name(), true, countColumn)
+	case FormatWord2Vec:
+		if countColumn {
+			return nil, "", fmt.Errorf("%s: the %s format has no count column", file.Name(), FormatWord2Vec)
+		}
+		return newWord2VecReader(r, file.Name())
+	default:
+		return nil, "", fmt.Errorf("unsupported format '%s', must be one of '%s', '%s', '%s' or '%s'",
+			format, FormatAuto, FormatGlove, FormatFastText, FormatWord2Vec)
+	}
+}
+
+// detectFormat looks at the start of the file without consuming it. Files
+// starting with a 'count dimensions' header are fastText files if the rest is
+// text and word2vec files otherwise. Everything else is treated as GloVe.
+func detectFormat(r *bufio.Reader) (string, error) {
+	// Peek returns what is available if the file is shorter than the buffer
+	start, err := r.Peek(r.Size())
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return "", err
+	}
+
+	end := strings.IndexByte(string(start), '\n')
+	if end == -1 {
+		return FormatGlove, nil
+	}
+
+	if _, _, ok := parseHeader(string(start[:end])); !ok {
+		return FormatGlove, nil
+	}
+
+	if looksLikeText(start[end+1:]) {
+		return FormatFastText, nil
+	}
+
+	return FormatWord2Vec, nil
+}
+
+func parseHeader(line string) (int, int, bool) {
+	parts := strings.Fields(line)
+	if len(parts) != 2 {
+		return 0, 0, false
+	}
+
+	count, err := strconv.Atoi(parts[0])
+	if err != nil || count < 0 {
+		return 0, 0, false
+	}
+
+	dims, err := strconv.Atoi(parts[1])
+	if err != nil || dims < 1 {
+		return 0, 0, false
+	}
+
+	return count, dims, true
+}
+
+// looksLikeText is false if the content contains control characters or
+// invalid utf-8, which is all but certain for binary float32s
+func looksLikeText(content []byte) bool {
+	// the buffer might end in the middle of a multi byte character
+	for len(content) > 0 && !utf8.Valid(content) && len(content) > utf8.UTFMax {
+		content = content[:len(content)-1]
+	}
+
+	if !utf8.Valid(content) {
+		return false
+	}
+
+	for _, b := range content {
+		if b < 0x20 && b != '\n' && b != '\r' && b != '\t' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// textReader reads the GloVe and fastText formats
+type textReader struct {
+	scanner     *bufio.Scanner
+	file        string
+	line        int
+	dimensions  int
+	countColumn bool
+}
+
+func newTextReader(r io.Reader, file string, header bool, countColumn bool) (vectorReader, string, error) {
+	scanner := bufio.NewScanner(r)
+	// lines of high dimensional vectors easily exceed the default limit
+	scanner.Buffer(make([]byte, 1024*1024), 64*1024*1024)
+
+	t := &textReader{scanner: scanner, file: file, dimensions: -1, countColumn: countColumn}
+	format := FormatGlove
+	if header {
+		format = FormatFastText
+		if !scanner.Scan() {
+			return nil, "", fmt.Errorf("%s: missing header line", file)
+		}
+		t.line++
+
+		_, dims, ok := parseHeader(scanner.Text())
+		if !ok {
+			return nil, "", fmt.Errorf("%s: invalid header line '%s', expected 'count dimensions'", file, scanner.Text())
+		}
+		t.dimensions = dims
+	}
+
+	return t, format, nil
+}
+
+func (t *textReader) next() (vectorRecord, error) {
+	if !t.scanner.Scan() {
+		if err := t.scanner.Err(); err != nil {
+			return vectorRecord{}, err
+		}
+		return vectorRecord{}, io.EOF
+	}
+	t.line++
+
+	// fastText ends every line with a space
+	parts := strings.Split(strings.TrimRight(t.scanner.Text(), " \r"), " ")
+	word := parts[0]
+	if word == "" {
+		return vectorRecord{}, t.error("", "empty word")
+	}
+
+	// the first column is always the word, optionally followed by the count
+	firstValue := 1
+	if t.countColumn {
+		firstValue = 2
+	}
+
+	if t.dimensions == -1 {
+		t.dimensions = len(parts) - firstValue
+	}
+
+	if len(parts)-firstValue != t.dimensions {
+		return vectorRecord{}, t.error(word, fmt.Sprintf("expected %d values, but found %d",
+			t.dimensions, len(parts)-firstValue))
+	}
+
+	record := vectorRecord{word: word, vector: make([]float32, t.dimensions)}
+	if t.countColumn {
+		count, err := parseCount(parts[1])
+		if err != nil {
+			return vectorRecord{}, t.error(word, err.Error())
+		}
+		record.count = count
+	}
+
+	for i := range record.vector {
+		value, err := strconv.ParseFloat(parts[firstValue+i], 32)
+		if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+			return vectorRecord{}, t.error(word, fmt.Sprintf("invalid value '%s' at position %d", parts[firstValue+i], i))
+		}
+		record.vector[i] = float32(value)
+	}
+
+	return record, nil
+}
+
+func (t *textReader) error(word, reason string) error {
+	return &InputError{File: t.file, Line: t.line, Word: word, Reason: reason}
+}
+
+// word2VecReader reads the binary word2vec format
+type word2VecReader struct {
+	r          *bufio.Reader
+	file       string
+	record     int
+	dimensions int
+	buf        []byte
+}
+
+func newWord2VecReader(r *bufio.Reader, file string) (vectorReader, string, error) {
+	header, err := r.ReadString('\n')
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: missing header line", file)
+	}
+
+	_, dims, ok := parseHeader(header)
+	if !ok {
+		return nil, "", fmt.Errorf("%s: invalid header line '%s', expected 'count dimensions'",
+			file, strings.TrimSpace(header))
+	}
+
+	return &word2VecReader{r: r, file: file, dimensions: dims, buf: make([]byte, 4*dims)}, FormatWord2Vec, nil
+}
+
+func (w *word2VecReader) next() (vectorRecord, error) {
+	// records are usually separated by a newline, but not all writers add one
+	for {
+		b, err := w.r.ReadByte()
+		if err != nil {
+			return vectorRecord{}, err
+		}
+		if b != '\n' {
+			w.r.UnreadByte()
+			break
+		}
+	}
+	w.record++
+
+	word, err := w.r.ReadString(' ')
+	if err != nil {
+		// a truncated file can't be recovered from, so this is the last record
+		return vectorRecord{}, w.truncated(strings.TrimSpace(word))
+	}
+	word = strings.TrimSuffix(word, " ")
+
+	if _, err := io.ReadFull(w.r, w.buf); err != nil {
+		return vectorRecord{}, w.truncated(word)
+	}
+
+	if !utf8.ValidString(word) {
+		return vectorRecord{}, &InputError{File: w.file, Line: w.record, Word: word, Reason: "word is not valid utf-8"}
+	}
+
+	record := vectorRecord{word: word, vector: make([]float32, w.dimensions)}
+	for i := range record.vector {
+		value := math.Float32frombits(binary.LittleEndian.Uint32(w.buf[4*i:]))
+		if math.IsNaN(float64(value)) || math.IsInf(float64(value), 0) {
+			return vectorRecord{}, &InputError{File: w.file, Line: w.record, Word: word,
+				Reason: fmt.Sprintf("invalid value %f at position %d", value, i)}
+		}
+		record.vector[i] = value
+	}
+
+	return record, nil
+}
+
+func (w *word2VecReader) truncated(word string) error {
+	// make sure the next call ends the file
+	w.r = bufio.NewReader(strings.NewReader(""))
+	return &InputError{File: w.file, Line: w.record, Word: word, Reason: "file ends in the middle of the record"}
+}
+
+// formatFromExtension is used to log a hint if the detected format doesn't
+// match the file extension
+func formatFromExtension(path string) string {
+	switch filepath.Ext(path) {
+	case ".bin":
+		return FormatWord2Vec
+	case ".vec":
+		return FormatFastText
+	default:
+		return ""
+	}
+}
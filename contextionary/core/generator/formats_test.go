@@ -0,0 +1,160 @@
+/*                          _       _
+ *__      _____  __ ___   ___  __ _| |_ ___
+ *\ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+ * \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+ *  \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+ *
+ * Copyright © 2016 - 2019 Weaviate. All rights reserved.
+ * LICENSE: https://github.com/weaviate/weaviate/blob/master/LICENSE
+ * DESIGN & CONCEPT: Bob van Luijt (@bobvanluijt)
+ * CONTACT: hello@weaviate.io
+ */
+package generator
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func word2VecFile(header string, words []string, vectors [][]float32) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(header)
+	for i, word := range words {
+		buf.WriteString(word + " ")
+		binary.Write(&buf, binary.LittleEndian, vectors[i])
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+func readAll(t *testing.T, content []byte, format string, countColumn bool) (string, []vectorRecord, []*InputError) {
+	dir, err := ioutil.TempDir("", "generator-test")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "vectors")
+	require.Nil(t, ioutil.WriteFile(path, content, 0644))
+	file, err := os.Open(path)
+	require.Nil(t, err)
+	defer file.Close()
+
+	reader, detected, err := newVectorReader(file, format, countColumn)
+	require.Nil(t, err)
+
+	var records []vectorRecord
+	var inputErrors []*InputError
+	for {
+		record, err := reader.next()
+		if err == io.EOF {
+			break
+		}
+		if inputErr, ok := err.(*InputError); ok {
+			inputErrors = append(inputErrors, inputErr)
+			continue
+		}
+		require.Nil(t, err)
+		records = append(records, record)
+	}
+
+	return detected, records, inputErrors
+}
+
+func TestReadingFormats(t *testing.T) {
+	expected := []vectorRecord{
+		{word: "apple", vector: []float32{1, 0.5}},
+		{word: "pie", vector: []float32{-1, 2}},
+	}
+
+	tests := []struct {
+		name    string
+		content []byte
+		format  string
+	}{
+		{
+			name:    "glove",
+			content: []byte("apple 1 0.5\npie -1 2\n"),
+			format:  FormatGlove,
+		},
+		{
+			name:    "fasttext with trailing spaces",
+			content: []byte("2 2\napple 1 0.5 \npie -1 2 \n"),
+			format:  FormatFastText,
+		},
+		{
+			name: "word2vec",
+			content: word2VecFile("2 2\n", []string{"apple", "pie"},
+				[][]float32{{1, 0.5}, {-1, 2}}),
+			format: FormatWord2Vec,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Run("detecting the format", func(t *testing.T) {
+				detected, records, inputErrors := readAll(t, test.content, FormatAuto, false)
+				assert.Equal(t, test.format, detected)
+				assert.Equal(t, expected, records)
+				assert.Empty(t, inputErrors)
+			})
+
+			t.Run("with an explicit format", func(t *testing.T) {
+				_, records, inputErrors := readAll(t, test.content, test.format, false)
+				assert.Equal(t, expected, records)
+				assert.Empty(t, inputErrors)
+			})
+		})
+	}
+}
+
+func TestReadingMalformedRecords(t *testing.T) {
+	t.Run("text", func(t *testing.T) {
+		content := []byte("2 2\napple 1 0.5\npie 1\ncomputer 1 abc\nfruit NaN 1\ncompany 3 4\n")
+		_, records, inputErrors := readAll(t, content, FormatAuto, false)
+
+		require.Len(t, records, 2)
+		assert.Equal(t, "apple", records[0].word)
+		assert.Equal(t, "company", records[1].word)
+
+		require.Len(t, inputErrors, 3)
+		assert.Equal(t, InputError{Line: 3, Word: "pie", Reason: "expected 2 values, but found 1"},
+			withoutFile(inputErrors[0]))
+		assert.Equal(t, InputError{Line: 4, Word: "computer", Reason: "invalid value 'abc' at position 1"},
+			withoutFile(inputErrors[1]))
+		assert.Equal(t, InputError{Line: 5, Word: "fruit", Reason: "invalid value 'NaN' at position 0"},
+			withoutFile(inputErrors[2]))
+	})
+
+	t.Run("count column", func(t *testing.T) {
+		content := []byte("apple 10 1 0.5\npie many 1 2\n")
+		_, records, inputErrors := readAll(t, content, FormatGlove, true)
+
+		require.Len(t, records, 1)
+		assert.Equal(t, uint64(10), records[0].count)
+		require.Len(t, inputErrors, 1)
+		assert.Equal(t, "pie", inputErrors[0].Word)
+	})
+
+	t.Run("truncated word2vec file", func(t *testing.T) {
+		content := word2VecFile("2 2\n", []string{"apple", "pie"}, [][]float32{{1, 0.5}, {-1, 2}})
+		_, records, inputErrors := readAll(t, content[:len(content)-4], FormatAuto, false)
+
+		require.Len(t, records, 1)
+		require.Len(t, inputErrors, 1)
+		assert.Equal(t, InputError{Line: 2, Word: "pie", Reason: "file ends in the middle of the record"},
+			withoutFile(inputErrors[0]))
+	})
+}
+
+func withoutFile(err *InputError) InputError {
+	out := *err
+	out.File = ""
+	return out
+}
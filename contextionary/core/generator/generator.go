@@ -17,22 +17,52 @@ import (
 	"encoding/binary"
 	"encoding/gob"
 	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
 	"log"
 	"os"
 	"strconv"
 	"strings"
 
 	"github.com/syndtr/goleveldb/leveldb"
-	annoy "github.com/weaviate/contextionary/contextionary/core/annoyindex"
+	"github.com/weaviate/contextionary/contextionary/core/annoy"
+	"github.com/weaviate/contextionary/contextionary/core/fingerprint"
+	"github.com/weaviate/contextionary/contextionary/core/quantization"
+	"github.com/weaviate/contextionary/contextionary/core/subword"
 )
 
 type Options struct {
-	VectorCSVPath string `short:"c" long:"vector-csv-path" description:"Path to the output file of Glove" required:"true"`
-	TempDBPath    string `short:"t" long:"temp-db-path" description:"Location for the temporary database" default:".tmp_import"`
-	OutputPrefix  string `short:"p" long:"output-prefix" description:"The prefix of the names of the files" required:"true"`
-	K             int    `short:"k" description:"number of forrests to generate" default:"20"`
+	VectorCSVPath string  `short:"c" long:"vector-csv-path" description:"Path to the vector file, e.g. the output file of Glove" required:"true"`
+	Format        string  `short:"f" long:"format" description:"The format of the vector files" choice:"auto" choice:"glove" choice:"fasttext" choice:"word2vec" default:"auto"`
+	ErrorReport   string  `long:"error-report" description:"Path to write a JSON report of all malformed records in the vector files to"`
+	TempDBPath    string  `short:"t" long:"temp-db-path" description:"Location for the temporary database" default:".tmp_import"`
+	OutputPrefix  string  `short:"p" long:"output-prefix" description:"The prefix of the names of the files" required:"true"`
+	K             int     `short:"k" description:"number of forrests to generate" default:"20"`
+	Metric        string  `short:"m" long:"metric" description:"The distance metric the vectors were trained for, either euclidean or cosine" default:"euclidean"`
+	MaxDistance   float64 `long:"certainty-max-distance" description:"The distance which corresponds to a certainty of 0. If not set, it is sampled from the vectors when the contextionary is loaded"`
+	WordCountPath string  `long:"word-count-path" description:"Path to a file with a 'word count' pair per line, such as the vocab file of Glove. The counts are written as the occurrences of the words"`
+	CountColumn   bool    `long:"count-column" description:"The second column of the vector file is the count of the word, i.e. lines have the format 'word count v1 v2 ...'"`
+
+	MergeVectorCSVPaths []string `long:"merge-vector-csv-path" description:"Path to an additional vector file which is merged into the contextionary, can be repeated. All files must have the same vector length"`
+	Quantize            bool     `long:"quantize" description:"Also write the int8 quantized vectors to <output-prefix>.knn.q8, which the contextionary can serve nearest neighbor queries from with a fraction of the memory"`
+	Subwords            bool     `long:"subwords" description:"Also write the character n-gram vectors to <output-prefix>.knn.subwords, which the contextionary can synthesize vectors for unknown words from"`
+	SubwordBuckets      int      `long:"subword-buckets" description:"The number of hash buckets of the character n-grams" default:"100000"`
+	PrincipalComponent  bool     `long:"principal-component" description:"Also compute the first principal component of the vectors, weighted by the occurrences of the words, and store it in the metadata. The contextionary can remove it from sentence vectors weighted by smooth inverse frequency"`
+	OnConflict          string   `long:"on-conflict" description:"What to do with a word that is contained in more than one vector file" choice:"keep-first" choice:"average" choice:"override" default:"keep-first"`
 }
 
+// placeholderOccurrence is used for all words if no counts are provided, so
+// that all words are weighted equally
+const placeholderOccurrence uint64 = 102
+
+// these need to match the metrics in the contextionary package, which can't
+// be imported here, as its tests depend on the generator
+const (
+	metricEuclidean = "euclidean"
+	metricCosine    = "cosine"
+)
+
 type WordVectorInfo struct {
 	numberOfWords int
 	vectorWidth   int
@@ -40,11 +70,33 @@ type WordVectorInfo struct {
 	metadata      JsonMetadata
 }
 
+// wordEntry is what is stored per word in the temporary database
+type wordEntry struct {
+	Vector     []float32
+	Occurrence uint64
+
+	// Sources is the number of vector files the word was read from
+	Sources int
+}
+
 type JsonMetadata struct {
-	K int `json:"k"` // the number of parallel forrests.
+	K           int     `json:"k"`                     // the number of parallel forrests.
+	Metric      string  `json:"metric"`                // the distance metric, euclidean or cosine
+	MaxDistance float64 `json:"maxDistance,omitempty"` // the distance of certainty 0, optional
+
+	// the first principal component of the vectors, optional
+	PrincipalComponent []float32 `json:"principalComponent,omitempty"`
 }
 
 func Generate(options Options) {
+	metric := options.Metric
+	if metric == "" {
+		metric = metricEuclidean
+	}
+	if metric != metricEuclidean && metric != metricCosine {
+		log.Fatalf("Unsupported metric '%s', must be one of '%s' or '%s'", metric, metricEuclidean, metricCosine)
+	}
+
 	db, err := leveldb.OpenFile(options.TempDBPath, nil)
 	defer db.Close()
 
@@ -52,17 +104,70 @@ func Generate(options Options) {
 		log.Fatalf("Could not open temporary database file %+v", err)
 	}
 
-	file, err := os.Open(options.VectorCSVPath)
+	onConflict, err := parseConflictStrategy(options.OnConflict)
 	if err != nil {
 		log.Fatal(err)
 	}
-	defer file.Close()
+
+	if options.WordCountPath != "" && options.CountColumn {
+		log.Fatal("Only one of --word-count-path and --count-column can be set")
+	}
+
+	var counts map[string]uint64
+	if options.WordCountPath != "" {
+		log.Print("Reading word counts")
+		counts, err = readWordCounts(options.WordCountPath)
+		if err != nil {
+			log.Fatalf("Could not read word counts: %v", err)
+		}
+	} else if !options.CountColumn {
+		log.Printf("No word counts provided, all words will have an occurrence of %d", placeholderOccurrence)
+	}
 
 	log.Print("Processing and ordering raw trained data")
-	info := readVectorsFromFileAndInsertIntoLevelDB(db, file)
+	info := WordVectorInfo{vectorWidth: -1}
+	inputErrors := []*InputError{}
+	paths := append([]string{options.VectorCSVPath}, options.MergeVectorCSVPaths...)
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		reader, format, err := newVectorReader(file, options.Format, options.CountColumn)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if hint := formatFromExtension(path); hint != "" && hint != format {
+			log.Printf("Reading %s as %s, although its extension suggests %s", path, format, hint)
+		}
+
+		report := readVectorsFromFileAndInsertIntoLevelDB(db, reader, path, &info, options.CountColumn, counts, onConflict)
+		file.Close()
+		report.log()
+		inputErrors = append(inputErrors, report.errors...)
+	}
+
+	if options.ErrorReport != "" {
+		if err := writeErrorReport(options.ErrorReport, inputErrors); err != nil {
+			log.Fatalf("Could not write error report: %v", err)
+		}
+	}
+
+	if info.numberOfWords == 0 {
+		log.Fatal("The vector files don't contain any valid words")
+	}
 
 	info.k = options.K
-	info.metadata = JsonMetadata{options.K}
+	if options.MaxDistance < 0 {
+		log.Fatalf("The certainty max distance must not be negative, got %f", options.MaxDistance)
+	}
+	info.metadata = JsonMetadata{K: options.K, Metric: metric, MaxDistance: options.MaxDistance}
+
+	if options.PrincipalComponent {
+		log.Print("Computing principal component")
+		info.metadata.PrincipalComponent = principalComponent(db, info)
+	}
 
 	log.Print("Generating wordlist")
 	createWordList(db, info, options.OutputPrefix+".idx")
@@ -70,53 +175,176 @@ func Generate(options Options) {
 	log.Print("Generating k-nn index")
 	createKnn(db, info, options.OutputPrefix+".knn")
 
+	if options.Quantize {
+		log.Print("Generating quantized vectors")
+		// the contextionary only uses the quantized vectors with the exact
+		// .knn and .idx files they were written for
+		modelFingerprint, err := fingerprint.Files(options.OutputPrefix+".knn", options.OutputPrefix+".idx")
+		if err != nil {
+			log.Fatalf("Could not fingerprint the model files %+v", err)
+		}
+		createQuantized(db, info, options.OutputPrefix+".knn.q8", modelFingerprint)
+	}
+
+	if options.Subwords {
+		log.Print("Generating subword vectors")
+		subwordOptions := subword.DefaultOptions
+		subwordOptions.Buckets = options.SubwordBuckets
+		createSubwords(db, info, subwordOptions, options.OutputPrefix+".knn.subwords")
+	}
+
 	db.Close()
 	os.RemoveAll(options.TempDBPath)
 }
 
-// read word vectors, insert them into level db, also return the dimension of the vectors.
-func readVectorsFromFileAndInsertIntoLevelDB(db *leveldb.DB, file *os.File) WordVectorInfo {
-	var vector_length int = -1
-	var nr_words int = 0
+// read word vectors, insert them into level db, also record the dimension of
+// the vectors and the number of distinct words in info. The vector length of
+// the first file determines the length for all following files. Words which
+// are already in the db are resolved with onConflict. Malformed records are
+// skipped and listed in the returned report.
+// The occurrence of a word is either read from the count column, if
+// countColumn is set, or looked up in counts. Words without a count get an
+// occurrence of 1, if counts are provided at all.
+func readVectorsFromFileAndInsertIntoLevelDB(db *leveldb.DB, reader vectorReader, path string, info *WordVectorInfo,
+	countColumn bool, counts map[string]uint64, onConflict conflictStrategy) sourceReport {
+	report := sourceReport{path: path}
+	var missing_counts int = 0
+	first_record := true
+
+	for {
+		record, err := reader.next()
+		if err == io.EOF {
+			break
+		}
+		if inputErr, ok := err.(*InputError); ok {
+			report.errors = append(report.errors, inputErr)
+			continue
+		}
+		if err != nil {
+			log.Fatalf("Could not read %s: %v", path, err)
+		}
 
-	scanner := bufio.NewScanner(file)
+		if info.vectorWidth == -1 {
+			info.vectorWidth = len(record.vector)
+		}
 
-	for scanner.Scan() {
-		nr_words += 1
-		parts := strings.Split(scanner.Text(), " ")
+		if len(record.vector) != info.vectorWidth {
+			if first_record {
+				log.Fatalf("%s has vectors of length %d, but the vectors of the previous files have length %d",
+					path, len(record.vector), info.vectorWidth)
+			}
+			// can only happen if a file has a header which doesn't match its
+			// records
+			report.errors = append(report.errors, &InputError{File: path, Word: record.word,
+				Reason: fmt.Sprintf("expected %d values, but found %d", info.vectorWidth, len(record.vector))})
+			continue
+		}
+		first_record = false
+
+		occurrence := placeholderOccurrence
+		if countColumn {
+			occurrence = record.count
+		} else if counts != nil {
+			count, ok := counts[record.word]
+			if !ok {
+				missing_counts += 1
+				count = 1
+			}
+			occurrence = count
+		}
 
-		word := parts[0]
-		if vector_length == -1 {
-			vector_length = len(parts) - 1
+		entry := wordEntry{Vector: record.vector, Occurrence: occurrence, Sources: 1}
+		existing, err := db.Get([]byte(record.word), nil)
+		switch err {
+		case nil:
+			report.duplicates = append(report.duplicates, record.word)
+			var previous wordEntry
+			if err := gob.NewDecoder(bytes.NewBuffer(existing)).Decode(&previous); err != nil {
+				log.Fatalf("Could not decode word entry %+v", err)
+			}
+			entry = onConflict.resolve(previous, entry)
+		case leveldb.ErrNotFound:
+			info.numberOfWords += 1
+		default:
+			log.Fatalf("Could not read from temp db %+v", err)
 		}
 
-		if vector_length != len(parts)-1 {
-			log.Print("Line corruption found for the word [" + word + "]. Lenght expected " + strconv.Itoa(vector_length) + " but found " + strconv.Itoa(len(parts)) + ". Word will be skipped.")
-			continue
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(entry); err != nil {
+			log.Fatal("Could not encode vector for temp db storage")
 		}
 
-		// pre-allocate a vector for speed.
-		vector := make([]float32, vector_length)
+		db.Put([]byte(record.word), buf.Bytes(), nil)
+		report.words += 1
+	}
 
-		for i := 1; i <= vector_length; i++ {
-			float, err := strconv.ParseFloat(parts[i], 64)
+	if missing_counts > 0 {
+		log.Printf("%d words were not found in the word counts, their occurrence is set to 1", missing_counts)
+	}
 
-			if err != nil {
-				log.Fatal("Error parsing float")
-			}
+	return report
+}
 
-			vector[i-1] = float32(float)
+// writeErrorReport writes the malformed records as a JSON array to path
+func writeErrorReport(path string, inputErrors []*InputError) error {
+	report, err := json.MarshalIndent(inputErrors, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, report, 0644)
+}
+
+// readWordCounts reads a file with a 'word count' pair per line
+func readWordCounts(path string) (map[string]uint64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	counts := map[string]uint64{}
+	scanner := bufio.NewScanner(file)
+	line := 0
+	for scanner.Scan() {
+		line++
+		parts := strings.Fields(scanner.Text())
+		if len(parts) == 0 {
+			continue
 		}
 
-		var buf bytes.Buffer
-		if err := gob.NewEncoder(&buf).Encode(vector); err != nil {
-			log.Fatal("Could not encode vector for temp db storage")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("line %d: expected 'word count', got '%s'", line, scanner.Text())
 		}
 
-		db.Put([]byte(word), buf.Bytes(), nil)
+		count, err := parseCount(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+
+		counts[parts[0]] = count
 	}
 
-	return WordVectorInfo{numberOfWords: nr_words, vectorWidth: vector_length}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// parseCount parses a word count. Counts of 0 are raised to 1, as the
+// occurrence is used as the argument of a logarithm when weighting words.
+func parseCount(in string) (uint64, error) {
+	count, err := strconv.ParseUint(in, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid count '%s'", in)
+	}
+
+	if count == 0 {
+		count = 1
+	}
+
+	return count, nil
 }
 
 func createWordList(db *leveldb.DB, info WordVectorInfo, outputFileName string) {
@@ -197,8 +425,15 @@ func createWordList(db *leveldb.DB, info WordVectorInfo, outputFileName string)
 		word := string(key)
 		length := len(word)
 
-		// hard-code occurence to 102 for now
-		err = binary.Write(wbuf, binary.LittleEndian, uint64(102))
+		var entry wordEntry
+		if err := gob.NewDecoder(bytes.NewBuffer(iter.Value())).Decode(&entry); err != nil {
+			log.Fatalf("Could not decode word entry %+v", err)
+		}
+
+		err = binary.Write(wbuf, binary.LittleEndian, entry.Occurrence)
+		if err != nil {
+			log.Fatal("Could not write occurrence to wordlist")
+		}
 
 		wbuf.Write([]byte(word))
 		wbuf.WriteByte(byte(0))
@@ -217,7 +452,12 @@ func createWordList(db *leveldb.DB, info WordVectorInfo, outputFileName string)
 }
 
 func createKnn(db *leveldb.DB, info WordVectorInfo, outputFileName string) {
-	var knn annoy.AnnoyIndex = annoy.NewAnnoyIndexEuclidean(info.vectorWidth)
+	var knn annoy.AnnoyIndex
+	if info.metadata.Metric == metricCosine {
+		knn = annoy.NewAnnoyIndexAngular(info.vectorWidth)
+	} else {
+		knn = annoy.NewAnnoyIndexEuclidean(info.vectorWidth)
+	}
 	var idx int = -1
 
 	iter := db.NewIterator(nil, nil)
@@ -225,15 +465,85 @@ func createKnn(db *leveldb.DB, info WordVectorInfo, outputFileName string) {
 	for iter.Next() {
 		idx += 1
 
-		vector := make([]float32, info.vectorWidth)
-		err := gob.NewDecoder(bytes.NewBuffer(iter.Value())).Decode(&vector)
+		var entry wordEntry
+		err := gob.NewDecoder(bytes.NewBuffer(iter.Value())).Decode(&entry)
 		if err != nil {
 			log.Fatalf("Could not decode vector value %+v", err)
 		}
-		knn.AddItem(idx, vector)
+		knn.AddItem(idx, entry.Vector)
+	}
+
+	if err := knn.Build(info.k); err != nil {
+		log.Fatalf("Could not build k-nn index %+v", err)
 	}
 
-	knn.Build(info.k) // Hardcoded for now. Must be tweaked.
-	knn.Save(outputFileName)
+	if err := knn.Save(outputFileName); err != nil {
+		log.Fatalf("Could not save k-nn index %+v", err)
+	}
 	knn.Unload()
 }
+
+// createQuantized writes the int8 quantized vectors in the same order as the
+// knn index
+func createQuantized(db *leveldb.DB, info WordVectorInfo, outputFileName string, modelFingerprint uint64) {
+	distance := quantization.SquaredEuclidean
+	if info.metadata.Metric == metricCosine {
+		distance = quantization.Cosine
+	}
+
+	forEachVector := func(fn func(vector []float32)) {
+		iter := db.NewIterator(nil, nil)
+		defer iter.Release()
+		for iter.Next() {
+			var entry wordEntry
+			if err := gob.NewDecoder(bytes.NewBuffer(iter.Value())).Decode(&entry); err != nil {
+				log.Fatalf("Could not decode vector value %+v", err)
+			}
+			fn(entry.Vector)
+		}
+	}
+
+	ranges := quantization.NewRanges(info.vectorWidth)
+	forEachVector(ranges.Add)
+
+	w, err := quantization.NewWriter(outputFileName, distance, ranges, info.numberOfWords, modelFingerprint)
+	if err != nil {
+		log.Fatalf("Could not create quantized vectors %+v", err)
+	}
+
+	forEachVector(func(vector []float32) {
+		if err := w.Add(vector); err != nil {
+			log.Fatalf("Could not write quantized vectors %+v", err)
+		}
+	})
+
+	if err := w.Close(); err != nil {
+		log.Fatalf("Could not save quantized vectors %+v", err)
+	}
+}
+
+// createSubwords derives the vectors of the character n-grams from the
+// vectors of the words which contain them
+func createSubwords(db *leveldb.DB, info WordVectorInfo, options subword.Options, outputFileName string) {
+	builder, err := subword.NewBuilder(options, info.vectorWidth)
+	if err != nil {
+		log.Fatalf("Could not create subword vectors %+v", err)
+	}
+
+	iter := db.NewIterator(nil, nil)
+	defer iter.Release()
+	for iter.Next() {
+		var entry wordEntry
+		if err := gob.NewDecoder(bytes.NewBuffer(iter.Value())).Decode(&entry); err != nil {
+			log.Fatalf("Could not decode vector value %+v", err)
+		}
+
+		if err := builder.Add(string(iter.Key()), entry.Vector); err != nil {
+			log.Fatalf("Could not add word to subword vectors %+v", err)
+		}
+	}
+
+	if err := builder.Write(outputFileName); err != nil {
+		log.Fatalf("Could not save subword vectors %+v", err)
+	}
+}
@@ -0,0 +1,110 @@
+/*                          _       _
+ *__      _____  __ ___   ___  __ _| |_ ___
+ *\ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+ * \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+ *  \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+ *
+ * Copyright © 2016 - 2019 Weaviate. All rights reserved.
+ * LICENSE: https://github.com/weaviate/weaviate/blob/master/LICENSE
+ * DESIGN & CONCEPT: Bob van Luijt (@bobvanluijt)
+ * CONTACT: hello@weaviate.io
+ */
+package generator
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// conflictStrategy decides which vector a word gets, if it is contained in
+// more than one of the merged vector files
+type conflictStrategy string
+
+const (
+	// ConflictKeepFirst keeps the vector and occurrence of the file the word
+	// was first found in
+	ConflictKeepFirst = "keep-first"
+
+	// ConflictAverage averages the vectors of all files and sums up the
+	// occurrences
+	ConflictAverage = "average"
+
+	// ConflictOverride uses the vector and occurrence of the file the word was
+	// last found in
+	ConflictOverride = "override"
+)
+
+func parseConflictStrategy(name string) (conflictStrategy, error) {
+	switch name {
+	case "":
+		return ConflictKeepFirst, nil
+	case ConflictKeepFirst, ConflictAverage, ConflictOverride:
+		return conflictStrategy(name), nil
+	default:
+		return "", fmt.Errorf("unsupported conflict strategy '%s', must be one of '%s', '%s' or '%s'",
+			name, ConflictKeepFirst, ConflictAverage, ConflictOverride)
+	}
+}
+
+func (c conflictStrategy) resolve(previous, next wordEntry) wordEntry {
+	switch c {
+	case ConflictOverride:
+		next.Sources = previous.Sources + next.Sources
+		return next
+	case ConflictAverage:
+		// running mean, so that every source has the same weight regardless
+		// of the order they are merged in
+		sources := previous.Sources + next.Sources
+		vector := make([]float32, len(previous.Vector))
+		for i := range vector {
+			vector[i] = previous.Vector[i] + (next.Vector[i]-previous.Vector[i])*float32(next.Sources)/float32(sources)
+		}
+		return wordEntry{
+			Vector:     vector,
+			Occurrence: previous.Occurrence + next.Occurrence,
+			Sources:    sources,
+		}
+	default:
+		previous.Sources = previous.Sources + next.Sources
+		return previous
+	}
+}
+
+// maxReportedWords limits how many of the malformed records and duplicate
+// words are listed in the log
+const maxReportedWords = 10
+
+// sourceReport summarizes what was read from a single vector file
+type sourceReport struct {
+	path       string
+	words      int
+	errors     []*InputError
+	duplicates []string
+}
+
+func (r sourceReport) log() {
+	log.Printf("Read %d words from %s", r.words, r.path)
+	if len(r.errors) > 0 {
+		log.Printf("Skipped %d malformed records in %s", len(r.errors), r.path)
+		for i, err := range r.errors {
+			if i == maxReportedWords {
+				log.Printf("... and %d more", len(r.errors)-maxReportedWords)
+				break
+			}
+			log.Print(err)
+		}
+	}
+	if len(r.duplicates) > 0 {
+		log.Printf("Found %d words in %s which were already read before: %s", len(r.duplicates), r.path,
+			wordSample(r.duplicates))
+	}
+}
+
+func wordSample(words []string) string {
+	if len(words) <= maxReportedWords {
+		return strings.Join(words, ", ")
+	}
+
+	return fmt.Sprintf("%s and %d more", strings.Join(words[:maxReportedWords], ", "), len(words)-maxReportedWords)
+}
@@ -0,0 +1,134 @@
+/*                          _       _
+ *__      _____  __ ___   ___  __ _| |_ ___
+ *\ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+ * \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+ *  \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+ *
+ * Copyright © 2016 - 2019 Weaviate. All rights reserved.
+ * LICENSE: https://github.com/weaviate/weaviate/blob/master/LICENSE
+ * DESIGN & CONCEPT: Bob van Luijt (@bobvanluijt)
+ * CONTACT: hello@weaviate.io
+ */
+package generator
+
+import (
+	"bytes"
+	"encoding/gob"
+	"log"
+	"math"
+
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+const (
+	powerIterations    = 1000
+	powerIterationStop = 1e-9
+)
+
+// principalComponent is the first principal component of the word vectors,
+// weighted by the probability of the words. It approximates the common
+// direction of the sentence vectors, which smooth inverse frequency
+// weighting removes. Like in the reference implementation of SIF, the
+// vectors aren't centered first.
+func principalComponent(db *leveldb.DB, info WordVectorInfo) []float32 {
+	dims := info.vectorWidth
+	forEachEntry := func(fn func(entry wordEntry)) {
+		iter := db.NewIterator(nil, nil)
+		defer iter.Release()
+		for iter.Next() {
+			var entry wordEntry
+			if err := gob.NewDecoder(bytes.NewBuffer(iter.Value())).Decode(&entry); err != nil {
+				log.Fatalf("Could not decode vector value %+v", err)
+			}
+			fn(entry)
+		}
+	}
+
+	var total float64
+	forEachEntry(func(entry wordEntry) {
+		total += float64(entry.Occurrence)
+	})
+	if total == 0 {
+		log.Fatal("Can't compute the principal component, all words have an occurrence of 0")
+	}
+
+	// the weighted second moment matrix, its first eigenvector is the first
+	// principal component. The weighted mean is a good first guess for it.
+	moment := make([]float64, dims*dims)
+	guess := make([]float64, dims)
+	forEachEntry(func(entry wordEntry) {
+		p := float64(entry.Occurrence) / total
+		for i, vi := range entry.Vector {
+			guess[i] += p * float64(vi)
+			row := moment[i*dims : (i+1)*dims]
+			for j, vj := range entry.Vector {
+				row[j] += p * float64(vi) * float64(vj)
+			}
+		}
+	})
+
+	return firstEigenvector(moment, dims, guess)
+}
+
+// firstEigenvector of the symmetric matrix through power iteration
+func firstEigenvector(matrix []float64, dims int, guess []float64) []float32 {
+	current := normalized(guess)
+	if current == nil {
+		current = make([]float64, dims)
+		for i := range current {
+			current[i] = 1
+		}
+		current = normalized(current)
+	}
+
+	next := make([]float64, dims)
+	for iteration := 0; iteration < powerIterations; iteration++ {
+		for i := range next {
+			next[i] = 0
+			row := matrix[i*dims : (i+1)*dims]
+			for j, v := range current {
+				next[i] += row[j] * v
+			}
+		}
+
+		n := normalized(next)
+		if n == nil {
+			// the matrix is zero, any direction is as good as any other
+			break
+		}
+
+		var change float64
+		for i := range n {
+			change += (n[i] - current[i]) * (n[i] - current[i])
+		}
+		current = n
+		if change < powerIterationStop {
+			break
+		}
+	}
+
+	out := make([]float32, dims)
+	for i, v := range current {
+		out[i] = float32(v)
+	}
+	return out
+}
+
+// normalized returns a unit length copy of the vector, nil for the zero
+// vector
+func normalized(vector []float64) []float64 {
+	var norm float64
+	for _, v := range vector {
+		norm += v * v
+	}
+	if norm == 0 {
+		return nil
+	}
+
+	norm = math.Sqrt(norm)
+	out := make([]float64, len(vector))
+	for i, v := range vector {
+		out[i] = v / norm
+	}
+	return out
+}
@@ -0,0 +1,153 @@
+/*                          _       _
+ *__      _____  __ ___   ___  __ _| |_ ___
+ *\ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+ * \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+ *  \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+ *
+ * Copyright © 2016 - 2019 Weaviate. All rights reserved.
+ * LICENSE: https://github.com/weaviate/weaviate/blob/master/LICENSE
+ * DESIGN & CONCEPT: Bob van Luijt (@bobvanluijt)
+ * CONTACT: hello@weaviate.io
+ */
+package hnsw
+
+import (
+	"container/heap"
+	"sort"
+	"sync"
+)
+
+type candidate struct {
+	id       uint32
+	distance float32
+}
+
+func (c candidate) closerThan(other candidate) bool {
+	if c.distance != other.distance {
+		return c.distance < other.distance
+	}
+	return c.id < other.id
+}
+
+func sortCandidates(candidates []candidate) {
+	sort.Slice(candidates, func(a, b int) bool { return candidates[a].closerThan(candidates[b]) })
+}
+
+func idsOf(candidates []candidate) []uint32 {
+	ids := make([]uint32, len(candidates))
+	for i, c := range candidates {
+		ids[i] = c.id
+	}
+	return ids
+}
+
+// mergeIDs appends all ids of b which are not already contained in a
+func mergeIDs(a, b []uint32) []uint32 {
+outer:
+	for _, id := range b {
+		for _, existing := range a {
+			if existing == id {
+				continue outer
+			}
+		}
+		a = append(a, id)
+	}
+	return a
+}
+
+type candidateHeap struct {
+	items []candidate
+	less  func(a, b candidate) bool
+}
+
+func (h candidateHeap) Len() int            { return len(h.items) }
+func (h candidateHeap) Less(i, j int) bool  { return h.less(h.items[i], h.items[j]) }
+func (h candidateHeap) Swap(i, j int)       { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *candidateHeap) Push(x interface{}) { h.items = append(h.items, x.(candidate)) }
+func (h *candidateHeap) Pop() interface{} {
+	item := h.items[len(h.items)-1]
+	h.items = h.items[:len(h.items)-1]
+	return item
+}
+
+// minHeap has the closest candidate on top
+type minHeap struct {
+	h candidateHeap
+}
+
+func (m *minHeap) push(c candidate) {
+	if m.h.less == nil {
+		m.h.less = func(a, b candidate) bool { return a.closerThan(b) }
+	}
+	heap.Push(&m.h, c)
+}
+
+func (m *minHeap) pop() candidate { return heap.Pop(&m.h).(candidate) }
+func (m *minHeap) len() int       { return m.h.Len() }
+
+// maxHeap has the furthest candidate on top
+type maxHeap struct {
+	h candidateHeap
+}
+
+func (m *maxHeap) push(c candidate) {
+	if m.h.less == nil {
+		m.h.less = func(a, b candidate) bool { return b.closerThan(a) }
+	}
+	heap.Push(&m.h, c)
+}
+
+func (m *maxHeap) pop() candidate { return heap.Pop(&m.h).(candidate) }
+func (m *maxHeap) top() candidate { return m.h.items[0] }
+func (m *maxHeap) len() int       { return m.h.Len() }
+
+// visitedList marks the nodes that were already looked at during a single
+// search. Instead of clearing the marks after each search, a new generation is
+// started, so that the list can be reused cheaply.
+type visitedList struct {
+	marks      []uint16
+	generation uint16
+}
+
+func (v *visitedList) reset() {
+	v.generation++
+	if v.generation == 0 {
+		// overflow, the old marks are no longer distinguishable
+		for i := range v.marks {
+			v.marks[i] = 0
+		}
+		v.generation = 1
+	}
+}
+
+func (v *visitedList) visit(id uint32) {
+	v.marks[id] = v.generation
+}
+
+func (v *visitedList) visited(id uint32) bool {
+	return v.marks[id] == v.generation
+}
+
+type visitedPool struct {
+	pool sync.Pool
+}
+
+func newVisitedPool(size int) *visitedPool {
+	return &visitedPool{
+		pool: sync.Pool{
+			New: func() interface{} {
+				return &visitedList{marks: make([]uint16, size)}
+			},
+		},
+	}
+}
+
+func (p *visitedPool) get() *visitedList {
+	v := p.pool.Get().(*visitedList)
+	v.reset()
+	return v
+}
+
+func (p *visitedPool) put(v *visitedList) {
+	p.pool.Put(v)
+}
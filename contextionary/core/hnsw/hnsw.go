@@ -0,0 +1,457 @@
+/*                          _       _
+ *__      _____  __ ___   ___  __ _| |_ ___
+ *\ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+ * \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+ *  \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+ *
+ * Copyright © 2016 - 2019 Weaviate. All rights reserved.
+ * LICENSE: https://github.com/weaviate/weaviate/blob/master/LICENSE
+ * DESIGN & CONCEPT: Bob van Luijt (@bobvanluijt)
+ * CONTACT: hello@weaviate.io
+ */
+
+// Package hnsw implements a Hierarchical Navigable Small World graph
+// (Malkov & Yashunin, 2016) for approximate nearest neighbor search. The graph
+// only stores the connections between items, the vectors themselves are looked
+// up through a VectorForID function, so that they can stay in the mmapped knn
+// file of the contextionary.
+package hnsw
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand"
+	"runtime"
+	"sync"
+)
+
+// VectorForID returns the vector of the item with the specified id. It must be
+// safe for concurrent use.
+type VectorForID func(id int) []float32
+
+// DistanceFunc is used to compare two vectors. Smaller is closer.
+type DistanceFunc func(a, b []float32) float32
+
+// Config for building an index
+type Config struct {
+	// MaxConnections is the maximum number of connections per node on all
+	// layers but the lowest one, which allows twice as many. Often called M.
+	MaxConnections int
+
+	// EfConstruction is the size of the dynamic candidate list while building
+	// the graph. Higher values lead to a better graph at the cost of a slower
+	// build.
+	EfConstruction int
+
+	// Distance between two vectors, defaults to SquaredEuclidean
+	Distance DistanceFunc
+
+	// DistanceName identifies the distance in the persisted graph, so that a
+	// graph isn't reused with a different distance. Defaults to
+	// "squared-euclidean".
+	DistanceName string
+
+	// Fingerprint identifies the vectors the graph is built for, e.g. the
+	// fingerprint of the model files, so that a persisted graph isn't reused
+	// for other vectors of the same number and length
+	Fingerprint uint64
+
+	// Workers is the amount of items that are inserted in parallel, defaults to
+	// the number of CPUs
+	Workers int
+}
+
+func (c *Config) validate() error {
+	if c.MaxConnections < 2 {
+		return fmt.Errorf("max connections must be at least 2, got %d", c.MaxConnections)
+	}
+
+	if c.EfConstruction < 1 {
+		return fmt.Errorf("ef construction must be at least 1, got %d", c.EfConstruction)
+	}
+
+	if c.Distance == nil {
+		c.Distance = SquaredEuclidean
+	}
+
+	if c.DistanceName == "" {
+		c.DistanceName = "squared-euclidean"
+	}
+
+	if len(c.DistanceName) > len(header{}.DistanceName) {
+		return fmt.Errorf("distance name '%s' is too long", c.DistanceName)
+	}
+
+	if c.Workers < 1 {
+		c.Workers = runtime.NumCPU()
+	}
+
+	return nil
+}
+
+// Index is an HNSW graph over items 0..size-1
+type Index struct {
+	sync.Mutex // protects entryPoint and maxLevel
+
+	maxConnections  int
+	efConstruction  int
+	levelNormalizer float64
+
+	distance     DistanceFunc
+	distanceName string
+	fingerprint  uint64
+	vectorForID  VectorForID
+
+	entryPoint int
+	maxLevel   int
+
+	nodes   []*node
+	visited *visitedPool
+}
+
+type node struct {
+	sync.RWMutex
+	// connections per level, connections[0] is the lowest layer which contains
+	// all nodes
+	connections [][]uint32
+}
+
+func (n *node) level() int {
+	return len(n.connections) - 1
+}
+
+func newIndex(cfg Config, size int, vectorForID VectorForID) *Index {
+	return &Index{
+		maxConnections:  cfg.MaxConnections,
+		efConstruction:  cfg.EfConstruction,
+		levelNormalizer: 1 / math.Log(float64(cfg.MaxConnections)),
+		distance:        cfg.Distance,
+		distanceName:    cfg.DistanceName,
+		fingerprint:     cfg.Fingerprint,
+		vectorForID:     vectorForID,
+		entryPoint:      -1,
+		nodes:           make([]*node, size),
+		visited:         newVisitedPool(size),
+	}
+}
+
+// Build creates a graph containing the items 0..size-1. The vectors are
+// retrieved using vectorForID.
+func Build(cfg Config, size int, vectorForID VectorForID) (*Index, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid hnsw config: %v", err)
+	}
+
+	index := newIndex(cfg, size, vectorForID)
+	if size == 0 {
+		return index, nil
+	}
+
+	// The levels are drawn up front from a seeded source, so that the layer
+	// structure of the graph doesn't depend on the scheduling of the workers.
+	random := rand.New(rand.NewSource(1))
+	for i := range index.nodes {
+		level := int(math.Floor(-math.Log(1-random.Float64()) * index.levelNormalizer))
+		index.nodes[i] = &node{connections: make([][]uint32, level+1)}
+	}
+
+	// the first node must be in place before anything can be connected to it
+	index.entryPoint = 0
+	index.maxLevel = index.nodes[0].level()
+
+	ids := make(chan int)
+	wg := &sync.WaitGroup{}
+	for w := 0; w < cfg.Workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range ids {
+				index.insert(id)
+			}
+		}()
+	}
+
+	for id := 1; id < size; id++ {
+		ids <- id
+	}
+	close(ids)
+	wg.Wait()
+
+	return index, nil
+}
+
+// Len returns the number of items in the graph
+func (i *Index) Len() int {
+	return len(i.nodes)
+}
+
+func (i *Index) maxConnectionsOnLevel(level int) int {
+	if level == 0 {
+		return 2 * i.maxConnections
+	}
+
+	return i.maxConnections
+}
+
+func (i *Index) insert(id int) {
+	n := i.nodes[id]
+	level := n.level()
+	vector := i.vectorForID(id)
+
+	i.Lock()
+	entryPoint := i.entryPoint
+	maxLevel := i.maxLevel
+	if level <= maxLevel {
+		i.Unlock()
+	} else {
+		// This node will become the new entrypoint. Keep holding the lock
+		// until it is fully connected, so nobody starts a search from a node
+		// that doesn't have any connections yet.
+		defer i.Unlock()
+	}
+
+	current := candidate{id: uint32(entryPoint), distance: i.distance(vector, i.vectorForID(entryPoint))}
+	for l := maxLevel; l > level; l-- {
+		current = i.greedySearchLayer(vector, current, l)
+	}
+
+	entryPoints := []candidate{current}
+	for l := min(level, maxLevel); l >= 0; l-- {
+		// building can't be cancelled
+		results, _ := i.searchLayer(context.Background(), vector, entryPoints, i.efConstruction, l)
+		neighbors := i.selectNeighbors(results, i.maxConnections)
+
+		n.Lock()
+		// A concurrent insert might have reached this node through an upper
+		// layer and already connected itself on this one, so keep those links
+		n.connections[l] = mergeIDs(idsOf(neighbors), n.connections[l])
+		n.Unlock()
+
+		for _, neighbor := range neighbors {
+			i.connect(int(neighbor.id), uint32(id), neighbor.distance, l)
+		}
+
+		entryPoints = results
+	}
+
+	if level > maxLevel {
+		i.entryPoint = id
+		i.maxLevel = level
+	}
+}
+
+// connect adds a connection from node source to target on the specified
+// level. If this exceeds the max connections of source, its connections are
+// shrunk using the neighbor selection heuristic.
+func (i *Index) connect(source int, target uint32, distance float32, level int) {
+	n := i.nodes[source]
+	n.Lock()
+	defer n.Unlock()
+
+	max := i.maxConnectionsOnLevel(level)
+	if len(n.connections[level]) < max {
+		n.connections[level] = append(n.connections[level], target)
+		return
+	}
+
+	sourceVector := i.vectorForID(source)
+	candidates := make([]candidate, 0, len(n.connections[level])+1)
+	candidates = append(candidates, candidate{id: target, distance: distance})
+	for _, id := range n.connections[level] {
+		candidates = append(candidates, candidate{
+			id:       id,
+			distance: i.distance(sourceVector, i.vectorForID(int(id))),
+		})
+	}
+
+	n.connections[level] = idsOf(i.selectNeighbors(candidates, max))
+}
+
+// selectNeighbors is the heuristic from algorithm 4 of the paper: a candidate
+// is only kept if it is closer to the query than to any of the neighbors
+// selected so far. This keeps the graph navigable across clusters.
+func (i *Index) selectNeighbors(candidates []candidate, max int) []candidate {
+	sortCandidates(candidates)
+	if len(candidates) <= max {
+		return candidates
+	}
+
+	selected := make([]candidate, 0, max)
+	selectedVectors := make([][]float32, 0, max)
+	for _, c := range candidates {
+		if len(selected) >= max {
+			break
+		}
+
+		vector := i.vectorForID(int(c.id))
+		good := true
+		for _, sv := range selectedVectors {
+			if i.distance(vector, sv) < c.distance {
+				good = false
+				break
+			}
+		}
+
+		if good {
+			selected = append(selected, c)
+			selectedVectors = append(selectedVectors, vector)
+		}
+	}
+
+	return selected
+}
+
+func (i *Index) connectionsOf(id uint32, level int) []uint32 {
+	n := i.nodes[id]
+	n.RLock()
+	defer n.RUnlock()
+
+	if level > n.level() {
+		return nil
+	}
+
+	// copy, so that the list can be used without holding the lock
+	return append([]uint32(nil), n.connections[level]...)
+}
+
+// greedySearchLayer moves to the closest neighbor until there is no closer
+// one, this is the same as searchLayer with ef=1 but much cheaper.
+func (i *Index) greedySearchLayer(vector []float32, current candidate, level int) candidate {
+	changed := true
+	for changed {
+		changed = false
+		for _, id := range i.connectionsOf(current.id, level) {
+			dist := i.distance(vector, i.vectorForID(int(id)))
+			if dist < current.distance {
+				current = candidate{id: id, distance: dist}
+				changed = true
+			}
+		}
+	}
+
+	return current
+}
+
+// searchLayer is algorithm 2 of the paper. It returns up to ef candidates,
+// sorted by ascending distance, or ctx.Err() once ctx is done, which is
+// checked before every expansion of a candidate.
+func (i *Index) searchLayer(ctx context.Context, vector []float32, entryPoints []candidate,
+	ef int, level int) ([]candidate, error) {
+	visited := i.visited.get()
+	defer i.visited.put(visited)
+
+	candidates := &minHeap{}
+	results := &maxHeap{}
+	for _, ep := range entryPoints {
+		visited.visit(ep.id)
+		candidates.push(ep)
+		results.push(ep)
+	}
+	for results.len() > ef {
+		results.pop()
+	}
+
+	for candidates.len() > 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		c := candidates.pop()
+		if c.distance > results.top().distance && results.len() >= ef {
+			break
+		}
+
+		for _, id := range i.connectionsOf(c.id, level) {
+			if visited.visited(id) {
+				continue
+			}
+			visited.visit(id)
+
+			dist := i.distance(vector, i.vectorForID(int(id)))
+			if results.len() < ef || dist < results.top().distance {
+				candidates.push(candidate{id: id, distance: dist})
+				results.push(candidate{id: id, distance: dist})
+				if results.len() > ef {
+					results.pop()
+				}
+			}
+		}
+	}
+
+	out := make([]candidate, results.len())
+	for j := len(out) - 1; j >= 0; j-- {
+		out[j] = results.pop()
+	}
+
+	return out, nil
+}
+
+// Search returns the ids of the n closest items to vector and their
+// distances. ef is the size of the dynamic candidate list, higher values lead
+// to a better recall at the cost of latency. It is raised to n if it is lower.
+func (i *Index) Search(vector []float32, n int, ef int) ([]int, []float32) {
+	ids, distances, _ := i.SearchContext(context.Background(), vector, n, ef)
+	return ids, distances
+}
+
+// SearchContext is Search, but stops with ctx.Err() once ctx is done
+func (i *Index) SearchContext(ctx context.Context, vector []float32, n int, ef int) ([]int, []float32, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	if len(i.nodes) == 0 || n <= 0 {
+		return nil, nil, nil
+	}
+
+	if ef < n {
+		ef = n
+	}
+
+	i.Lock()
+	entryPoint := i.entryPoint
+	maxLevel := i.maxLevel
+	i.Unlock()
+
+	current := candidate{id: uint32(entryPoint), distance: i.distance(vector, i.vectorForID(entryPoint))}
+	for l := maxLevel; l > 0; l-- {
+		current = i.greedySearchLayer(vector, current, l)
+	}
+
+	results, err := i.searchLayer(ctx, vector, []candidate{current}, ef, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(results) > n {
+		results = results[:n]
+	}
+
+	ids := make([]int, len(results))
+	distances := make([]float32, len(results))
+	for j, res := range results {
+		ids[j] = int(res.id)
+		distances[j] = res.distance
+	}
+
+	return ids, distances, nil
+}
+
+// SquaredEuclidean distance between a and b. It has the same order as the
+// euclidean distance, but avoids the square root.
+func SquaredEuclidean(a, b []float32) float32 {
+	var sum float32
+	for i := range a {
+		diff := a[i] - b[i]
+		sum += diff * diff
+	}
+
+	return sum
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,207 @@
+/*                          _       _
+ *__      _____  __ ___   ___  __ _| |_ ___
+ *\ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+ * \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+ *  \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+ *
+ * Copyright © 2016 - 2019 Weaviate. All rights reserved.
+ * LICENSE: https://github.com/weaviate/weaviate/blob/master/LICENSE
+ * DESIGN & CONCEPT: Bob van Luijt (@bobvanluijt)
+ * CONTACT: hello@weaviate.io
+ */
+package hnsw
+
+import (
+	"context"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func randomVectors(count, dims int, seed int64) [][]float32 {
+	r := rand.New(rand.NewSource(seed))
+	vectors := make([][]float32, count)
+	for i := range vectors {
+		vectors[i] = make([]float32, dims)
+		for j := range vectors[i] {
+			vectors[i][j] = r.Float32()
+		}
+	}
+	return vectors
+}
+
+func bruteForce(vectors [][]float32, query []float32, n int) []int {
+	ids := make([]int, len(vectors))
+	for i := range ids {
+		ids[i] = i
+	}
+	sort.Slice(ids, func(a, b int) bool {
+		return SquaredEuclidean(query, vectors[ids[a]]) < SquaredEuclidean(query, vectors[ids[b]])
+	})
+	return ids[:n]
+}
+
+func testConfig() Config {
+	return Config{MaxConnections: 16, EfConstruction: 100}
+}
+
+func TestSearch(t *testing.T) {
+	vectors := randomVectors(2000, 16, 1)
+	vectorForID := func(id int) []float32 { return vectors[id] }
+
+	index, err := Build(testConfig(), len(vectors), vectorForID)
+	require.Nil(t, err)
+	assert.Equal(t, 2000, index.Len())
+
+	t.Run("finding the item itself", func(t *testing.T) {
+		for i := 0; i < len(vectors); i += 100 {
+			ids, distances := index.Search(vectors[i], 1, 50)
+			assert.Equal(t, []int{i}, ids)
+			assert.Equal(t, []float32{0}, distances)
+		}
+	})
+
+	t.Run("recall compared to a brute force search", func(t *testing.T) {
+		queries := randomVectors(100, 16, 2)
+		found := 0
+		for _, query := range queries {
+			expected := bruteForce(vectors, query, 10)
+			ids, distances := index.Search(query, 10, 100)
+			require.Len(t, ids, 10)
+			assert.True(t, sort.SliceIsSorted(distances, func(a, b int) bool { return distances[a] < distances[b] }))
+			for _, id := range ids {
+				for _, e := range expected {
+					if id == e {
+						found++
+					}
+				}
+			}
+		}
+
+		recall := float32(found) / float32(len(queries)*10)
+		assert.True(t, recall > 0.95, "recall should be above 0.95, got %f", recall)
+	})
+
+	t.Run("ef is raised to n", func(t *testing.T) {
+		ids, _ := index.Search(vectors[0], 30, 1)
+		assert.Len(t, ids, 30)
+	})
+}
+
+func TestSearchContext(t *testing.T) {
+	vectors := randomVectors(2000, 16, 1)
+	var onVector func()
+	vectorForID := func(id int) []float32 {
+		if onVector != nil {
+			onVector()
+		}
+		return vectors[id]
+	}
+
+	index, err := Build(testConfig(), len(vectors), vectorForID)
+	require.Nil(t, err)
+
+	t.Run("with a cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, _, err := index.SearchContext(ctx, vectors[0], 10, 100)
+		assert.Equal(t, context.Canceled, err)
+	})
+
+	t.Run("cancelled during the search", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		visited := 0
+		onVector = func() {
+			visited++
+			if visited == 50 {
+				cancel()
+			}
+		}
+		defer func() { onVector = nil }()
+
+		_, _, err := index.SearchContext(ctx, vectors[0], 10, 1000)
+		assert.Equal(t, context.Canceled, err)
+		assert.True(t, visited < 1000, "the search should stop early, visited %d vectors", visited)
+	})
+}
+
+func TestInvalidConfig(t *testing.T) {
+	_, err := Build(Config{MaxConnections: 1, EfConstruction: 10}, 1, nil)
+	assert.NotNil(t, err)
+
+	_, err = Build(Config{MaxConnections: 10, EfConstruction: 0}, 1, nil)
+	assert.NotNil(t, err)
+}
+
+func TestSavingAndLoading(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hnsw-test")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	fileName := filepath.Join(dir, "test.hnsw")
+
+	vectors := randomVectors(500, 8, 3)
+	vectorForID := func(id int) []float32 { return vectors[id] }
+
+	const fingerprint = 0xc11f
+	cfg := testConfig()
+	cfg.Fingerprint = fingerprint
+	built, err := Build(cfg, len(vectors), vectorForID)
+	require.Nil(t, err)
+	require.Nil(t, built.Save(fileName))
+
+	t.Run("saving leaves no temporary files behind", func(t *testing.T) {
+		files, err := ioutil.ReadDir(dir)
+		require.Nil(t, err)
+		require.Len(t, files, 1)
+		assert.Equal(t, "test.hnsw", files[0].Name())
+	})
+
+	t.Run("loading a matching file", func(t *testing.T) {
+		loaded, err := Load(fileName, len(vectors), nil, "", fingerprint, vectorForID)
+		require.Nil(t, err)
+
+		for _, query := range randomVectors(20, 8, 4) {
+			expectedIDs, expectedDistances := built.Search(query, 5, 20)
+			ids, distances := loaded.Search(query, 5, 20)
+			assert.Equal(t, expectedIDs, ids)
+			assert.Equal(t, expectedDistances, distances)
+		}
+	})
+
+	t.Run("loading a file with a different number of items", func(t *testing.T) {
+		_, err := Load(fileName, len(vectors)-1, nil, "", fingerprint, vectorForID)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("loading a file with a different vector length", func(t *testing.T) {
+		other := randomVectors(500, 9, 3)
+		_, err := Load(fileName, len(other), nil, "", fingerprint, func(id int) []float32 { return other[id] })
+		assert.NotNil(t, err)
+	})
+
+	t.Run("loading a file with a different distance", func(t *testing.T) {
+		_, err := Load(fileName, len(vectors), nil, "cosine", fingerprint, vectorForID)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("loading a file which was built for other vectors", func(t *testing.T) {
+		// e.g. a retrained model with the same vocabulary size
+		_, err := Load(fileName, len(vectors), nil, "", fingerprint+1, vectorForID)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("loading a file that isn't an hnsw graph", func(t *testing.T) {
+		other := filepath.Join(dir, "other")
+		require.Nil(t, ioutil.WriteFile(other, make([]byte, 100), 0644))
+		_, err := Load(other, len(vectors), nil, "", fingerprint, vectorForID)
+		assert.NotNil(t, err)
+	})
+}
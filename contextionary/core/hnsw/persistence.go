@@ -0,0 +1,260 @@
+/*                          _       _
+ *__      _____  __ ___   ___  __ _| |_ ___
+ *\ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+ * \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+ *  \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+ *
+ * Copyright © 2016 - 2019 Weaviate. All rights reserved.
+ * LICENSE: https://github.com/weaviate/weaviate/blob/master/LICENSE
+ * DESIGN & CONCEPT: Bob van Luijt (@bobvanluijt)
+ * CONTACT: hello@weaviate.io
+ */
+package hnsw
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// The sidecar file only contains the graph, the vectors stay in the knn file.
+// All numbers are little endian:
+//
+//	[8]byte magic "C11YHNSW"
+//	uint32  version
+//	[32]byte name of the distance, zero padded
+//	uint32  dimensions
+//	uint64  number of nodes
+//	uint32  max connections
+//	uint32  ef construction
+//	uint64  entrypoint
+//	uint32  max level
+//	uint64  fingerprint of the vectors
+//
+// followed by every node:
+//
+//	uint32  level
+//	per level 0..level: uint32 number of connections, []uint32 connections
+var magic = [8]byte{'C', '1', '1', 'Y', 'H', 'N', 'S', 'W'}
+
+const fileVersion uint32 = 3
+
+type header struct {
+	Magic          [8]byte
+	Version        uint32
+	DistanceName   [32]byte
+	Dimensions     uint32
+	Size           uint64
+	MaxConnections uint32
+	EfConstruction uint32
+	EntryPoint     uint64
+	MaxLevel       uint32
+	Fingerprint    uint64
+}
+
+func (i *Index) dimensions() int {
+	if len(i.nodes) == 0 {
+		return 0
+	}
+
+	return len(i.vectorForID(0))
+}
+
+// Save writes the graph to filename. The file is written to a uniquely named
+// temporary file first and then moved into place, so that a crash can't leave
+// a partial graph behind and several processes can save the same graph.
+func (i *Index) Save(filename string) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(filename), "."+filepath.Base(filename)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("create hnsw file: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if err := tmp.Chmod(0644); err != nil {
+		return fmt.Errorf("create hnsw file: %v", err)
+	}
+
+	w := bufio.NewWriter(tmp)
+	if err := i.write(w); err != nil {
+		return fmt.Errorf("write hnsw file: %v", err)
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("write hnsw file: %v", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close hnsw file: %v", err)
+	}
+
+	if err := os.Rename(tmp.Name(), filename); err != nil {
+		return fmt.Errorf("move hnsw file into place: %v", err)
+	}
+
+	return nil
+}
+
+func (i *Index) write(w io.Writer) error {
+	h := header{
+		Magic:          magic,
+		DistanceName:   distanceNameBytes(i.distanceName),
+		Version:        fileVersion,
+		Dimensions:     uint32(i.dimensions()),
+		Size:           uint64(len(i.nodes)),
+		MaxConnections: uint32(i.maxConnections),
+		EfConstruction: uint32(i.efConstruction),
+		EntryPoint:     uint64(i.entryPoint),
+		MaxLevel:       uint32(i.maxLevel),
+		Fingerprint:    i.fingerprint,
+	}
+	if err := binary.Write(w, binary.LittleEndian, h); err != nil {
+		return err
+	}
+
+	for _, n := range i.nodes {
+		if err := binary.Write(w, binary.LittleEndian, uint32(n.level())); err != nil {
+			return err
+		}
+
+		for _, connections := range n.connections {
+			if err := binary.Write(w, binary.LittleEndian, uint32(len(connections))); err != nil {
+				return err
+			}
+
+			if err := binary.Write(w, binary.LittleEndian, connections); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func distanceNameBytes(name string) [32]byte {
+	var out [32]byte
+	copy(out[:], name)
+	return out
+}
+
+// Load reads a graph which was previously written by Save. It errors if the
+// graph doesn't match the vectors, i.e. if it was built for a different number
+// of items, a different vector length, a different distance or vectors with a
+// different fingerprint, so that the caller can rebuild it. An empty
+// distanceName is the default distance.
+func Load(filename string, size int, distance DistanceFunc, distanceName string,
+	fingerprint uint64, vectorForID VectorForID) (*Index, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("open hnsw file: %v", err)
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	var h header
+	if err := binary.Read(r, binary.LittleEndian, &h); err != nil {
+		return nil, fmt.Errorf("read hnsw header: %v", err)
+	}
+
+	if h.Magic != magic {
+		return nil, fmt.Errorf("%s is not an hnsw file", filename)
+	}
+
+	if h.Version != fileVersion {
+		return nil, fmt.Errorf("unsupported hnsw file version %d, expected %d", h.Version, fileVersion)
+	}
+
+	if h.Size != uint64(size) {
+		return nil, fmt.Errorf("hnsw file contains %d nodes, but there are %d items", h.Size, size)
+	}
+
+	if h.Fingerprint != fingerprint {
+		return nil, fmt.Errorf("hnsw file was built for vectors with the fingerprint %x, but they have %x",
+			h.Fingerprint, fingerprint)
+	}
+
+	cfg := Config{
+		MaxConnections: int(h.MaxConnections),
+		EfConstruction: int(h.EfConstruction),
+		Distance:       distance,
+		DistanceName:   distanceName,
+		Fingerprint:    fingerprint,
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("invalid hnsw header: %v", err)
+	}
+
+	if h.DistanceName != distanceNameBytes(cfg.DistanceName) {
+		return nil, fmt.Errorf("hnsw file was built for distance '%s', but '%s' is required",
+			strings.TrimRight(string(h.DistanceName[:]), "\x00"), cfg.DistanceName)
+	}
+
+	index := newIndex(cfg, size, vectorForID)
+	if dims := index.dimensions(); uint32(dims) != h.Dimensions {
+		return nil, fmt.Errorf("hnsw file was built for vectors of length %d, but vectors have length %d",
+			h.Dimensions, dims)
+	}
+
+	index.entryPoint = int(h.EntryPoint)
+	index.maxLevel = int(h.MaxLevel)
+
+	for id := range index.nodes {
+		n, err := readNode(r, size)
+		if err != nil {
+			return nil, fmt.Errorf("read hnsw node %d: %v", id, err)
+		}
+		index.nodes[id] = n
+	}
+
+	if size > 0 && (index.entryPoint >= size || index.nodes[index.entryPoint].level() != index.maxLevel) {
+		return nil, fmt.Errorf("hnsw file has an invalid entrypoint %d", index.entryPoint)
+	}
+
+	return index, nil
+}
+
+func readNode(r io.Reader, size int) (*node, error) {
+	var level uint32
+	if err := binary.Read(r, binary.LittleEndian, &level); err != nil {
+		return nil, err
+	}
+
+	// levels are drawn from an exponential distribution, anything above
+	// this is a sure sign of a corrupt file
+	if level > 64 {
+		return nil, fmt.Errorf("invalid level %d", level)
+	}
+
+	n := &node{connections: make([][]uint32, level+1)}
+	for l := range n.connections {
+		var length uint32
+		if err := binary.Read(r, binary.LittleEndian, &length); err != nil {
+			return nil, err
+		}
+
+		if uint64(length) > uint64(size) || length > math.MaxInt32 {
+			return nil, fmt.Errorf("invalid number of connections %d", length)
+		}
+
+		connections := make([]uint32, length)
+		if err := binary.Read(r, binary.LittleEndian, connections); err != nil {
+			return nil, err
+		}
+
+		for _, id := range connections {
+			if int(id) >= size {
+				return nil, fmt.Errorf("connection to non-existing node %d", id)
+			}
+		}
+
+		n.connections[l] = connections
+	}
+
+	return n, nil
+}
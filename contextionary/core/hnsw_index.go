@@ -0,0 +1,166 @@
+/*                          _       _
+ *__      _____  __ ___   ___  __ _| |_ ___
+ *\ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+ * \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+ *  \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+ *
+ * Copyright © 2016 - 2019 Weaviate. All rights reserved.
+ * LICENSE: https://github.com/weaviate/weaviate/blob/master/LICENSE
+ * DESIGN & CONCEPT: Bob van Luijt (@bobvanluijt)
+ * CONTACT: hello@weaviate.io
+ */
+package contextionary
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/sirupsen/logrus"
+	"github.com/weaviate/contextionary/contextionary/core/hnsw"
+)
+
+// HNSWConfig configures the HNSW backed contextionary
+type HNSWConfig struct {
+	// File is the sidecar file the graph is persisted to. If it doesn't exist
+	// or doesn't match the vectors, the graph is built and written to it.
+	File string
+
+	// Ef is the size of the dynamic candidate list at query time. It replaces
+	// the k (number of trees) parameter of the annoy based contextionary.
+	Ef int
+
+	MaxConnections int
+	EfConstruction int
+
+	// Logger receives warnings about sidecar files which can't be used or
+	// written, the standard logger is used if it is nil
+	Logger logrus.FieldLogger
+}
+
+// hnswIndex serves words and vectors from the same mmapped .idx and .knn
+// files as the mmappedIndex, but answers nearest neighbor queries through an
+// HNSW graph instead of the annoy trees.
+type hnswIndex struct {
+	*mmappedIndex
+	graph *hnsw.Index
+	ef    int
+}
+
+// LoadHNSWVectorFromDisk loads the contextionary from the .knn and .idx
+// files and uses an HNSW graph for nearest neighbor searches. The graph is
+// loaded from cfg.File, or built and persisted there first, which can take a
+// while for large contextionaries. If the graph can't be persisted, it is
+// only kept in memory.
+func LoadHNSWVectorFromDisk(annoy_index string, word_index_file_name string, cfg HNSWConfig) (Contextionary, error) {
+	if cfg.Ef < 1 {
+		return nil, fmt.Errorf("hnsw ef must be at least 1, got %d", cfg.Ef)
+	}
+
+	m, err := loadMmappedIndex(annoy_index, word_index_file_name)
+	if err != nil {
+		return nil, err
+	}
+
+	graph, err := loadOrBuildGraph(m, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &hnswIndex{mmappedIndex: m, graph: graph, ef: cfg.Ef}, nil
+}
+
+func loadOrBuildGraph(m *mmappedIndex, cfg HNSWConfig) (*hnsw.Index, error) {
+	logger := sidecarLogger(cfg.Logger).WithField("file", cfg.File)
+	size := m.GetNumberOfItems()
+	if _, err := os.Stat(cfg.File); err == nil {
+		graph, err := hnsw.Load(cfg.File, size, m.metric.hnswDistance(), string(m.metric),
+			m.fingerprint, m.getItem)
+		if err == nil {
+			return graph, nil
+		}
+		logger.WithError(err).Warn("hnsw index can't be used, it is rebuilt")
+	} else if !os.IsNotExist(err) {
+		logger.WithError(err).Warn("hnsw index can't be used, it is rebuilt")
+	}
+
+	graph, err := hnsw.Build(hnsw.Config{
+		MaxConnections: cfg.MaxConnections,
+		EfConstruction: cfg.EfConstruction,
+		Distance:       m.metric.hnswDistance(),
+		DistanceName:   string(m.metric),
+		Fingerprint:    m.fingerprint,
+	}, size, m.getItem)
+	if err != nil {
+		return nil, fmt.Errorf("build hnsw index: %v", err)
+	}
+
+	if err := graph.Save(cfg.File); err != nil {
+		// e.g. a read-only model directory, the graph is complete anyway, it
+		// just has to be built again on the next start
+		logger.WithError(err).Warn("could not persist hnsw index, it is only kept in memory")
+	}
+
+	return graph, nil
+}
+
+func sidecarLogger(logger logrus.FieldLogger) logrus.FieldLogger {
+	if logger == nil {
+		return logrus.StandardLogger()
+	}
+	return logger
+}
+
+// GetNnsByItem returns the n nearest neighbours of item. k is ignored, the
+// search quality is controlled by the configured ef instead.
+func (h *hnswIndex) GetNnsByItem(item ItemIndex, n int, k int) ([]ItemIndex, []float32, error) {
+	if item < 0 || int(item) >= h.GetNumberOfItems() {
+		return nil, nil, fmt.Errorf("Index out of bounds")
+	}
+
+	return h.search(context.Background(), h.getItem(int(item)), n)
+}
+
+// GetNnsByVector returns the n nearest neighbours of vector. k is ignored,
+// the search quality is controlled by the configured ef instead.
+func (h *hnswIndex) GetNnsByVector(vector Vector, n int, k int) ([]ItemIndex, []float32, error) {
+	return h.GetNnsByVectorContext(context.Background(), vector, n, k)
+}
+
+// GetNnsByVectorContext is GetNnsByVector, but stops the graph search once
+// ctx is done
+func (h *hnswIndex) GetNnsByVectorContext(ctx context.Context, vector Vector, n int, k int) ([]ItemIndex, []float32, error) {
+	if len(vector.vector) != h.GetVectorLength() {
+		return nil, nil, fmt.Errorf("Wrong vector length provided")
+	}
+
+	return h.search(ctx, vector.vector, n)
+}
+
+func (h *hnswIndex) search(ctx context.Context, vector []float32, n int) ([]ItemIndex, []float32, error) {
+	ids, distances, err := h.graph.SearchContext(ctx, vector, n, h.ef)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	indices := make([]ItemIndex, len(ids))
+	for i, id := range ids {
+		indices[i] = ItemIndex(id)
+		distances[i] = h.metric.fromHNSWDistance(distances[i])
+	}
+
+	return indices, distances, nil
+}
+
+// SafeGetSimilarWords returns n similar words in the contextionary. See
+// mmappedIndex.SafeGetSimilarWords, k is ignored.
+func (h *hnswIndex) SafeGetSimilarWords(word string, n, k int) ([]string, []float32) {
+	return safeGetSimilarWordsFromAny(h, word, n, k)
+}
+
+// SafeGetSimilarWordsWithCertainty returns similar words in the
+// contextionary, if they are close enough to match the required certainty.
+// See mmappedIndex.SafeGetSimilarWordsWithCertainty.
+func (h *hnswIndex) SafeGetSimilarWordsWithCertainty(word string, certainty float32) []string {
+	return safeGetSimilarWordsWithCertaintyFromAny(h, word, certainty)
+}
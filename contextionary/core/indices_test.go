@@ -12,11 +12,19 @@
 package contextionary
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"math"
 	"os"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
 	"github.com/weaviate/contextionary/contextionary/core/generator"
 )
 
@@ -42,6 +50,271 @@ func TestMMappedIndex(t *testing.T) {
 
 	defer os.RemoveAll(tempdir)
 
+	generateTestIndex(t, tempdir, "")
+
+	// And load the index.
+	vi, err := LoadVectorFromDisk(tempdir+"/glove.knn", tempdir+"/glove.idx")
+	if err != nil {
+		t.Errorf("Could not load vectors from disk: %v", err)
+	}
+
+	shared_tests(t, vi)
+
+	if err := vi.(io.Closer).Close(); err != nil {
+		t.Errorf("Could not unmap the contextionary: %v", err)
+	}
+}
+
+func TestHNSWIndex(t *testing.T) {
+	tempdir, err := ioutil.TempDir("", "weaviate-vector-test")
+
+	if err != nil {
+		t.Errorf("Could not create temporary directory, %v", err)
+	}
+
+	defer os.RemoveAll(tempdir)
+
+	generateTestIndex(t, tempdir, "")
+
+	cfg := HNSWConfig{
+		File:           tempdir + "/glove.hnsw",
+		Ef:             10,
+		MaxConnections: 4,
+		EfConstruction: 10,
+	}
+
+	t.Run("building the graph on first load", func(t *testing.T) {
+		vi, err := LoadHNSWVectorFromDisk(tempdir+"/glove.knn", tempdir+"/glove.idx", cfg)
+		if err != nil {
+			t.Fatalf("Could not load vectors from disk: %v", err)
+		}
+
+		if _, err := os.Stat(cfg.File); err != nil {
+			t.Errorf("expected the graph to be persisted, but got: %v", err)
+		}
+
+		shared_tests(t, vi)
+	})
+
+	t.Run("loading the persisted graph", func(t *testing.T) {
+		vi, err := LoadHNSWVectorFromDisk(tempdir+"/glove.knn", tempdir+"/glove.idx", cfg)
+		if err != nil {
+			t.Fatalf("Could not load vectors from disk: %v", err)
+		}
+
+		shared_tests(t, vi)
+	})
+
+	t.Run("rebuilding the graph of a replaced model", func(t *testing.T) {
+		// e.g. a retrained model with the same vocabulary size
+		before, err := os.Stat(cfg.File)
+		if err != nil {
+			t.Fatalf("Could not stat graph: %v", err)
+		}
+		later := time.Now().Add(time.Minute)
+		if err := os.Chtimes(tempdir+"/glove.knn", later, later); err != nil {
+			t.Fatalf("Could not touch the knn file: %v", err)
+		}
+
+		vi, err := LoadHNSWVectorFromDisk(tempdir+"/glove.knn", tempdir+"/glove.idx", cfg)
+		if err != nil {
+			t.Fatalf("Could not load vectors from disk: %v", err)
+		}
+
+		after, err := os.Stat(cfg.File)
+		if err != nil {
+			t.Fatalf("Could not stat graph: %v", err)
+		}
+		if after.ModTime().Equal(before.ModTime()) {
+			t.Errorf("expected the graph to be rebuilt for the replaced model")
+		}
+
+		shared_tests(t, vi)
+	})
+
+	t.Run("rebuilding an outdated graph", func(t *testing.T) {
+		if err := ioutil.WriteFile(cfg.File, []byte("not a graph"), 0644); err != nil {
+			t.Fatalf("Could not overwrite graph: %v", err)
+		}
+
+		logger, hook := test.NewNullLogger()
+		outdated := cfg
+		outdated.Logger = logger
+		vi, err := LoadHNSWVectorFromDisk(tempdir+"/glove.knn", tempdir+"/glove.idx", outdated)
+		if err != nil {
+			t.Fatalf("Could not load vectors from disk: %v", err)
+		}
+
+		if len(hook.Entries) != 1 || hook.LastEntry().Level != logrus.WarnLevel {
+			t.Errorf("expected a warning about the outdated graph, got %v", hook.Entries)
+		}
+
+		shared_tests(t, vi)
+	})
+
+	t.Run("serving a graph which can't be persisted", func(t *testing.T) {
+		// e.g. a read-only model directory
+		logger, hook := test.NewNullLogger()
+		unwritable := cfg
+		unwritable.File = tempdir + "/missing/glove.hnsw"
+		unwritable.Logger = logger
+		vi, err := LoadHNSWVectorFromDisk(tempdir+"/glove.knn", tempdir+"/glove.idx", unwritable)
+		if err != nil {
+			t.Fatalf("Could not load vectors from disk: %v", err)
+		}
+
+		if len(hook.Entries) != 1 || hook.LastEntry().Level != logrus.WarnLevel {
+			t.Errorf("expected a warning about the graph not being persisted, got %v", hook.Entries)
+		}
+
+		shared_tests(t, vi)
+	})
+
+	t.Run("with an invalid ef", func(t *testing.T) {
+		invalid := cfg
+		invalid.Ef = 0
+		_, err := LoadHNSWVectorFromDisk(tempdir+"/glove.knn", tempdir+"/glove.idx", invalid)
+		if err == nil {
+			t.Errorf("expected an error for ef=0")
+		}
+	})
+}
+
+func TestQuantizedIndex(t *testing.T) {
+	tempdir, err := ioutil.TempDir("", "weaviate-vector-test")
+
+	if err != nil {
+		t.Errorf("Could not create temporary directory, %v", err)
+	}
+
+	defer os.RemoveAll(tempdir)
+
+	var dataset string
+	for _, vt := range vectorTests {
+		dataset += fmt.Sprintf("%s %f %f %f\n", vt.word, vt.vec[0], vt.vec[1], vt.vec[2])
+	}
+	generateFromDataset(t, tempdir, dataset, generator.Options{Quantize: true})
+
+	cfg := QuantizedConfig{
+		File:         tempdir + "/glove.knn.q8",
+		RerankFactor: 2,
+	}
+
+	t.Run("loading the quantized vectors of the generator", func(t *testing.T) {
+		before, err := os.Stat(cfg.File)
+		if err != nil {
+			t.Fatalf("Could not stat quantized vectors: %v", err)
+		}
+
+		vi, err := LoadQuantizedVectorFromDisk(tempdir+"/glove.knn", tempdir+"/glove.idx", cfg)
+		if err != nil {
+			t.Fatalf("Could not load vectors from disk: %v", err)
+		}
+
+		after, err := os.Stat(cfg.File)
+		if err != nil {
+			t.Fatalf("Could not stat quantized vectors: %v", err)
+		}
+		if !after.ModTime().Equal(before.ModTime()) {
+			t.Errorf("expected the quantized vectors of the generator to be used as they are")
+		}
+
+		shared_tests(t, vi)
+	})
+
+	t.Run("building the quantized vectors on load", func(t *testing.T) {
+		built := cfg
+		built.File = tempdir + "/built.q8"
+		vi, err := LoadQuantizedVectorFromDisk(tempdir+"/glove.knn", tempdir+"/glove.idx", built)
+		if err != nil {
+			t.Fatalf("Could not load vectors from disk: %v", err)
+		}
+
+		if _, err := os.Stat(built.File); err != nil {
+			t.Errorf("expected the quantized vectors to be persisted, but got: %v", err)
+		}
+
+		shared_tests(t, vi)
+	})
+
+	t.Run("rebuilding the quantized vectors of a replaced model", func(t *testing.T) {
+		// e.g. a retrained model with the same vocabulary size
+		before, err := os.Stat(cfg.File)
+		if err != nil {
+			t.Fatalf("Could not stat quantized vectors: %v", err)
+		}
+		later := time.Now().Add(2 * time.Minute)
+		if err := os.Chtimes(tempdir+"/glove.knn", later, later); err != nil {
+			t.Fatalf("Could not touch the knn file: %v", err)
+		}
+
+		vi, err := LoadQuantizedVectorFromDisk(tempdir+"/glove.knn", tempdir+"/glove.idx", cfg)
+		if err != nil {
+			t.Fatalf("Could not load vectors from disk: %v", err)
+		}
+
+		after, err := os.Stat(cfg.File)
+		if err != nil {
+			t.Fatalf("Could not stat quantized vectors: %v", err)
+		}
+		if after.ModTime().Equal(before.ModTime()) {
+			t.Errorf("expected the quantized vectors to be rebuilt for the replaced model")
+		}
+
+		shared_tests(t, vi)
+	})
+
+	t.Run("rebuilding outdated quantized vectors", func(t *testing.T) {
+		if err := ioutil.WriteFile(cfg.File, []byte("not quantized vectors"), 0644); err != nil {
+			t.Fatalf("Could not overwrite quantized vectors: %v", err)
+		}
+
+		logger, hook := test.NewNullLogger()
+		outdated := cfg
+		outdated.Logger = logger
+		vi, err := LoadQuantizedVectorFromDisk(tempdir+"/glove.knn", tempdir+"/glove.idx", outdated)
+		if err != nil {
+			t.Fatalf("Could not load vectors from disk: %v", err)
+		}
+
+		if len(hook.Entries) != 1 || hook.LastEntry().Level != logrus.WarnLevel {
+			t.Errorf("expected a warning about the outdated quantized vectors, got %v", hook.Entries)
+		}
+
+		shared_tests(t, vi)
+	})
+
+	t.Run("serving quantized vectors which can't be persisted", func(t *testing.T) {
+		// e.g. a read-only model directory
+		logger, hook := test.NewNullLogger()
+		unwritable := cfg
+		unwritable.File = tempdir + "/missing/glove.knn.q8"
+		unwritable.Logger = logger
+		vi, err := LoadQuantizedVectorFromDisk(tempdir+"/glove.knn", tempdir+"/glove.idx", unwritable)
+		if err != nil {
+			t.Fatalf("Could not load vectors from disk: %v", err)
+		}
+
+		if len(hook.Entries) != 1 || hook.LastEntry().Level != logrus.WarnLevel {
+			t.Errorf("expected a warning about the quantized vectors not being persisted, got %v", hook.Entries)
+		}
+
+		shared_tests(t, vi)
+	})
+
+	t.Run("with an invalid rerank factor", func(t *testing.T) {
+		invalid := cfg
+		invalid.RerankFactor = 0
+		_, err := LoadQuantizedVectorFromDisk(tempdir+"/glove.knn", tempdir+"/glove.idx", invalid)
+		if err == nil {
+			t.Errorf("expected an error for a rerank factor of 0")
+		}
+	})
+}
+
+// generateTestIndex builds glove.knn and glove.idx from the test data in
+// tempdir
+func generateTestIndex(t *testing.T, tempdir string, metric string) {
 	// First generate the csv input fileformat based on the test data.
 	var dataset = ""
 
@@ -54,7 +327,7 @@ func TestMMappedIndex(t *testing.T) {
 		dataset += fmt.Sprintf("%f\n", vt.vec[len(vt.vec)-1])
 	}
 
-	err = ioutil.WriteFile(tempdir+"/glove.txt", []byte(dataset), 0644)
+	err := ioutil.WriteFile(tempdir+"/glove.txt", []byte(dataset), 0644)
 	if err != nil {
 		t.Errorf("Could not create input file: %v", err)
 	}
@@ -66,16 +339,348 @@ func TestMMappedIndex(t *testing.T) {
 		gen_opts.TempDBPath = tempdir + "/tempdb"
 		gen_opts.OutputPrefix = tempdir + "/glove"
 		gen_opts.K = 3
+		gen_opts.Metric = metric
 		generator.Generate(gen_opts)
 	})
+}
+
+func TestCosineIndex(t *testing.T) {
+	tempdir, err := ioutil.TempDir("", "weaviate-vector-test")
 
-	// And load the index.
-	vi, err := LoadVectorFromDisk(tempdir+"/glove.knn", tempdir+"/glove.idx")
 	if err != nil {
-		t.Errorf("Could not load vectors from disk: %v", err)
+		t.Errorf("Could not create temporary directory, %v", err)
 	}
 
-	shared_tests(t, vi)
+	defer os.RemoveAll(tempdir)
+
+	generateTestIndex(t, tempdir, "cosine")
+
+	mmapped, err := LoadVectorFromDisk(tempdir+"/glove.knn", tempdir+"/glove.idx")
+	if err != nil {
+		t.Fatalf("Could not load vectors from disk: %v", err)
+	}
+
+	hnsw, err := LoadHNSWVectorFromDisk(tempdir+"/glove.knn", tempdir+"/glove.idx", HNSWConfig{
+		File:           tempdir + "/glove.hnsw",
+		Ef:             10,
+		MaxConnections: 4,
+		EfConstruction: 10,
+	})
+	if err != nil {
+		t.Fatalf("Could not load vectors from disk: %v", err)
+	}
+
+	builder := InMemoryBuilderWithMetric(3, MetricCosine)
+	for _, v := range vectorTests {
+		builder.AddWord(v.word, NewVector(v.vec))
+	}
+	memory := Contextionary(builder.Build(3))
+
+	for name, vi := range map[string]Contextionary{"mmapped": mmapped, "hnsw": hnsw, "memory": memory} {
+		t.Run(name, func(t *testing.T) { cosine_tests(t, vi) })
+	}
+}
+
+func cosine_tests(t *testing.T, vi Contextionary) {
+	if vi.Metric() != MetricCosine {
+		t.Fatalf("expected metric to be cosine, got %s", vi.Metric())
+	}
+
+	t.Run("Test that the distances between all pairs are cosine distances", func(t *testing.T) {
+		for _, a := range vectorTests {
+			for _, b := range vectorTests {
+				vec_a := NewVector(a.vec)
+				vec_b := NewVector(b.vec)
+
+				dist, err := vi.GetDistance(vi.WordToItemIndex(a.word), vi.WordToItemIndex(b.word))
+				if err != nil {
+					t.Errorf("Could not compute distance")
+				}
+
+				expected, _ := MetricCosine.VectorDistance(&vec_a, &vec_b)
+				if !equal_float_epsilon(dist, expected, 0.0001) {
+					t.Errorf("Distance between %v and %v incorrect; %v vs %v (expected)", a.word, b.word, dist, expected)
+				}
+			}
+		}
+	})
+
+	t.Run("Test nearest neighbours of apple", func(t *testing.T) {
+		// fruit points in the exact same direction as apple, so it has the same
+		// distance as apple itself, even though it's much shorter
+		res, distances, err := vi.GetNnsByItem(vi.WordToItemIndex("apple"), 3, 3)
+		if err != nil {
+			t.Fatalf("GetNNs failed: %v", err)
+		}
+
+		if len(res) != 3 {
+			t.Fatalf("Wrong number of items returned; got %v expected 3", len(res))
+		}
+
+		words := map[string]bool{}
+		for _, r := range res[:2] {
+			w, _ := vi.ItemIndexToWord(r)
+			words[w] = true
+		}
+		if !words["apple"] || !words["fruit"] {
+			t.Errorf("apple and fruit should be closest to apple, got:\n%v", debug_print_items(vi, res, distances))
+		}
+
+		if !equal_float_epsilon(distances[0], 0, 0.0001) || !equal_float_epsilon(distances[1], 0, 0.0001) {
+			t.Errorf("apple and fruit should have distance 0, got:\n%v", debug_print_items(vi, res, distances))
+		}
+
+		if !equal_float_epsilon(distances[2], 1, 0.0001) {
+			t.Errorf("orthogonal vectors should have distance 1, got:\n%v", debug_print_items(vi, res, distances))
+		}
+	})
+
+	t.Run("Test that the certainty is calibrated on cosine distances", func(t *testing.T) {
+		// the test vectors are either parallel or orthogonal, so the largest
+		// distance between two words is 1
+		calibration := vi.CertaintyCalibration()
+		if !equal_float_epsilon(calibration.MaxDistance, 1, 0.0001) {
+			t.Errorf("expected max distance 1, got %v", calibration.MaxDistance)
+		}
+	})
+}
+
+func TestCombiningIndicesWithDifferentMetrics(t *testing.T) {
+	euclidean := InMemoryBuilder(3)
+	euclidean.AddWord("apple", NewVector([]float32{1, 0, 0}))
+	cosine := InMemoryBuilderWithMetric(3, MetricCosine)
+	cosine.AddWord("pie", NewVector([]float32{0, 1, 0}))
+
+	_, err := CombineVectorIndices([]Contextionary{euclidean.Build(3), cosine.Build(3)})
+	if err == nil {
+		t.Errorf("expected an error when combining indices with different metrics")
+	}
+}
+
+func TestParseMetric(t *testing.T) {
+	tests := map[string]Metric{
+		"":          MetricEuclidean,
+		"euclidean": MetricEuclidean,
+		"cosine":    MetricCosine,
+		"angular":   MetricCosine,
+	}
+
+	for name, expected := range tests {
+		metric, err := ParseMetric(name)
+		if err != nil || metric != expected {
+			t.Errorf("parsing '%s': expected %s, got %s (err: %v)", name, expected, metric, err)
+		}
+	}
+
+	if _, err := ParseMetric("manhattan"); err == nil {
+		t.Errorf("expected an error for an unsupported metric")
+	}
+}
+
+func TestGeneratedOccurrences(t *testing.T) {
+	counts := map[string]uint64{"apple": 500, "pie": 20, "computer": 7000, "fruit": 0}
+
+	occurrenceOf := func(t *testing.T, vi Contextionary, word string) uint64 {
+		occ, err := vi.ItemIndexToOccurrence(vi.WordToItemIndex(word))
+		if err != nil {
+			t.Fatalf("could not get occurrence of '%s': %v", word, err)
+		}
+		return occ
+	}
+
+	t.Run("with a word count file", func(t *testing.T) {
+		tempdir, err := ioutil.TempDir("", "weaviate-vector-test")
+		if err != nil {
+			t.Fatalf("Could not create temporary directory, %v", err)
+		}
+		defer os.RemoveAll(tempdir)
+
+		var dataset, vocab string
+		for _, vt := range vectorTests {
+			dataset += fmt.Sprintf("%s %f %f %f\n", vt.word, vt.vec[0], vt.vec[1], vt.vec[2])
+			if count, ok := counts[vt.word]; ok {
+				vocab += fmt.Sprintf("%s %d\n", vt.word, count)
+			}
+		}
+		err = ioutil.WriteFile(tempdir+"/vocab.txt", []byte(vocab), 0644)
+		if err != nil {
+			t.Fatalf("Could not create word count file: %v", err)
+		}
+
+		vi := generateFromDataset(t, tempdir, dataset, generator.Options{WordCountPath: tempdir + "/vocab.txt"})
+
+		expected := map[string]uint64{"apple": 500, "pie": 20, "computer": 7000, "fruit": 1, "company": 1}
+		for word, occ := range expected {
+			if actual := occurrenceOf(t, vi, word); actual != occ {
+				t.Errorf("expected occurrence of '%s' to be %d, got %d", word, occ, actual)
+			}
+		}
+
+		if perc := vi.OccurrencePercentile(100); perc != 7000 {
+			t.Errorf("expected the 100th occurrence percentile to be 7000, got %d", perc)
+		}
+	})
+
+	t.Run("with a count column", func(t *testing.T) {
+		tempdir, err := ioutil.TempDir("", "weaviate-vector-test")
+		if err != nil {
+			t.Fatalf("Could not create temporary directory, %v", err)
+		}
+		defer os.RemoveAll(tempdir)
+
+		var dataset string
+		for i, vt := range vectorTests {
+			dataset += fmt.Sprintf("%s %d %f %f %f\n", vt.word, (i+1)*10, vt.vec[0], vt.vec[1], vt.vec[2])
+		}
+
+		vi := generateFromDataset(t, tempdir, dataset, generator.Options{CountColumn: true})
+
+		if vi.GetVectorLength() != 3 {
+			t.Errorf("expected the count column not to be part of the vector, got length %d", vi.GetVectorLength())
+		}
+
+		for i, vt := range vectorTests {
+			if actual := occurrenceOf(t, vi, vt.word); actual != uint64((i+1)*10) {
+				t.Errorf("expected occurrence of '%s' to be %d, got %d", vt.word, (i+1)*10, actual)
+			}
+		}
+	})
+}
+
+func TestGeneratedPrincipalComponent(t *testing.T) {
+	// the frequent word dominates the common direction
+	dataset := "apple 1000 1 0 0\npie 10 0 1 0\ncomputer 10 0 0 1\n"
+
+	t.Run("with the principal component", func(t *testing.T) {
+		tempdir, err := ioutil.TempDir("", "weaviate-vector-test")
+		if err != nil {
+			t.Fatalf("Could not create temporary directory, %v", err)
+		}
+		defer os.RemoveAll(tempdir)
+
+		vi := generateFromDataset(t, tempdir, dataset, generator.Options{CountColumn: true, PrincipalComponent: true})
+
+		params := vi.SIFParameters()
+		if params.TotalOccurrence != 1020 {
+			t.Errorf("expected the total occurrence to be 1020, got %d", params.TotalOccurrence)
+		}
+
+		expected := []float32{1, 0, 0}
+		if len(params.PrincipalComponent) != len(expected) {
+			t.Fatalf("expected a principal component of length %d, got %v", len(expected), params.PrincipalComponent)
+		}
+		for i := range expected {
+			if math.Abs(float64(params.PrincipalComponent[i]-expected[i])) > 0.0001 {
+				t.Errorf("expected the principal component to be %v, got %v", expected, params.PrincipalComponent)
+				break
+			}
+		}
+	})
+
+	t.Run("without the principal component", func(t *testing.T) {
+		tempdir, err := ioutil.TempDir("", "weaviate-vector-test")
+		if err != nil {
+			t.Fatalf("Could not create temporary directory, %v", err)
+		}
+		defer os.RemoveAll(tempdir)
+
+		vi := generateFromDataset(t, tempdir, dataset, generator.Options{CountColumn: true})
+
+		if pc := vi.SIFParameters().PrincipalComponent; pc != nil {
+			t.Errorf("expected no principal component, got %v", pc)
+		}
+	})
+}
+
+func TestMergingVectorSources(t *testing.T) {
+	base := "apple 1 0 0\npie 0 1 0\n"
+	domain := "apple 0 0 1\ncomputer 0 0 1\n"
+
+	tests := []struct {
+		onConflict string
+		apple      []float32
+		occurrence uint64
+	}{
+		{generator.ConflictKeepFirst, []float32{1, 0, 0}, 10},
+		{generator.ConflictAverage, []float32{0.5, 0, 0.5}, 30},
+		{generator.ConflictOverride, []float32{0, 0, 1}, 20},
+	}
+
+	for _, test := range tests {
+		t.Run(test.onConflict, func(t *testing.T) {
+			tempdir, err := ioutil.TempDir("", "weaviate-vector-test")
+			if err != nil {
+				t.Fatalf("Could not create temporary directory, %v", err)
+			}
+			defer os.RemoveAll(tempdir)
+
+			err = ioutil.WriteFile(tempdir+"/domain.txt", []byte(addCounts(domain, 20)), 0644)
+			if err != nil {
+				t.Fatalf("Could not create input file: %v", err)
+			}
+
+			vi := generateFromDataset(t, tempdir, addCounts(base, 10), generator.Options{
+				CountColumn:         true,
+				MergeVectorCSVPaths: []string{tempdir + "/domain.txt"},
+				OnConflict:          test.onConflict,
+			})
+
+			if vi.GetNumberOfItems() != 3 {
+				t.Errorf("expected 3 distinct words, got %d", vi.GetNumberOfItems())
+			}
+
+			apple := vi.WordToItemIndex("apple")
+			vector, err := vi.GetVectorForItemIndex(apple)
+			if err != nil {
+				t.Fatalf("could not get vector of apple: %v", err)
+			}
+			if !reflect.DeepEqual(vector.ToArray(), test.apple) {
+				t.Errorf("expected vector of apple to be %v, got %v", test.apple, vector.ToArray())
+			}
+
+			occ, _ := vi.ItemIndexToOccurrence(apple)
+			if occ != test.occurrence {
+				t.Errorf("expected occurrence of apple to be %d, got %d", test.occurrence, occ)
+			}
+
+			for _, word := range []string{"pie", "computer"} {
+				if item := vi.WordToItemIndex(word); !item.IsPresent() {
+					t.Errorf("expected '%s' to be present", word)
+				}
+			}
+		})
+	}
+}
+
+// addCounts inserts the count as the second column of every line
+func addCounts(dataset string, count int) string {
+	lines := strings.Split(strings.TrimSpace(dataset), "\n")
+	for i, line := range lines {
+		parts := strings.SplitN(line, " ", 2)
+		lines[i] = fmt.Sprintf("%s %d %s", parts[0], count, parts[1])
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// generateFromDataset builds and loads glove.knn and glove.idx in tempdir
+// from the dataset in the generator's input format
+func generateFromDataset(t *testing.T, tempdir string, dataset string, opts generator.Options) Contextionary {
+	err := ioutil.WriteFile(tempdir+"/glove.txt", []byte(dataset), 0644)
+	if err != nil {
+		t.Fatalf("Could not create input file: %v", err)
+	}
+
+	opts.VectorCSVPath = tempdir + "/glove.txt"
+	opts.TempDBPath = tempdir + "/tempdb"
+	opts.OutputPrefix = tempdir + "/glove"
+	opts.K = 3
+	generator.Generate(opts)
+
+	vi, err := LoadVectorFromDisk(tempdir+"/glove.knn", tempdir+"/glove.idx")
+	if err != nil {
+		t.Fatalf("Could not load vectors from disk: %v", err)
+	}
+	return vi
 }
 
 func TestInMemoryIndex(t *testing.T) {
@@ -209,7 +814,7 @@ func shared_tests(t *testing.T, vi Contextionary) {
 					t.Errorf("Could not compute distance")
 				}
 
-				simple_dist, err := vt_a_vec.Distance(&vt_b_vec)
+				simple_dist, err := vi.Metric().VectorDistance(&vt_a_vec, &vt_b_vec)
 				if err != nil {
 					panic("should be same length")
 				}
@@ -324,7 +929,7 @@ func shared_tests(t *testing.T, vi Contextionary) {
 			panic("could not fetch pie vector")
 		}
 
-		distance_to_fruit, err := apple_pie.Distance(v_fruit)
+		distance_to_fruit, err := vi.Metric().VectorDistance(&apple_pie, v_fruit)
 		if err != nil {
 			panic("should be same length")
 		}
@@ -332,7 +937,7 @@ func shared_tests(t *testing.T, vi Contextionary) {
 			t.Errorf("Wrong distance for fruit, expect %v, got %v", distance_to_fruit, distances[0])
 		}
 
-		distance_to_apple, err := apple_pie.Distance(v_apple)
+		distance_to_apple, err := vi.Metric().VectorDistance(&apple_pie, v_apple)
 		if err != nil {
 			panic("should be same length")
 		}
@@ -340,7 +945,7 @@ func shared_tests(t *testing.T, vi Contextionary) {
 			t.Errorf("Wrong distance for apple, got %v", distances[1])
 		}
 
-		distance_to_pie, err := apple_pie.Distance(v_pie)
+		distance_to_pie, err := vi.Metric().VectorDistance(&apple_pie, v_pie)
 		if err != nil {
 			panic("should be same size")
 		}
@@ -348,6 +953,30 @@ func shared_tests(t *testing.T, vi Contextionary) {
 			t.Errorf("Wrong distance for pie, expected %v, got %v", distance_to_pie, distances[2])
 		}
 	})
+
+	t.Run("Test k-nearest from vector with a context", func(t *testing.T) {
+		var apple_pie = NewVector( /* centroid of apple and pie */ []float32{0.5, 0.5, 0})
+
+		expected, expectedDistances, err := vi.GetNnsByVector(apple_pie, 3, 3)
+		if err != nil {
+			t.Fatalf("GetNNs failed: %v", err)
+		}
+
+		res, distances, err := vi.GetNnsByVectorContext(context.Background(), apple_pie, 3, 3)
+		if err != nil {
+			t.Fatalf("GetNNs failed: %v", err)
+		}
+		if !reflect.DeepEqual(res, expected) || !reflect.DeepEqual(distances, expectedDistances) {
+			t.Errorf("expected the same neighbors as without a context, got %v %v, expected %v %v",
+				res, distances, expected, expectedDistances)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, _, err := vi.GetNnsByVectorContext(ctx, apple_pie, 3, 3); err != context.Canceled {
+			t.Errorf("expected a cancelled search to fail with %v, got %v", context.Canceled, err)
+		}
+	})
 }
 
 func equal_float_epsilon(a float32, b float32, epsilon float32) bool {
@@ -12,16 +12,19 @@
 package contextionary
 
 import (
+	"context"
 	"fmt"
 	"sort"
 
-	annoy "github.com/weaviate/contextionary/contextionary/core/annoyindex"
+	"github.com/weaviate/contextionary/contextionary/core/annoy"
 )
 
 type MemoryIndex struct {
-	dimensions int
-	words      []string
-	knn        annoy.AnnoyIndex
+	dimensions  int
+	metric      Metric
+	calibration CertaintyCalibration
+	words       []string
+	knn         annoy.AnnoyIndex
 }
 
 // Return the number of items that is stored in the index.
@@ -34,6 +37,21 @@ func (mi *MemoryIndex) GetVectorLength() int {
 	return mi.dimensions
 }
 
+// Returns the distance metric of the index
+func (mi *MemoryIndex) Metric() Metric {
+	return mi.metric
+}
+
+// Returns the certainty calibration sampled from the words of the index
+func (mi *MemoryIndex) CertaintyCalibration() CertaintyCalibration {
+	return mi.calibration
+}
+
+// Returns empty parameters, the words of the index don't have occurrences
+func (mi *MemoryIndex) SIFParameters() SIFParameters {
+	return SIFParameters{}
+}
+
 // Look up a word, return an index.
 // Perform binary search.
 func (mi *MemoryIndex) WordToItemIndex(word string) ItemIndex {
@@ -80,7 +98,7 @@ func (mi *MemoryIndex) GetVectorForItemIndex(item ItemIndex) (*Vector, error) {
 // Compute the distance between two items.
 func (mi MemoryIndex) GetDistance(a ItemIndex, b ItemIndex) (float32, error) {
 	if a >= 0 && b >= 0 && int(a) <= len(mi.words) && int(b) <= len(mi.words) {
-		return mi.knn.GetDistance(int(a), int(b)), nil
+		return mi.metric.fromAnnoyDistance(mi.knn.GetDistance(int(a), int(b))), nil
 	} else {
 		return 0, fmt.Errorf("Index out of bounds")
 	}
@@ -94,6 +112,7 @@ func (mi *MemoryIndex) GetNnsByItem(item ItemIndex, n int, k int) ([]ItemIndex,
 		var distances []float32
 
 		mi.knn.GetNnsByItem(int(item), n, k, &items, &distances)
+		mi.metric.fromAnnoyDistances(distances)
 
 		var indices []ItemIndex = make([]ItemIndex, len(items))
 		for i, x := range items {
@@ -113,7 +132,10 @@ func (mi *MemoryIndex) GetNnsByVector(vector Vector, n int, k int) ([]ItemIndex,
 		var items []int
 		var distances []float32
 
-		mi.knn.GetNnsByVector(vector.vector, n, k, &items, &distances)
+		if err := mi.knn.GetNnsByVector(vector.vector, n, k, &items, &distances); err != nil {
+			return nil, nil, err
+		}
+		mi.metric.fromAnnoyDistances(distances)
 
 		var indices []ItemIndex = make([]ItemIndex, len(items))
 		for i, x := range items {
@@ -126,6 +148,16 @@ func (mi *MemoryIndex) GetNnsByVector(vector Vector, n int, k int) ([]ItemIndex,
 	}
 }
 
+// GetNnsByVectorContext is GetNnsByVector for a search which is neither
+// cancelled nor expired yet, annoy can't stop a running search
+func (mi *MemoryIndex) GetNnsByVectorContext(ctx context.Context, vector Vector, n int, k int) ([]ItemIndex, []float32, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return mi.GetNnsByVector(vector, n, k)
+}
+
 // SafeGetSimilarWords returns n similar words in the contextionary,
 // examining k trees. It is guaratueed to have results, even if the word is
 // not in the contextionary. In this case the list only contains the word
@@ -150,6 +182,7 @@ func (mi *MemoryIndex) SafeGetSimilarWordsWithCertainty(word string, certainty f
 
 type MemoryIndexBuilder struct {
 	dimensions   int
+	metric       Metric
 	word_vectors mib_pairs
 }
 
@@ -167,8 +200,15 @@ func (a mib_pairs) Less(i, j int) bool { return a[i].word < a[j].word }
 
 // Construct a new builder.
 func InMemoryBuilder(dimensions int) *MemoryIndexBuilder {
+	return InMemoryBuilderWithMetric(dimensions, MetricEuclidean)
+}
+
+// Construct a new builder for vectors in the specified metric. When the index
+// is combined with another contextionary, the metrics must match.
+func InMemoryBuilderWithMetric(dimensions int, metric Metric) *MemoryIndexBuilder {
 	mib := MemoryIndexBuilder{
 		dimensions:   dimensions,
+		metric:       metric,
 		word_vectors: make([]mib_pair, 0),
 	}
 
@@ -185,8 +225,9 @@ func (mib *MemoryIndexBuilder) AddWord(word string, vector Vector) {
 func (mib *MemoryIndexBuilder) Build(trees int) *MemoryIndex {
 	mi := MemoryIndex{
 		dimensions: mib.dimensions,
+		metric:     mib.metric,
 		words:      make([]string, 0),
-		knn:        annoy.NewAnnoyIndexEuclidean(mib.dimensions),
+		knn:        mib.metric.newAnnoyIndex(mib.dimensions),
 	}
 
 	// First sort the words; this way we can do binary search on the words.
@@ -201,5 +242,9 @@ func (mib *MemoryIndexBuilder) Build(trees int) *MemoryIndex {
 	// And instruct Annoy to build it's index
 	mi.knn.Build(trees)
 
+	mi.calibration = sampleCalibration(mi.metric, len(mi.words), func(i int) []float32 {
+		return mib.word_vectors[i].vector.vector
+	})
+
 	return &mi
 }
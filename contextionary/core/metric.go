@@ -0,0 +1,148 @@
+/*                          _       _
+ *__      _____  __ ___   ___  __ _| |_ ___
+ *\ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+ * \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+ *  \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+ *
+ * Copyright © 2016 - 2019 Weaviate. All rights reserved.
+ * LICENSE: https://github.com/weaviate/weaviate/blob/master/LICENSE
+ * DESIGN & CONCEPT: Bob van Luijt (@bobvanluijt)
+ * CONTACT: hello@weaviate.io
+ */
+package contextionary
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/weaviate/contextionary/contextionary/core/annoy"
+	"github.com/weaviate/contextionary/contextionary/core/hnsw"
+	"github.com/weaviate/contextionary/contextionary/core/quantization"
+)
+
+// Metric is the distance metric the vectors of a model were trained for. It
+// is a property of the model and recorded in the metadata of the .idx file.
+// All distances returned by a Contextionary are in its metric.
+type Metric string
+
+const (
+	// MetricEuclidean is the euclidean (L2) distance. It is the default for
+	// models which don't specify a metric.
+	MetricEuclidean Metric = "euclidean"
+
+	// MetricCosine is the cosine distance, i.e. 1 - cos(a, b). It ranges from
+	// 0 (same direction) to 2 (opposite direction).
+	MetricCosine Metric = "cosine"
+)
+
+// ParseMetric parses the metric name as found in the .idx metadata. An empty
+// name is treated as euclidean for backward compatibility, "angular" is
+// accepted as an alias for cosine.
+func ParseMetric(name string) (Metric, error) {
+	switch name {
+	case "", string(MetricEuclidean):
+		return MetricEuclidean, nil
+	case string(MetricCosine), "angular":
+		return MetricCosine, nil
+	default:
+		return "", fmt.Errorf("unsupported distance metric '%s', must be one of '%s' or '%s'",
+			name, MetricEuclidean, MetricCosine)
+	}
+}
+
+// VectorDistance computes the distance between two vectors in this metric
+func (m Metric) VectorDistance(a, b *Vector) (float32, error) {
+	if len(a.vector) != len(b.vector) {
+		return 0.0, fmt.Errorf("Vectors have different dimensions")
+	}
+
+	return m.distance(a.vector, b.vector), nil
+}
+
+func (m Metric) distance(a, b []float32) float32 {
+	switch m {
+	case MetricCosine:
+		return cosineDistance(a, b)
+	default:
+		return float32(math.Sqrt(float64(hnsw.SquaredEuclidean(a, b))))
+	}
+}
+
+func cosineDistance(a, b []float32) float32 {
+	var ab, aa, bb float32
+	for i := range a {
+		ab += a[i] * b[i]
+		aa += a[i] * a[i]
+		bb += b[i] * b[i]
+	}
+
+	if aa == 0 || bb == 0 {
+		// the angle to a null vector is undefined, treat it as orthogonal
+		return 1
+	}
+
+	return 1 - ab/float32(math.Sqrt(float64(aa)*float64(bb)))
+}
+
+// newAnnoyIndex creates an annoy index which can answer queries in this
+// metric. Distances returned by it need to be converted with
+// fromAnnoyDistance.
+func (m Metric) newAnnoyIndex(dimensions int) annoy.AnnoyIndex {
+	switch m {
+	case MetricCosine:
+		return annoy.NewAnnoyIndexAngular(dimensions)
+	default:
+		return annoy.NewAnnoyIndexEuclidean(dimensions)
+	}
+}
+
+func (m Metric) fromAnnoyDistance(d float32) float32 {
+	switch m {
+	case MetricCosine:
+		// annoy's angular distance is sqrt(2 - 2cos)
+		return d * d / 2
+	default:
+		return d
+	}
+}
+
+func (m Metric) fromAnnoyDistances(distances []float32) []float32 {
+	for i := range distances {
+		distances[i] = m.fromAnnoyDistance(distances[i])
+	}
+	return distances
+}
+
+// hnswDistance is the distance the HNSW graph is built on. It only has to
+// have the same order as the metric, so the square root can be skipped for
+// euclidean distances. The results need to be converted with
+// fromHNSWDistance.
+func (m Metric) hnswDistance() hnsw.DistanceFunc {
+	switch m {
+	case MetricCosine:
+		return cosineDistance
+	default:
+		return hnsw.SquaredEuclidean
+	}
+}
+
+func (m Metric) fromHNSWDistance(d float32) float32 {
+	switch m {
+	case MetricCosine:
+		return d
+	default:
+		return float32(math.Sqrt(float64(d)))
+	}
+}
+
+// quantizationDistance is the distance the quantized vectors are compared
+// with. Like the hnswDistance it only has to have the same order as the
+// metric.
+func (m Metric) quantizationDistance() quantization.Distance {
+	switch m {
+	case MetricCosine:
+		return quantization.Cosine
+	default:
+		return quantization.SquaredEuclidean
+	}
+}
@@ -12,21 +12,26 @@
 package contextionary
 
 import (
-	"encoding/binary"
+	"context"
 	"fmt"
-	"log"
-	"math"
 	"os"
 	"syscall"
 
-	annoy "github.com/weaviate/contextionary/contextionary/core/annoyindex"
+	"github.com/weaviate/contextionary/contextionary/core/annoy"
+	"github.com/weaviate/contextionary/contextionary/core/fingerprint"
 )
 
 type mmappedIndex struct {
-	word_index *Wordlist
-	knn        annoy.AnnoyIndex
-	knnRaw     []byte
-	dimensions int
+	word_index  *Wordlist
+	knn         annoy.AnnoyIndex
+	knnRaw      []byte
+	dimensions  int
+	metric      Metric
+	calibration CertaintyCalibration
+
+	// fingerprint of the .knn and .idx files, which the sidecar files of the
+	// other indices must have been built for
+	fingerprint uint64
 }
 
 func (m *mmappedIndex) GetNumberOfItems() int {
@@ -38,6 +43,21 @@ func (m *mmappedIndex) GetVectorLength() int {
 	return int(m.word_index.vectorWidth)
 }
 
+func (m *mmappedIndex) Metric() Metric {
+	return m.metric
+}
+
+func (m *mmappedIndex) CertaintyCalibration() CertaintyCalibration {
+	return m.calibration
+}
+
+func (m *mmappedIndex) SIFParameters() SIFParameters {
+	return SIFParameters{
+		TotalOccurrence:    m.word_index.TotalOccurrence(),
+		PrincipalComponent: m.word_index.PrincipalComponent(),
+	}
+}
+
 func (m *mmappedIndex) WordToItemIndex(word string) ItemIndex {
 	return m.word_index.FindIndexByWord(word)
 }
@@ -75,29 +95,18 @@ func (m *mmappedIndex) GetVectorForItemIndex(item ItemIndex) (*Vector, error) {
 	return &Vector{vector: floats}, nil
 }
 
+// getItem reads the vector straight from the mmapped knn file, the node
+// layout depends on the metric
 func (m *mmappedIndex) getItem(index int) []float32 {
-	offset := 16
-	vectorSize := m.dimensions * 4
-	begin := index*(offset+vectorSize) + offset
-	end := begin + vectorSize
-	return vectorFromBytes(m.knnRaw[begin:end])
-}
-
-func vectorFromBytes(in []byte) []float32 {
-	out := make([]float32, len(in)/4)
-	for offset := 0; offset < len(in); offset += 4 {
-		bits := binary.LittleEndian.Uint32(in[offset : offset+4])
-		float := math.Float32frombits(bits)
-		out[offset/4] = float
-	}
-
-	return out
+	var vector []float32
+	m.knn.GetItem(index, &vector)
+	return vector
 }
 
 // Compute the distance between two items.
 func (m *mmappedIndex) GetDistance(a ItemIndex, b ItemIndex) (float32, error) {
 	if a >= 0 && b >= 0 && a <= m.word_index.GetNumberOfWords() && b <= m.word_index.GetNumberOfWords() {
-		return m.knn.GetDistance(int(a), int(b)), nil
+		return m.metric.fromAnnoyDistance(m.knn.GetDistance(int(a), int(b))), nil
 	} else {
 		return 0, fmt.Errorf("Index out of bounds")
 	}
@@ -109,6 +118,7 @@ func (m *mmappedIndex) GetNnsByItem(item ItemIndex, n int, k int) ([]ItemIndex,
 		var distances []float32
 
 		m.knn.GetNnsByItem(int(item), n, k, &items, &distances)
+		m.metric.fromAnnoyDistances(distances)
 
 		var indices []ItemIndex = make([]ItemIndex, len(items))
 		for i, x := range items {
@@ -126,7 +136,10 @@ func (m *mmappedIndex) GetNnsByVector(vector Vector, n int, k int) ([]ItemIndex,
 		var items []int
 		var distances []float32
 
-		m.knn.GetNnsByVector(vector.vector, n, k, &items, &distances)
+		if err := m.knn.GetNnsByVector(vector.vector, n, k, &items, &distances); err != nil {
+			return nil, nil, err
+		}
+		m.metric.fromAnnoyDistances(distances)
 
 		var indices []ItemIndex = make([]ItemIndex, len(items))
 		for i, x := range items {
@@ -139,6 +152,16 @@ func (m *mmappedIndex) GetNnsByVector(vector Vector, n int, k int) ([]ItemIndex,
 	}
 }
 
+// GetNnsByVectorContext is GetNnsByVector for a search which is neither
+// cancelled nor expired yet, annoy can't stop a running search
+func (m *mmappedIndex) GetNnsByVectorContext(ctx context.Context, vector Vector, n int, k int) ([]ItemIndex, []float32, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return m.GetNnsByVector(vector, n, k)
+}
+
 // SafeGetSimilarWords returns n similar words in the contextionary,
 // examining k trees. It is guaratueed to have results, even if the word is
 // not in the contextionary. In this case the list only contains the word
@@ -159,46 +182,90 @@ func (m *mmappedIndex) SafeGetSimilarWordsWithCertainty(word string, certainty f
 }
 
 func LoadVectorFromDisk(annoy_index string, word_index_file_name string) (Contextionary, error) {
+	return loadMmappedIndex(annoy_index, word_index_file_name)
+}
+
+func loadMmappedIndex(annoy_index string, word_index_file_name string) (*mmappedIndex, error) {
+	modelFingerprint, err := fingerprint.Files(annoy_index, word_index_file_name)
+	if err != nil {
+		return nil, err
+	}
+
 	word_index, err := LoadWordlist(word_index_file_name)
 
 	if err != nil {
 		return nil, fmt.Errorf("Could not load vector: %+v", err)
 	}
 
-	knn := annoy.NewAnnoyIndexEuclidean(int(word_index.vectorWidth))
-	knn.Load(annoy_index)
-
 	knnRaw, err := loadAnnoyIndexDirectly(annoy_index)
 	if err != nil {
 		return nil, fmt.Errorf("load raw index: %v", err)
 	}
 
+	// the native annoy index works directly on the mmapped memory, so there is
+	// no need to map the file twice
+	knn := word_index.Metric().newAnnoyIndex(int(word_index.vectorWidth))
+	if err := knn.LoadBytes(knnRaw); err != nil {
+		return nil, fmt.Errorf("load knn index: %v", err)
+	}
+
 	idx := &mmappedIndex{
-		word_index: word_index,
-		knn:        knn,
-		knnRaw:     knnRaw,
-		dimensions: int(word_index.vectorWidth),
+		word_index:  word_index,
+		knn:         knn,
+		knnRaw:      knnRaw,
+		dimensions:  int(word_index.vectorWidth),
+		metric:      word_index.Metric(),
+		fingerprint: modelFingerprint,
+	}
+
+	if maxDistance := word_index.MaxDistance(); maxDistance > 0 {
+		idx.calibration = CertaintyCalibration{MaxDistance: maxDistance}
+	} else {
+		idx.calibration = sampleCalibration(idx.metric, idx.GetNumberOfItems(), idx.getItem)
 	}
 
 	return idx, nil
 }
 
-// directly load the annoy index file to avoid memory leaks in the annoy
-// go-port of the C library, see #26
+// Close unmaps the .knn and .idx files. The contextionary must not be used
+// afterwards, so the caller has to make sure that no requests are in flight.
+func (m *mmappedIndex) Close() error {
+	if err := m.knn.Unload(); err != nil {
+		return err
+	}
+
+	if err := syscall.Munmap(m.knnRaw); err != nil {
+		return fmt.Errorf("munmap knn file: %v", err)
+	}
+
+	return m.word_index.Close()
+}
+
+// directly load the annoy index file, so it can be shared by the knn index
+// and the vector lookups in getItem, see #26
 func loadAnnoyIndexDirectly(path string) ([]byte, error) {
+	return mmapReadOnly(path, "knn file")
+}
+
+func mmapReadOnly(path string, kind string) ([]byte, error) {
 	file, err := os.Open(path)
 	if err != nil {
-		log.Fatalf("Can't open the knn file at %s: %+v", path, err)
+		return nil, fmt.Errorf("Can't open the %s at %s: %+v", kind, path, err)
 	}
+	defer file.Close()
 
 	file_info, err := file.Stat()
 	if err != nil {
-		log.Fatalf("Can't stat the knn file at %s: %+v", path, err)
+		return nil, fmt.Errorf("Can't stat the %s at %s: %+v", kind, path, err)
+	}
+
+	if file_info.Size() == 0 {
+		return nil, fmt.Errorf("The %s at %s is empty", kind, path)
 	}
 
 	mmap, err := syscall.Mmap(int(file.Fd()), 0, int(file_info.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
 	if err != nil {
-		log.Fatalf("Can't mmap the knn file %s: %+v", path, err)
+		return nil, fmt.Errorf("Can't mmap the %s %s: %+v", kind, path, err)
 	}
 
 	return mmap, nil
@@ -0,0 +1,598 @@
+/*                          _       _
+ *__      _____  __ ___   ___  __ _| |_ ___
+ *\ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+ * \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+ *  \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+ *
+ * Copyright © 2016 - 2019 Weaviate. All rights reserved.
+ * LICENSE: https://github.com/weaviate/weaviate/blob/master/LICENSE
+ * DESIGN & CONCEPT: Bob van Luijt (@bobvanluijt)
+ * CONTACT: hello@weaviate.io
+ */
+
+// Package quantization implements a scalar int8 quantization of vectors.
+// Every dimension is mapped linearly from its value range onto 0..255, which
+// shrinks float32 vectors to a quarter of their size. The quantized vectors
+// are stored in a sidecar file which is mmapped and scanned for approximate
+// nearest neighbors. The results are meant to be re-ranked with the exact
+// vectors.
+package quantization
+
+import (
+	"bufio"
+	"bytes"
+	"container/heap"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+// Distance between a query and the quantized vectors
+type Distance int
+
+const (
+	// SquaredEuclidean has the same order as the euclidean distance
+	SquaredEuclidean Distance = iota
+	// Cosine is 1 - cos(a, b)
+	Cosine
+)
+
+func (d Distance) String() string {
+	switch d {
+	case Cosine:
+		return "cosine"
+	default:
+		return "squared-euclidean"
+	}
+}
+
+// The file starts with a header, all numbers are little endian:
+//
+//	[8]byte  magic "C11YQNT8"
+//	uint32   version
+//	[32]byte name of the distance, zero padded
+//	uint32   dimensions
+//	uint64   number of vectors
+//	uint64   fingerprint of the vectors
+//
+// followed by the minimum and the scale of every dimension as float32s and
+// a record per vector:
+//
+//	float32  norm of the decoded vector
+//	[]uint8  one code per dimension
+var magic = [8]byte{'C', '1', '1', 'Y', 'Q', 'N', 'T', '8'}
+
+const fileVersion uint32 = 2
+
+type header struct {
+	Magic        [8]byte
+	Version      uint32
+	DistanceName [32]byte
+	Dimensions   uint32
+	Size         uint64
+	Fingerprint  uint64
+}
+
+const headerSize = 8 + 4 + 32 + 4 + 8 + 8
+
+func distanceNameBytes(d Distance) [32]byte {
+	var out [32]byte
+	copy(out[:], d.String())
+	return out
+}
+
+// Ranges collects the value range of every dimension, it is the first pass
+// of quantizing a set of vectors
+type Ranges struct {
+	min []float32
+	max []float32
+}
+
+// NewRanges for vectors of length dimensions
+func NewRanges(dimensions int) *Ranges {
+	r := &Ranges{min: make([]float32, dimensions), max: make([]float32, dimensions)}
+	for i := range r.min {
+		r.min[i] = float32(math.Inf(1))
+		r.max[i] = float32(math.Inf(-1))
+	}
+	return r
+}
+
+// Add extends the ranges by vector
+func (r *Ranges) Add(vector []float32) {
+	for i, v := range vector {
+		if v < r.min[i] {
+			r.min[i] = v
+		}
+		if v > r.max[i] {
+			r.max[i] = v
+		}
+	}
+}
+
+func (r *Ranges) scales() []float32 {
+	scales := make([]float32, len(r.min))
+	for i := range scales {
+		if r.max[i] > r.min[i] {
+			scales[i] = (r.max[i] - r.min[i]) / 255
+		}
+	}
+	return scales
+}
+
+// Writer writes a quantized sidecar file. The file is written to a uniquely
+// named temporary file first and only moved into place by Close, so that an
+// aborted write can't leave a partial file behind and several processes can
+// write the same file.
+type Writer struct {
+	filename string
+	tmp      *os.File
+	w        *bufio.Writer
+	min      []float32
+	scales   []float32
+	size     int
+	written  int
+	record   []byte
+}
+
+// NewWriter starts a file for size vectors, which were all added to ranges.
+// fingerprint identifies the vectors, e.g. the fingerprint of the model
+// files, so that the file isn't loaded for other vectors of the same number
+// and length.
+func NewWriter(filename string, distance Distance, ranges *Ranges, size int,
+	fingerprint uint64) (*Writer, error) {
+	tmp, err := ioutil.TempFile(filepath.Dir(filename), "."+filepath.Base(filename)+".*.tmp")
+	if err != nil {
+		return nil, fmt.Errorf("create quantized vector file: %v", err)
+	}
+
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return nil, fmt.Errorf("create quantized vector file: %v", err)
+	}
+
+	qw, err := newWriter(tmp, distance, ranges, size, fingerprint)
+	if err != nil {
+		return nil, err
+	}
+
+	qw.filename = filename
+	qw.tmp = tmp
+	return qw, nil
+}
+
+// Build quantizes size vectors, which were all added to ranges, into a store
+// which is kept in memory instead of a file, e.g. if the file can't be
+// written
+func Build(distance Distance, ranges *Ranges, size int, fingerprint uint64,
+	vectorForID func(id int) []float32) (*Store, error) {
+	var buf bytes.Buffer
+	qw, err := newWriter(&buf, distance, ranges, size, fingerprint)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < size; i++ {
+		if err := qw.Add(vectorForID(i)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := qw.w.Flush(); err != nil {
+		return nil, fmt.Errorf("write quantized vectors: %v", err)
+	}
+
+	s, err := newStore(buf.Bytes(), "in-memory quantized vectors", size, len(ranges.min), distance, fingerprint)
+	if err != nil {
+		return nil, err
+	}
+
+	// nothing to unmap
+	s.mmap = nil
+	return s, nil
+}
+
+func newWriter(out io.Writer, distance Distance, ranges *Ranges, size int,
+	fingerprint uint64) (*Writer, error) {
+	qw := &Writer{
+		w:      bufio.NewWriter(out),
+		min:    ranges.min,
+		scales: ranges.scales(),
+		size:   size,
+		record: make([]byte, 4+len(ranges.min)),
+	}
+
+	h := header{
+		Magic:        magic,
+		Version:      fileVersion,
+		DistanceName: distanceNameBytes(distance),
+		Dimensions:   uint32(len(ranges.min)),
+		Size:         uint64(size),
+		Fingerprint:  fingerprint,
+	}
+	if err := qw.write(h, qw.min, qw.scales); err != nil {
+		if tmp, ok := out.(*os.File); ok {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+		return nil, err
+	}
+
+	return qw, nil
+}
+
+func (qw *Writer) write(values ...interface{}) error {
+	for _, v := range values {
+		if err := binary.Write(qw.w, binary.LittleEndian, v); err != nil {
+			return fmt.Errorf("write quantized vector file: %v", err)
+		}
+	}
+	return nil
+}
+
+func (qw *Writer) abort() {
+	if qw.tmp == nil {
+		return
+	}
+
+	qw.tmp.Close()
+	os.Remove(qw.tmp.Name())
+}
+
+// Add quantizes and writes the next vector
+func (qw *Writer) Add(vector []float32) error {
+	if len(vector) != len(qw.min) {
+		qw.abort()
+		return fmt.Errorf("vector has length %d, expected %d", len(vector), len(qw.min))
+	}
+
+	var norm float32
+	codes := qw.record[4:]
+	for i, v := range vector {
+		var code float32
+		if qw.scales[i] > 0 {
+			code = float32(math.Round(float64((v - qw.min[i]) / qw.scales[i])))
+		}
+		if code < 0 {
+			code = 0
+		} else if code > 255 {
+			code = 255
+		}
+		codes[i] = uint8(code)
+
+		decoded := qw.min[i] + qw.scales[i]*code
+		norm += decoded * decoded
+	}
+	binary.LittleEndian.PutUint32(qw.record[0:4], math.Float32bits(float32(math.Sqrt(float64(norm)))))
+
+	if _, err := qw.w.Write(qw.record); err != nil {
+		qw.abort()
+		return fmt.Errorf("write quantized vector file: %v", err)
+	}
+
+	qw.written++
+	return nil
+}
+
+// Close finishes the file and moves it into place
+func (qw *Writer) Close() error {
+	if qw.written != qw.size {
+		qw.abort()
+		return fmt.Errorf("%d vectors were added, but the file was started for %d", qw.written, qw.size)
+	}
+
+	if err := qw.w.Flush(); err != nil {
+		qw.abort()
+		return fmt.Errorf("write quantized vector file: %v", err)
+	}
+
+	if err := qw.tmp.Close(); err != nil {
+		os.Remove(qw.tmp.Name())
+		return fmt.Errorf("close quantized vector file: %v", err)
+	}
+
+	if err := os.Rename(qw.tmp.Name(), qw.filename); err != nil {
+		os.Remove(qw.tmp.Name())
+		return fmt.Errorf("move quantized vector file into place: %v", err)
+	}
+
+	return nil
+}
+
+// Store serves the quantized vectors from the mmapped sidecar file, or from
+// memory if it was built by Build
+type Store struct {
+	distance   Distance
+	dimensions int
+	size       int
+	min        []float32
+	scales     []float32
+	records    []byte
+	recordSize int
+	mmap       []byte
+}
+
+// Load mmaps a sidecar file previously written by a Writer. It errors if the
+// file doesn't match the expected number of vectors, their length, the
+// distance or the fingerprint of the vectors, so that the caller can rebuild
+// it.
+func Load(filename string, size, dimensions int, distance Distance, fingerprint uint64) (*Store, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("open quantized vector file: %v", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("stat quantized vector file: %v", err)
+	}
+
+	if info.Size() < headerSize {
+		return nil, fmt.Errorf("%s is not a quantized vector file", filename)
+	}
+
+	mmap, err := syscall.Mmap(int(file.Fd()), 0, int(info.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, fmt.Errorf("mmap quantized vector file: %v", err)
+	}
+
+	s, err := newStore(mmap, filename, size, dimensions, distance, fingerprint)
+	if err != nil {
+		syscall.Munmap(mmap)
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func newStore(mmap []byte, filename string, size, dimensions int, distance Distance,
+	fingerprint uint64) (*Store, error) {
+	var h header
+	copy(h.Magic[:], mmap[0:8])
+	h.Version = binary.LittleEndian.Uint32(mmap[8:12])
+	copy(h.DistanceName[:], mmap[12:44])
+	h.Dimensions = binary.LittleEndian.Uint32(mmap[44:48])
+	h.Size = binary.LittleEndian.Uint64(mmap[48:56])
+	h.Fingerprint = binary.LittleEndian.Uint64(mmap[56:64])
+
+	if h.Magic != magic {
+		return nil, fmt.Errorf("%s is not a quantized vector file", filename)
+	}
+
+	if h.Version != fileVersion {
+		return nil, fmt.Errorf("unsupported quantized vector file version %d, expected %d", h.Version, fileVersion)
+	}
+
+	if h.Size != uint64(size) || h.Dimensions != uint32(dimensions) {
+		return nil, fmt.Errorf("quantized vector file contains %d vectors of length %d, but there are %d of length %d",
+			h.Size, h.Dimensions, size, dimensions)
+	}
+
+	if h.Fingerprint != fingerprint {
+		return nil, fmt.Errorf("quantized vector file was built for vectors with the fingerprint %x, but they have %x",
+			h.Fingerprint, fingerprint)
+	}
+
+	if h.DistanceName != distanceNameBytes(distance) {
+		return nil, fmt.Errorf("quantized vector file was built for distance '%s', but '%s' is required",
+			strings.TrimRight(string(h.DistanceName[:]), "\x00"), distance)
+	}
+
+	recordSize := 4 + dimensions
+	rangesEnd := headerSize + 8*dimensions
+	if len(mmap) != rangesEnd+size*recordSize {
+		return nil, fmt.Errorf("quantized vector file has %d bytes, expected %d", len(mmap), rangesEnd+size*recordSize)
+	}
+
+	s := &Store{
+		distance:   distance,
+		dimensions: dimensions,
+		size:       size,
+		min:        make([]float32, dimensions),
+		scales:     make([]float32, dimensions),
+		records:    mmap[rangesEnd:],
+		recordSize: recordSize,
+		mmap:       mmap,
+	}
+	for i := 0; i < dimensions; i++ {
+		s.min[i] = math.Float32frombits(binary.LittleEndian.Uint32(mmap[headerSize+4*i:]))
+		s.scales[i] = math.Float32frombits(binary.LittleEndian.Uint32(mmap[headerSize+4*(dimensions+i):]))
+	}
+
+	return s, nil
+}
+
+// Close unmaps the file, the store must not be used afterwards
+func (s *Store) Close() error {
+	if s.mmap == nil {
+		return nil
+	}
+
+	return syscall.Munmap(s.mmap)
+}
+
+// Len is the number of vectors in the store
+func (s *Store) Len() int {
+	return s.size
+}
+
+// Vector decodes the quantized vector of id
+func (s *Store) Vector(id int) []float32 {
+	codes := s.records[id*s.recordSize+4 : (id+1)*s.recordSize]
+	out := make([]float32, s.dimensions)
+	for i, c := range codes {
+		out[i] = s.min[i] + s.scales[i]*float32(c)
+	}
+	return out
+}
+
+// query holds what can be precomputed per query, so that the distance to a
+// record can be computed straight from the codes
+type query struct {
+	distance Distance
+	// euclidean: query minus min, cosine: query times scale
+	shifted []float32
+	scales  []float32
+	// cosine: dot product of query and min, and the norm of the query
+	offset float32
+	norm   float32
+}
+
+func (s *Store) newQuery(vector []float32) query {
+	q := query{distance: s.distance, shifted: make([]float32, s.dimensions), scales: s.scales}
+	for i, v := range vector {
+		if s.distance == Cosine {
+			q.shifted[i] = v * s.scales[i]
+			q.offset += v * s.min[i]
+			q.norm += v * v
+		} else {
+			q.shifted[i] = v - s.min[i]
+		}
+	}
+	q.norm = float32(math.Sqrt(float64(q.norm)))
+	return q
+}
+
+func (q query) distanceTo(record []byte) float32 {
+	codes := record[4:]
+	if q.distance == Cosine {
+		norm := math.Float32frombits(binary.LittleEndian.Uint32(record[0:4]))
+		if norm == 0 || q.norm == 0 {
+			return 1
+		}
+
+		dot := q.offset
+		for i, c := range codes {
+			dot += q.shifted[i] * float32(c)
+		}
+		return 1 - dot/(q.norm*norm)
+	}
+
+	var sum float32
+	for i, c := range codes {
+		diff := q.shifted[i] - q.scales[i]*float32(c)
+		sum += diff * diff
+	}
+	return sum
+}
+
+// scanChunk is the number of vectors a worker scans between checking whether
+// the search was cancelled
+const scanChunk = 4096
+
+// Search scans all quantized vectors and returns the ids of the n closest
+// ones to vector with their approximate distances, sorted by ascending
+// distance. The scan is split across all CPUs.
+func (s *Store) Search(vector []float32, n int) ([]int, []float32) {
+	ids, distances, _ := s.SearchContext(context.Background(), vector, n)
+	return ids, distances
+}
+
+// SearchContext is Search, but stops with ctx.Err() once ctx is done, which
+// every worker checks after scanning scanChunk vectors
+func (s *Store) SearchContext(ctx context.Context, vector []float32, n int) ([]int, []float32, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	if n <= 0 || s.size == 0 {
+		return nil, nil, nil
+	}
+
+	if n > s.size {
+		n = s.size
+	}
+
+	q := s.newQuery(vector)
+
+	workers := runtime.NumCPU()
+	// small stores aren't worth the overhead of splitting
+	if s.size < 10000 {
+		workers = 1
+	}
+	chunk := (s.size + workers - 1) / workers
+
+	results := make([]resultHeap, workers)
+	wg := &sync.WaitGroup{}
+	for w := 0; w < workers; w++ {
+		start, end := w*chunk, (w+1)*chunk
+		if end > s.size {
+			end = s.size
+		}
+
+		wg.Add(1)
+		go func(w, start, end int) {
+			defer wg.Done()
+			h := make(resultHeap, 0, n+1)
+			for id := start; id < end; id++ {
+				if (id-start)%scanChunk == 0 && ctx.Err() != nil {
+					return
+				}
+
+				dist := q.distanceTo(s.records[id*s.recordSize : (id+1)*s.recordSize])
+				if len(h) < n || dist < h[0].distance {
+					heap.Push(&h, result{id: id, distance: dist})
+					if len(h) > n {
+						heap.Pop(&h)
+					}
+				}
+			}
+			results[w] = h
+		}(w, start, end)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	merged := results[0]
+	for _, h := range results[1:] {
+		for _, r := range h {
+			heap.Push(&merged, r)
+			if len(merged) > n {
+				heap.Pop(&merged)
+			}
+		}
+	}
+
+	ids := make([]int, len(merged))
+	distances := make([]float32, len(merged))
+	for i := len(merged) - 1; i >= 0; i-- {
+		r := heap.Pop(&merged).(result)
+		ids[i] = r.id
+		distances[i] = r.distance
+	}
+
+	return ids, distances, nil
+}
+
+type result struct {
+	id       int
+	distance float32
+}
+
+// resultHeap is a max heap, so the furthest result can be replaced
+type resultHeap []result
+
+func (h resultHeap) Len() int            { return len(h) }
+func (h resultHeap) Less(i, j int) bool  { return h[i].distance > h[j].distance }
+func (h resultHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *resultHeap) Push(x interface{}) { *h = append(*h, x.(result)) }
+func (h *resultHeap) Pop() interface{} {
+	old := *h
+	r := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return r
+}
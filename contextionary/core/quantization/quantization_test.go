@@ -0,0 +1,248 @@
+/*                          _       _
+ *__      _____  __ ___   ___  __ _| |_ ___
+ *\ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+ * \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+ *  \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+ *
+ * Copyright © 2016 - 2019 Weaviate. All rights reserved.
+ * LICENSE: https://github.com/weaviate/weaviate/blob/master/LICENSE
+ * DESIGN & CONCEPT: Bob van Luijt (@bobvanluijt)
+ * CONTACT: hello@weaviate.io
+ */
+package quantization
+
+import (
+	"context"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func randomVectors(count, dims int, seed int64) [][]float32 {
+	r := rand.New(rand.NewSource(seed))
+	vectors := make([][]float32, count)
+	for i := range vectors {
+		vectors[i] = make([]float32, dims)
+		for j := range vectors[i] {
+			vectors[i][j] = r.Float32()*2 - 1
+		}
+	}
+	return vectors
+}
+
+// testFingerprint of the vectors written by writeStore
+const testFingerprint uint64 = 0xc0ffee
+
+func writeStore(t *testing.T, filename string, distance Distance, vectors [][]float32) {
+	ranges := NewRanges(len(vectors[0]))
+	for _, v := range vectors {
+		ranges.Add(v)
+	}
+
+	w, err := NewWriter(filename, distance, ranges, len(vectors), testFingerprint)
+	require.Nil(t, err)
+	for _, v := range vectors {
+		require.Nil(t, w.Add(v))
+	}
+	require.Nil(t, w.Close())
+}
+
+func squaredEuclidean(a, b []float32) float32 {
+	var sum float32
+	for i := range a {
+		sum += (a[i] - b[i]) * (a[i] - b[i])
+	}
+	return sum
+}
+
+func cosine(a, b []float32) float32 {
+	var ab, aa, bb float32
+	for i := range a {
+		ab += a[i] * b[i]
+		aa += a[i] * a[i]
+		bb += b[i] * b[i]
+	}
+	return 1 - ab/float32(math.Sqrt(float64(aa)*float64(bb)))
+}
+
+func TestQuantizedSearch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "quantization-test")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	vectors := randomVectors(2000, 32, 1)
+	queries := randomVectors(50, 32, 2)
+
+	for _, test := range []struct {
+		distance Distance
+		exact    func(a, b []float32) float32
+	}{
+		{SquaredEuclidean, squaredEuclidean},
+		{Cosine, cosine},
+	} {
+		t.Run(test.distance.String(), func(t *testing.T) {
+			filename := filepath.Join(dir, test.distance.String())
+			writeStore(t, filename, test.distance, vectors)
+
+			store, err := Load(filename, len(vectors), 32, test.distance, testFingerprint)
+			require.Nil(t, err)
+			defer store.Close()
+			assert.Equal(t, len(vectors), store.Len())
+
+			t.Run("decoded vectors are close to the originals", func(t *testing.T) {
+				for i := 0; i < len(vectors); i += 100 {
+					for j, v := range store.Vector(i) {
+						// the step of a dimension is at most 2/255
+						assert.InDelta(t, vectors[i][j], v, 1.0/255+1e-6)
+					}
+				}
+			})
+
+			t.Run("the top 10 contain the exact nearest neighbor", func(t *testing.T) {
+				for _, query := range queries {
+					best := 0
+					for id := range vectors {
+						if test.exact(query, vectors[id]) < test.exact(query, vectors[best]) {
+							best = id
+						}
+					}
+
+					ids, distances := store.Search(query, 10)
+					require.Len(t, ids, 10)
+					assert.True(t, sort.SliceIsSorted(distances, func(a, b int) bool { return distances[a] < distances[b] }))
+					assert.Contains(t, ids, best)
+				}
+			})
+		})
+	}
+}
+
+// cancelledAfter reports that it was cancelled once Err was called more than
+// calls times
+type cancelledAfter struct {
+	context.Context
+	calls int32
+}
+
+func (c *cancelledAfter) Err() error {
+	if atomic.AddInt32(&c.calls, -1) < 0 {
+		return context.Canceled
+	}
+	return nil
+}
+
+func TestCancellingASearch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "quantization-test")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	vectors := randomVectors(20000, 4, 1)
+	filename := filepath.Join(dir, "vectors.q8")
+	writeStore(t, filename, SquaredEuclidean, vectors)
+	store, err := Load(filename, len(vectors), 4, SquaredEuclidean, testFingerprint)
+	require.Nil(t, err)
+	defer store.Close()
+
+	t.Run("with a cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, _, err := store.SearchContext(ctx, vectors[0], 10)
+		assert.Equal(t, context.Canceled, err)
+	})
+
+	t.Run("cancelled during the scan", func(t *testing.T) {
+		ctx := &cancelledAfter{Context: context.Background(), calls: 1}
+
+		_, _, err := store.SearchContext(ctx, vectors[0], 10)
+		assert.Equal(t, context.Canceled, err)
+	})
+
+	t.Run("without cancelling", func(t *testing.T) {
+		ids, _, err := store.SearchContext(context.Background(), vectors[0], 10)
+		require.Nil(t, err)
+		assert.Len(t, ids, 10)
+	})
+}
+
+func TestLoadingMismatchingFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "quantization-test")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "vectors.q8")
+	writeStore(t, filename, SquaredEuclidean, randomVectors(100, 8, 1))
+
+	_, err = Load(filename, 99, 8, SquaredEuclidean, testFingerprint)
+	assert.NotNil(t, err, "different number of vectors")
+
+	_, err = Load(filename, 100, 9, SquaredEuclidean, testFingerprint)
+	assert.NotNil(t, err, "different vector length")
+
+	_, err = Load(filename, 100, 8, Cosine, testFingerprint)
+	assert.NotNil(t, err, "different distance")
+
+	_, err = Load(filename, 100, 8, SquaredEuclidean, testFingerprint+1)
+	assert.NotNil(t, err, "vectors of a different model")
+
+	other := filepath.Join(dir, "other")
+	require.Nil(t, ioutil.WriteFile(other, make([]byte, 100), 0644))
+	_, err = Load(other, 100, 8, SquaredEuclidean, testFingerprint)
+	assert.NotNil(t, err, "not a quantized vector file")
+}
+
+func TestIncompleteWrite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "quantization-test")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "vectors.q8")
+	ranges := NewRanges(2)
+	ranges.Add([]float32{0, 1})
+	w, err := NewWriter(filename, SquaredEuclidean, ranges, 2, testFingerprint)
+	require.Nil(t, err)
+	require.Nil(t, w.Add([]float32{0, 1}))
+	assert.NotNil(t, w.Close())
+
+	files, err := ioutil.ReadDir(dir)
+	require.Nil(t, err)
+	assert.Len(t, files, 0, "no partial or temporary file must be left behind")
+}
+
+func TestBuildingInMemory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "quantization-test")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	vectors := randomVectors(500, 8, 1)
+	filename := filepath.Join(dir, "vectors.q8")
+	writeStore(t, filename, Cosine, vectors)
+	loaded, err := Load(filename, len(vectors), 8, Cosine, testFingerprint)
+	require.Nil(t, err)
+	defer loaded.Close()
+
+	ranges := NewRanges(8)
+	for _, v := range vectors {
+		ranges.Add(v)
+	}
+	built, err := Build(Cosine, ranges, len(vectors), testFingerprint, func(id int) []float32 { return vectors[id] })
+	require.Nil(t, err)
+	assert.Equal(t, len(vectors), built.Len())
+
+	for _, query := range randomVectors(20, 8, 2) {
+		expectedIDs, expectedDistances := loaded.Search(query, 5)
+		ids, distances := built.Search(query, 5)
+		assert.Equal(t, expectedIDs, ids)
+		assert.Equal(t, expectedDistances, distances)
+	}
+
+	assert.Nil(t, built.Close())
+}
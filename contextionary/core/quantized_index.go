@@ -0,0 +1,216 @@
+/*                          _       _
+ *__      _____  __ ___   ___  __ _| |_ ___
+ *\ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+ * \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+ *  \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+ *
+ * Copyright © 2016 - 2019 Weaviate. All rights reserved.
+ * LICENSE: https://github.com/weaviate/weaviate/blob/master/LICENSE
+ * DESIGN & CONCEPT: Bob van Luijt (@bobvanluijt)
+ * CONTACT: hello@weaviate.io
+ */
+package contextionary
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+	"github.com/weaviate/contextionary/contextionary/core/quantization"
+)
+
+// QuantizedConfig configures the contextionary backed by quantized vectors
+type QuantizedConfig struct {
+	// File is the int8 sidecar file. If it doesn't exist or doesn't match the
+	// vectors, it is built from the .knn file and written to it.
+	File string
+
+	// RerankFactor controls how many candidates of the approximate search are
+	// re-ranked with the exact vectors: n * RerankFactor for n results
+	RerankFactor int
+
+	// Logger receives warnings about sidecar files which can't be used or
+	// written, the standard logger is used if it is nil
+	Logger logrus.FieldLogger
+}
+
+// quantizedIndex serves words and exact vectors from the same mmapped .idx
+// and .knn files as the mmappedIndex, but answers nearest neighbor queries
+// by scanning the much smaller int8 quantized vectors. Only the vectors of
+// the best candidates are read from the .knn file for an exact re-ranking,
+// so most of it never needs to be paged into memory.
+type quantizedIndex struct {
+	*mmappedIndex
+	store        *quantization.Store
+	rerankFactor int
+}
+
+// LoadQuantizedVectorFromDisk loads the contextionary from the .knn and .idx
+// files and uses the quantized vectors in cfg.File for nearest neighbor
+// searches. The quantized vectors are built and persisted first, if they
+// don't exist yet. If they can't be persisted, they are only kept in memory.
+func LoadQuantizedVectorFromDisk(annoy_index string, word_index_file_name string,
+	cfg QuantizedConfig) (Contextionary, error) {
+	if cfg.RerankFactor < 1 {
+		return nil, fmt.Errorf("quantization rerank factor must be at least 1, got %d", cfg.RerankFactor)
+	}
+
+	m, err := loadMmappedIndex(annoy_index, word_index_file_name)
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := loadOrBuildQuantized(m, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &quantizedIndex{mmappedIndex: m, store: store, rerankFactor: cfg.RerankFactor}, nil
+}
+
+func loadOrBuildQuantized(m *mmappedIndex, cfg QuantizedConfig) (*quantization.Store, error) {
+	logger := sidecarLogger(cfg.Logger).WithField("file", cfg.File)
+	size := m.GetNumberOfItems()
+	distance := m.metric.quantizationDistance()
+	if _, err := os.Stat(cfg.File); err == nil {
+		store, err := quantization.Load(cfg.File, size, m.dimensions, distance, m.fingerprint)
+		if err == nil {
+			return store, nil
+		}
+		logger.WithError(err).Warn("quantized vectors can't be used, they are rebuilt")
+	} else if !os.IsNotExist(err) {
+		logger.WithError(err).Warn("quantized vectors can't be used, they are rebuilt")
+	}
+
+	ranges := quantization.NewRanges(m.dimensions)
+	for i := 0; i < size; i++ {
+		ranges.Add(m.getItem(i))
+	}
+
+	store, err := persistQuantized(m, cfg.File, distance, ranges)
+	if err == nil {
+		return store, nil
+	}
+
+	// e.g. a read-only model directory, the vectors just have to be
+	// quantized again on the next start
+	logger.WithError(err).Warn("could not persist quantized vectors, they are only kept in memory")
+	store, err = quantization.Build(distance, ranges, size, m.fingerprint, m.getItem)
+	if err != nil {
+		return nil, fmt.Errorf("build quantized vectors: %v", err)
+	}
+
+	return store, nil
+}
+
+func persistQuantized(m *mmappedIndex, file string, distance quantization.Distance,
+	ranges *quantization.Ranges) (*quantization.Store, error) {
+	size := m.GetNumberOfItems()
+	w, err := quantization.NewWriter(file, distance, ranges, size, m.fingerprint)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < size; i++ {
+		if err := w.Add(m.getItem(i)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return quantization.Load(file, size, m.dimensions, distance, m.fingerprint)
+}
+
+// GetNnsByItem returns the n nearest neighbours of item. k is ignored, the
+// search quality is controlled by the configured rerank factor instead.
+func (q *quantizedIndex) GetNnsByItem(item ItemIndex, n int, k int) ([]ItemIndex, []float32, error) {
+	if item < 0 || int(item) >= q.GetNumberOfItems() {
+		return nil, nil, fmt.Errorf("Index out of bounds")
+	}
+
+	return q.search(context.Background(), q.getItem(int(item)), n)
+}
+
+// GetNnsByVector returns the n nearest neighbours of vector. k is ignored,
+// the search quality is controlled by the configured rerank factor instead.
+func (q *quantizedIndex) GetNnsByVector(vector Vector, n int, k int) ([]ItemIndex, []float32, error) {
+	return q.GetNnsByVectorContext(context.Background(), vector, n, k)
+}
+
+// GetNnsByVectorContext is GetNnsByVector, but stops the scan once ctx is
+// done
+func (q *quantizedIndex) GetNnsByVectorContext(ctx context.Context, vector Vector, n int, k int) ([]ItemIndex, []float32, error) {
+	if len(vector.vector) != q.GetVectorLength() {
+		return nil, nil, fmt.Errorf("Wrong vector length provided")
+	}
+
+	return q.search(ctx, vector.vector, n)
+}
+
+func (q *quantizedIndex) search(ctx context.Context, vector []float32, n int) ([]ItemIndex, []float32, error) {
+	candidates, _, err := q.store.SearchContext(ctx, vector, n*q.rerankFactor)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// re-rank with the exact vectors, the approximate distances can be off
+	// by the quantization error
+	indices := make([]ItemIndex, len(candidates))
+	distances := make([]float32, len(candidates))
+	for i, id := range candidates {
+		indices[i] = ItemIndex(id)
+		distances[i] = q.metric.distance(vector, q.getItem(id))
+	}
+
+	sort.Sort(&byDistance{indices, distances})
+	if len(indices) > n {
+		indices, distances = indices[:n], distances[:n]
+	}
+
+	return indices, distances, nil
+}
+
+// Close unmaps the quantized vectors as well as the .knn and .idx files
+func (q *quantizedIndex) Close() error {
+	if err := q.store.Close(); err != nil {
+		return fmt.Errorf("munmap quantized vectors: %v", err)
+	}
+
+	return q.mmappedIndex.Close()
+}
+
+// SafeGetSimilarWords returns n similar words in the contextionary. See
+// mmappedIndex.SafeGetSimilarWords, k is ignored.
+func (q *quantizedIndex) SafeGetSimilarWords(word string, n, k int) ([]string, []float32) {
+	return safeGetSimilarWordsFromAny(q, word, n, k)
+}
+
+// SafeGetSimilarWordsWithCertainty returns similar words in the
+// contextionary, if they are close enough to match the required certainty.
+// See mmappedIndex.SafeGetSimilarWordsWithCertainty.
+func (q *quantizedIndex) SafeGetSimilarWordsWithCertainty(word string, certainty float32) []string {
+	return safeGetSimilarWordsWithCertaintyFromAny(q, word, certainty)
+}
+
+type byDistance struct {
+	indices   []ItemIndex
+	distances []float32
+}
+
+func (b *byDistance) Len() int { return len(b.indices) }
+func (b *byDistance) Less(i, j int) bool {
+	if b.distances[i] == b.distances[j] {
+		// deterministic order for equally distant items
+		return b.indices[i] < b.indices[j]
+	}
+	return b.distances[i] < b.distances[j]
+}
+func (b *byDistance) Swap(i, j int) {
+	b.indices[i], b.indices[j] = b.indices[j], b.indices[i]
+	b.distances[i], b.distances[j] = b.distances[j], b.distances[i]
+}
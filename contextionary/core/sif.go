@@ -0,0 +1,59 @@
+/*                          _       _
+ *__      _____  __ ___   ___  __ _| |_ ___
+ *\ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+ * \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+ *  \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+ *
+ * Copyright © 2016 - 2019 Weaviate. All rights reserved.
+ * LICENSE: https://github.com/weaviate/weaviate/blob/master/LICENSE
+ * DESIGN & CONCEPT: Bob van Luijt (@bobvanluijt)
+ * CONTACT: hello@weaviate.io
+ */
+package contextionary
+
+// SIFParameters is what a model provides to weigh the words of a sentence
+// by their smooth inverse frequency (SIF), as described in "A Simple but
+// Tough-to-Beat Baseline for Sentence Embeddings" by Arora et al.
+type SIFParameters struct {
+	// TotalOccurrence is the sum of the occurrences of all words, the
+	// probability of a word is its occurrence divided by the total
+	TotalOccurrence uint64
+
+	// PrincipalComponent is the unit length first principal component of
+	// the model, which mostly captures the common direction of all
+	// sentences. It is nil if the model wasn't generated with one.
+	PrincipalComponent []float32
+}
+
+// Weight of a word with the specified occurrence, a/(a+p(w)) with the
+// smoothing a. Rare words are weighted close to 1, words whose probability
+// is much larger than a close to 0.
+func (p SIFParameters) Weight(occurrence uint64, smoothing float64) float64 {
+	if p.TotalOccurrence == 0 {
+		return 1
+	}
+
+	probability := float64(occurrence) / float64(p.TotalOccurrence)
+	return smoothing / (smoothing + probability)
+}
+
+// RemovePrincipalComponent subtracts the projection of the vector onto the
+// principal component, the vector is returned unchanged if the model
+// doesn't have a principal component
+func (p SIFParameters) RemovePrincipalComponent(vector []float32) []float32 {
+	if len(p.PrincipalComponent) != len(vector) {
+		return vector
+	}
+
+	var dot float32
+	for i, v := range vector {
+		dot += v * p.PrincipalComponent[i]
+	}
+
+	out := make([]float32, len(vector))
+	for i, v := range vector {
+		out[i] = v - dot*p.PrincipalComponent[i]
+	}
+
+	return out
+}
@@ -0,0 +1,45 @@
+/*                          _       _
+ *__      _____  __ ___   ___  __ _| |_ ___
+ *\ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+ * \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+ *  \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+ *
+ * Copyright © 2016 - 2019 Weaviate. All rights reserved.
+ * LICENSE: https://github.com/weaviate/weaviate/blob/master/LICENSE
+ * DESIGN & CONCEPT: Bob van Luijt (@bobvanluijt)
+ * CONTACT: hello@weaviate.io
+ */
+package contextionary
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSIFWeight(t *testing.T) {
+	params := SIFParameters{TotalOccurrence: 1000}
+
+	if w := params.Weight(1, 0.001); w != 0.5 {
+		t.Errorf("expected a word with p(w) = a to be weighted 0.5, got %f", w)
+	}
+
+	if rare, frequent := params.Weight(1, 0.001), params.Weight(500, 0.001); rare <= frequent {
+		t.Errorf("expected the rare word to be weighted higher, got %f and %f", rare, frequent)
+	}
+
+	if w := (SIFParameters{}).Weight(500, 0.001); w != 1 {
+		t.Errorf("expected a weight of 1 without a total occurrence, got %f", w)
+	}
+}
+
+func TestSIFRemovePrincipalComponent(t *testing.T) {
+	params := SIFParameters{PrincipalComponent: []float32{0, 1, 0}}
+
+	if out := params.RemovePrincipalComponent([]float32{1, 2, 3}); !reflect.DeepEqual(out, []float32{1, 0, 3}) {
+		t.Errorf("expected the principal component to be removed, got %v", out)
+	}
+
+	if out := (SIFParameters{}).RemovePrincipalComponent([]float32{1, 2, 3}); !reflect.DeepEqual(out, []float32{1, 2, 3}) {
+		t.Errorf("expected the vector to be unchanged without a principal component, got %v", out)
+	}
+}
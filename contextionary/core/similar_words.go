@@ -58,7 +58,7 @@ func safeGetSimilarWordsWithCertaintyFromAny(c11y Contextionary, word string, ce
 		}
 
 		var dist float32
-		if dist = DistanceToCertainty(certainties[i]); dist < certainty {
+		if dist = c11y.CertaintyCalibration().DistanceToCertainty(certainties[i]); dist < certainty {
 			continue
 		}
 
@@ -62,6 +62,17 @@ func (d *Detector) IsStopWord(word string) bool {
 	return false
 }
 
+// Normalize applies the normalization of the looked up words to the
+// stopwords as well, so that they still match
+func (d *Detector) Normalize(normalize func(word string) string) {
+	lookup := map[string]int{}
+	for word := range d.lookup {
+		lookup[normalize(word)] = 1
+	}
+
+	d.lookup = lookup
+}
+
 func buildLookupMap(words []string) map[string]int {
 	lookup := map[string]int{}
 	for _, word := range words {
@@ -0,0 +1,310 @@
+/*                          _       _
+ *__      _____  __ ___   ___  __ _| |_ ___
+ *\ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+ * \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+ *  \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+ *
+ * Copyright © 2016 - 2019 Weaviate. All rights reserved.
+ * LICENSE: https://github.com/weaviate/weaviate/blob/master/LICENSE
+ * DESIGN & CONCEPT: Bob van Luijt (@bobvanluijt)
+ * CONTACT: hello@weaviate.io
+ */
+
+// Package subword synthesizes vectors for words which are not in the
+// contextionary from the character n-grams they contain. The n-grams are
+// hashed into a fixed number of buckets. The vector of a bucket is the mean
+// of the vectors of all words of the contextionary which contain one of its
+// n-grams, so it is derived at generation time without any training. The
+// vector of an unknown word is the mean of the vectors of its buckets, which
+// places typos, rare inflections and product codes close to the words they
+// share most n-grams with.
+package subword
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// Options of the n-grams, they are stored in the file, so that a model is
+// always queried with the options it was built with
+type Options struct {
+	// MinN and MaxN are the shortest and longest n-grams in characters
+	MinN int
+	MaxN int
+
+	// Buckets is the number of hash buckets, more buckets mean fewer
+	// collisions between n-grams, but a larger file
+	Buckets int
+}
+
+// DefaultOptions are the n-gram lengths of fastText with fewer buckets, as
+// the vectors of the buckets are kept in memory
+var DefaultOptions = Options{MinN: 3, MaxN: 6, Buckets: 100000}
+
+func (o Options) validate() error {
+	if o.MinN < 1 || o.MaxN < o.MinN {
+		return fmt.Errorf("invalid n-gram lengths %d to %d", o.MinN, o.MaxN)
+	}
+
+	if o.Buckets < 1 {
+		return fmt.Errorf("there must be at least one bucket, got %d", o.Buckets)
+	}
+
+	return nil
+}
+
+// buckets returns the buckets of all n-grams of the word. The word is
+// wrapped in '<' and '>', so that prefixes and suffixes have n-grams of
+// their own.
+func (o Options) buckets(word string) []int {
+	runes := []rune("<" + word + ">")
+	var out []int
+	for n := o.MinN; n <= o.MaxN; n++ {
+		for start := 0; start+n <= len(runes); start++ {
+			h := fnv.New32a()
+			h.Write([]byte(string(runes[start : start+n])))
+			out = append(out, int(h.Sum32()%uint32(o.Buckets)))
+		}
+	}
+
+	return out
+}
+
+// The file starts with a header, all numbers are little endian:
+//
+//	[8]byte  magic "C11YSUBW"
+//	uint32   version
+//	uint32   shortest n-gram
+//	uint32   longest n-gram
+//	uint32   number of buckets
+//	uint32   dimensions
+//
+// followed by a record per bucket:
+//
+//	uint32   number of words which contributed to the bucket, 0 if empty
+//	[]float32 mean vector of these words
+var magic = [8]byte{'C', '1', '1', 'Y', 'S', 'U', 'B', 'W'}
+
+const fileVersion uint32 = 1
+
+const headerSize = 8 + 5*4
+
+// Builder derives the vectors of the buckets from the words of a
+// contextionary
+type Builder struct {
+	options    Options
+	dimensions int
+	sums       []float64
+	counts     []uint32
+}
+
+// NewBuilder for vectors of length dimensions
+func NewBuilder(options Options, dimensions int) (*Builder, error) {
+	if err := options.validate(); err != nil {
+		return nil, err
+	}
+
+	return &Builder{
+		options:    options,
+		dimensions: dimensions,
+		sums:       make([]float64, options.Buckets*dimensions),
+		counts:     make([]uint32, options.Buckets),
+	}, nil
+}
+
+// Add the vector of a word to the buckets of its n-grams
+func (b *Builder) Add(word string, vector []float32) error {
+	if len(vector) != b.dimensions {
+		return fmt.Errorf("vector of '%s' has length %d, expected %d", word, len(vector), b.dimensions)
+	}
+
+	for _, bucket := range b.options.buckets(word) {
+		sum := b.sums[bucket*b.dimensions : (bucket+1)*b.dimensions]
+		for i, v := range vector {
+			sum[i] += float64(v)
+		}
+		b.counts[bucket]++
+	}
+
+	return nil
+}
+
+// Write the mean vectors of the buckets to filename. The file is written to
+// a temporary location first and only moved into place once it is complete.
+func (b *Builder) Write(filename string) error {
+	tmp, err := os.Create(filepath.Join(filepath.Dir(filename), "."+filepath.Base(filename)+".tmp"))
+	if err != nil {
+		return fmt.Errorf("create subword file: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	w := bufio.NewWriter(tmp)
+	h := []uint32{fileVersion, uint32(b.options.MinN), uint32(b.options.MaxN),
+		uint32(b.options.Buckets), uint32(b.dimensions)}
+	if err := b.write(w, magic, h); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	mean := make([]float32, b.dimensions)
+	for bucket, count := range b.counts {
+		sum := b.sums[bucket*b.dimensions : (bucket+1)*b.dimensions]
+		for i := range mean {
+			mean[i] = 0
+			if count > 0 {
+				mean[i] = float32(sum[i] / float64(count))
+			}
+		}
+
+		if err := b.write(w, count, mean); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write subword file: %v", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close subword file: %v", err)
+	}
+
+	if err := os.Rename(tmp.Name(), filename); err != nil {
+		return fmt.Errorf("move subword file into place: %v", err)
+	}
+
+	return nil
+}
+
+func (b *Builder) write(w *bufio.Writer, values ...interface{}) error {
+	for _, v := range values {
+		if err := binary.Write(w, binary.LittleEndian, v); err != nil {
+			return fmt.Errorf("write subword file: %v", err)
+		}
+	}
+	return nil
+}
+
+// Model serves the vectors of the buckets from the mmapped file
+type Model struct {
+	options    Options
+	dimensions int
+	records    []byte
+	recordSize int
+	mmap       []byte
+}
+
+// Load mmaps a file previously written by a Builder. It errors if the
+// vectors of the file don't have the length of the contextionary's vectors.
+func Load(filename string, dimensions int) (*Model, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("open subword file: %v", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("stat subword file: %v", err)
+	}
+
+	if info.Size() < headerSize {
+		return nil, fmt.Errorf("%s is not a subword file", filename)
+	}
+
+	mmap, err := syscall.Mmap(int(file.Fd()), 0, int(info.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, fmt.Errorf("mmap subword file: %v", err)
+	}
+
+	m, err := newModel(mmap, filename, dimensions)
+	if err != nil {
+		syscall.Munmap(mmap)
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func newModel(mmap []byte, filename string, dimensions int) (*Model, error) {
+	var fileMagic [8]byte
+	copy(fileMagic[:], mmap[0:8])
+	if fileMagic != magic {
+		return nil, fmt.Errorf("%s is not a subword file", filename)
+	}
+
+	header := make([]int, 5)
+	for i := range header {
+		header[i] = int(binary.LittleEndian.Uint32(mmap[8+4*i:]))
+	}
+
+	if uint32(header[0]) != fileVersion {
+		return nil, fmt.Errorf("unsupported subword file version %d, expected %d", header[0], fileVersion)
+	}
+
+	options := Options{MinN: header[1], MaxN: header[2], Buckets: header[3]}
+	if err := options.validate(); err != nil {
+		return nil, fmt.Errorf("subword file %s: %v", filename, err)
+	}
+
+	if header[4] != dimensions {
+		return nil, fmt.Errorf("subword file contains vectors of length %d, but the contextionary has %d",
+			header[4], dimensions)
+	}
+
+	recordSize := 4 + 4*dimensions
+	if len(mmap) != headerSize+options.Buckets*recordSize {
+		return nil, fmt.Errorf("subword file has %d bytes, expected %d", len(mmap),
+			headerSize+options.Buckets*recordSize)
+	}
+
+	return &Model{
+		options:    options,
+		dimensions: dimensions,
+		records:    mmap[headerSize:],
+		recordSize: recordSize,
+		mmap:       mmap,
+	}, nil
+}
+
+// Close unmaps the file, the model must not be used afterwards
+func (m *Model) Close() error {
+	return syscall.Munmap(m.mmap)
+}
+
+// Vector synthesizes the vector of a word as the mean of the vectors of its
+// n-grams. It is nil if none of the n-grams occurs in any word of the
+// contextionary.
+func (m *Model) Vector(word string) []float32 {
+	sum := make([]float32, m.dimensions)
+	found := 0
+	for _, bucket := range m.options.buckets(word) {
+		record := m.records[bucket*m.recordSize : (bucket+1)*m.recordSize]
+		if binary.LittleEndian.Uint32(record[0:4]) == 0 {
+			continue
+		}
+
+		for i := range sum {
+			sum[i] += math.Float32frombits(binary.LittleEndian.Uint32(record[4+4*i:]))
+		}
+		found++
+	}
+
+	if found == 0 {
+		return nil
+	}
+
+	for i := range sum {
+		sum[i] /= float32(found)
+	}
+
+	return sum
+}
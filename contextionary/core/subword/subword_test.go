@@ -0,0 +1,91 @@
+/*                          _       _
+ *__      _____  __ ___   ___  __ _| |_ ___
+ *\ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+ * \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+ *  \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+ *
+ * Copyright © 2016 - 2019 Weaviate. All rights reserved.
+ * LICENSE: https://github.com/weaviate/weaviate/blob/master/LICENSE
+ * DESIGN & CONCEPT: Bob van Luijt (@bobvanluijt)
+ * CONTACT: hello@weaviate.io
+ */
+package subword
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func squaredEuclidean(a, b []float32) float32 {
+	var sum float32
+	for i := range a {
+		sum += (a[i] - b[i]) * (a[i] - b[i])
+	}
+	return sum
+}
+
+func TestSubwordVectors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "subword-test")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	words := map[string][]float32{
+		"contextionary": {1, 0, 0},
+		"dictionary":    {0.8, 0.2, 0},
+		"elephant":      {0, 1, 0},
+		"giraffe":       {0, 0, 1},
+	}
+
+	builder, err := NewBuilder(DefaultOptions, 3)
+	require.Nil(t, err)
+	for word, vector := range words {
+		require.Nil(t, builder.Add(word, vector))
+	}
+
+	filename := filepath.Join(dir, "c11y.knn.subwords")
+	require.Nil(t, builder.Write(filename))
+
+	m, err := Load(filename, 3)
+	require.Nil(t, err)
+	defer m.Close()
+
+	t.Run("a typo is closest to the word it was made in", func(t *testing.T) {
+		vector := m.Vector("elepahnt")
+		require.NotNil(t, vector)
+
+		closest, closestDistance := "", float32(0)
+		for word, wordVector := range words {
+			dist := squaredEuclidean(vector, wordVector)
+			if closest == "" || dist < closestDistance {
+				closest, closestDistance = word, dist
+			}
+		}
+		assert.Equal(t, "elephant", closest)
+	})
+
+	t.Run("a word without any known n-grams", func(t *testing.T) {
+		assert.Nil(t, m.Vector("xyz"))
+	})
+
+	t.Run("loading with the wrong dimensions", func(t *testing.T) {
+		_, err := Load(filename, 300)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("adding a vector with the wrong dimensions", func(t *testing.T) {
+		assert.NotNil(t, builder.Add("zebra", []float32{1, 2}))
+	})
+}
+
+func TestInvalidOptions(t *testing.T) {
+	_, err := NewBuilder(Options{MinN: 4, MaxN: 3, Buckets: 10}, 3)
+	assert.NotNil(t, err)
+
+	_, err = NewBuilder(Options{MinN: 3, MaxN: 6, Buckets: 0}, 3)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,244 @@
+/*                          _       _
+ *__      _____  __ ___   ___  __ _| |_ ___
+ *\ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+ * \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+ *  \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+ *
+ * Copyright © 2016 - 2019 Weaviate. All rights reserved.
+ * LICENSE: https://github.com/weaviate/weaviate/blob/master/LICENSE
+ * DESIGN & CONCEPT: Bob van Luijt (@bobvanluijt)
+ * CONTACT: hello@weaviate.io
+ */
+package contextionary
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"syscall"
+	"unicode/utf8"
+)
+
+// maxModelErrors limits the number of errors ValidateModel collects, a
+// broken file would otherwise produce an error for every single word
+const maxModelErrors = 100
+
+// ModelError is a single problem found by ValidateModel
+type ModelError struct {
+	// File is the path of the .idx or .knn file the problem was found in
+	File string `json:"file"`
+
+	// Check is the name of the failed check, e.g. "header" or "sort order"
+	Check string `json:"check"`
+
+	// Item is the index of the word the problem was found at, or -1 if the
+	// problem isn't specific to a word
+	Item int `json:"item"`
+
+	Message string `json:"message"`
+}
+
+func (e ModelError) Error() string {
+	if e.Item < 0 {
+		return fmt.Sprintf("%s: %s: %s", e.File, e.Check, e.Message)
+	}
+
+	return fmt.Sprintf("%s: %s: item %d: %s", e.File, e.Check, e.Item, e.Message)
+}
+
+// ModelValidationError is returned by ValidateModel, it contains all
+// problems that were found
+type ModelValidationError struct {
+	Errors []ModelError
+
+	// Truncated is set if there were more problems than listed in Errors
+	Truncated bool
+}
+
+func (e *ModelValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+
+	more := ""
+	if e.Truncated {
+		more = " (and more)"
+	}
+
+	return fmt.Sprintf("invalid model, found %d problems%s: %s", len(e.Errors), more, strings.Join(msgs, "; "))
+}
+
+type modelValidator struct {
+	errors    []ModelError
+	truncated bool
+}
+
+func (v *modelValidator) add(file, check string, item int, format string, args ...interface{}) {
+	if len(v.errors) >= maxModelErrors {
+		v.truncated = true
+		return
+	}
+
+	v.errors = append(v.errors, ModelError{File: file, Check: check, Item: item, Message: fmt.Sprintf(format, args...)})
+}
+
+// ValidateModel checks that the .idx wordlist and the .knn index form a
+// consistent model, which can be loaded safely. It checks the wordlist
+// header and metadata, the bounds, NUL-termination and alignment padding of
+// all words, that the words are strictly sorted, which the lookup by word
+// relies on, and that the knn index contains a vector for every word. It
+// returns nil or a *ModelValidationError listing all problems.
+func ValidateModel(knnFile, idxFile string) error {
+	v := &modelValidator{}
+
+	wl, ok := v.validateWordlist(idxFile)
+	if ok {
+		v.validateKnn(knnFile, wl)
+	}
+
+	if len(v.errors) == 0 {
+		return nil
+	}
+
+	return &ModelValidationError{Errors: v.errors, Truncated: v.truncated}
+}
+
+// validatedWordlist is the part of the wordlist the knn file is checked
+// against
+type validatedWordlist struct {
+	numberOfWords uint64
+	vectorWidth   uint64
+	metric        Metric
+}
+
+func (v *modelValidator) validateWordlist(path string) (validatedWordlist, bool) {
+	raw, err := mmapReadOnly(path, "wordlist")
+	if err != nil {
+		v.add(path, "file", -1, "%v", err)
+		return validatedWordlist{}, false
+	}
+	defer syscall.Munmap(raw)
+
+	if len(raw) < 24 {
+		v.add(path, "header", -1, "file has %d bytes, but the header alone needs 24", len(raw))
+		return validatedWordlist{}, false
+	}
+
+	wl := validatedWordlist{
+		numberOfWords: binary.LittleEndian.Uint64(raw[0:8]),
+		vectorWidth:   binary.LittleEndian.Uint64(raw[8:16]),
+	}
+	metadataLength := binary.LittleEndian.Uint64(raw[16:24])
+
+	if wl.vectorWidth == 0 {
+		v.add(path, "header", -1, "vector width must not be 0")
+	}
+
+	if metadataLength > uint64(len(raw)-24) {
+		v.add(path, "header", -1, "metadata length %d exceeds the file size", metadataLength)
+		return wl, false
+	}
+
+	var metadata map[string]interface{}
+	if err := json.Unmarshal(raw[24:24+metadataLength], &metadata); err != nil {
+		v.add(path, "metadata", -1, "metadata is not valid json: %v", err)
+	}
+
+	metricName, _ := metadata["metric"].(string)
+	if wl.metric, err = ParseMetric(metricName); err != nil {
+		v.add(path, "metadata", -1, "%v", err)
+	}
+
+	if maxDistance, _ := metadata["maxDistance"].(float64); maxDistance < 0 {
+		v.add(path, "metadata", -1, "maxDistance must not be negative, got %f", maxDistance)
+	}
+
+	if _, err := parsePrincipalComponent(metadata, int(wl.vectorWidth)); err != nil {
+		v.add(path, "metadata", -1, "%v", err)
+	}
+
+	// same computation as in LoadWordlist
+	startOfTable := 24 + metadataLength
+	startOfTable += 4 - (startOfTable % 4)
+
+	endOfTable := startOfTable + 8*wl.numberOfWords
+	if wl.numberOfWords > uint64(len(raw))/8 || endOfTable > uint64(len(raw)) {
+		v.add(path, "header", -1, "table of %d words exceeds the file size", wl.numberOfWords)
+		return wl, false
+	}
+
+	var previous []byte
+	for i := uint64(0); i < wl.numberOfWords; i++ {
+		item := int(i)
+		offset := binary.LittleEndian.Uint64(raw[startOfTable+8*i:])
+
+		if offset < endOfTable || offset > uint64(len(raw))-8 {
+			v.add(path, "bounds", item, "word offset %d is outside of the word section", offset)
+			previous = nil
+			continue
+		}
+
+		if offset%4 != 0 {
+			v.add(path, "alignment", item, "word offset %d is not 4-byte aligned", offset)
+		}
+
+		// the occurrence is followed by the NUL-terminated word
+		wordStart := offset + 8
+		length := bytes.IndexByte(raw[wordStart:], 0)
+		if length == -1 {
+			v.add(path, "termination", item, "word is not NUL-terminated")
+			previous = nil
+			continue
+		}
+		word := raw[wordStart : wordStart+uint64(length)]
+
+		if length == 0 {
+			v.add(path, "word", item, "word is empty")
+		} else if !utf8.Valid(word) {
+			v.add(path, "word", item, "word is not valid utf-8")
+		}
+
+		// the word is padded with zeros up to the next word, or the end of
+		// the file for the last word
+		paddingEnd := uint64(len(raw))
+		if i+1 < wl.numberOfWords {
+			paddingEnd = binary.LittleEndian.Uint64(raw[startOfTable+8*(i+1):])
+		}
+		paddingStart := wordStart + uint64(length) + 1
+		if paddingEnd < paddingStart {
+			v.add(path, "bounds", item, "word overlaps with the next word")
+		} else if paddingEnd-paddingStart > 4 || len(bytes.Trim(raw[paddingStart:paddingEnd], "\x00")) > 0 {
+			v.add(path, "alignment", item, "invalid padding of %d bytes after the word", paddingEnd-paddingStart)
+		}
+
+		if previous != nil && bytes.Compare(previous, word) >= 0 {
+			v.add(path, "sort order", item, "word '%s' is not sorted after '%s'", word, previous)
+		}
+		previous = word
+	}
+
+	return wl, true
+}
+
+func (v *modelValidator) validateKnn(path string, wl validatedWordlist) {
+	raw, err := loadAnnoyIndexDirectly(path)
+	if err != nil {
+		v.add(path, "file", -1, "%v", err)
+		return
+	}
+	defer syscall.Munmap(raw)
+
+	knn := wl.metric.newAnnoyIndex(int(wl.vectorWidth))
+	if err := knn.LoadBytes(raw); err != nil {
+		v.add(path, "size", -1, "%v", err)
+		return
+	}
+
+	if items := knn.GetNItems(); uint64(items) != wl.numberOfWords {
+		v.add(path, "size", -1, "knn index contains %d items, but the wordlist contains %d words",
+			items, wl.numberOfWords)
+	}
+}
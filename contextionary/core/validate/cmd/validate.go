@@ -0,0 +1,57 @@
+/*                          _       _
+ *__      _____  __ ___   ___  __ _| |_ ___
+ *\ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+ * \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+ *  \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+ *
+ * Copyright © 2016 - 2019 Weaviate. All rights reserved.
+ * LICENSE: https://github.com/weaviate/weaviate/blob/master/LICENSE
+ * DESIGN & CONCEPT: Bob van Luijt (@bobvanluijt)
+ * CONTACT: hello@weaviate.io
+ */
+package main
+
+import (
+	"fmt"
+	"os"
+
+	flags "github.com/jessevdk/go-flags"
+	contextionary "github.com/weaviate/contextionary/contextionary/core"
+)
+
+type Options struct {
+	KNNFile string `short:"k" long:"knn-file" description:"Path to the .knn file of the contextionary" required:"true"`
+	IDXFile string `short:"i" long:"idx-file" description:"Path to the .idx file of the contextionary" required:"true"`
+}
+
+func main() {
+	var options Options
+	var parser = flags.NewParser(&options, flags.Default)
+
+	if _, err := parser.Parse(); err != nil {
+		if flagsErr, ok := err.(*flags.Error); ok && flagsErr.Type == flags.ErrHelp {
+			os.Exit(0)
+		} else {
+			os.Exit(1)
+		}
+	}
+
+	err := contextionary.ValidateModel(options.KNNFile, options.IDXFile)
+	if err == nil {
+		fmt.Println("model is valid")
+		return
+	}
+
+	if modelErr, ok := err.(*contextionary.ModelValidationError); ok {
+		for _, e := range modelErr.Errors {
+			fmt.Fprintln(os.Stderr, e.Error())
+		}
+		if modelErr.Truncated {
+			fmt.Fprintln(os.Stderr, "... validation stopped after too many problems")
+		}
+	} else {
+		fmt.Fprintln(os.Stderr, err.Error())
+	}
+
+	os.Exit(1)
+}
@@ -0,0 +1,143 @@
+/*                          _       _
+ *__      _____  __ ___   ___  __ _| |_ ___
+ *\ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+ * \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+ *  \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+ *
+ * Copyright © 2016 - 2019 Weaviate. All rights reserved.
+ * LICENSE: https://github.com/weaviate/weaviate/blob/master/LICENSE
+ * DESIGN & CONCEPT: Bob van Luijt (@bobvanluijt)
+ * CONTACT: hello@weaviate.io
+ */
+package contextionary
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weaviate/contextionary/contextionary/core/generator"
+)
+
+func TestValidateModel(t *testing.T) {
+	tempdir, err := ioutil.TempDir("", "weaviate-vector-test")
+	require.Nil(t, err)
+	defer os.RemoveAll(tempdir)
+
+	generateFromDataset(t, tempdir, "apple 1 0\ncomputer 0 1\npie 1 1\n", generator.Options{})
+	knnFile, idxFile := tempdir+"/glove.knn", tempdir+"/glove.idx"
+
+	valid, err := ioutil.ReadFile(idxFile)
+	require.Nil(t, err)
+
+	// offsetOf returns the position of the word entry of item
+	offsetOf := func(raw []byte, item int) int {
+		metadataLength := int(binary.LittleEndian.Uint64(raw[16:24]))
+		startOfTable := 24 + metadataLength
+		startOfTable += 4 - startOfTable%4
+		return int(binary.LittleEndian.Uint64(raw[startOfTable+8*item:]))
+	}
+
+	t.Run("a valid model", func(t *testing.T) {
+		assert.Nil(t, ValidateModel(knnFile, idxFile))
+	})
+
+	tests := []struct {
+		name   string
+		mutate func(raw []byte) []byte
+		check  string
+		item   int
+	}{
+		{
+			name: "invalid json metadata",
+			mutate: func(raw []byte) []byte {
+				raw[24] = 'x'
+				return raw
+			},
+			check: "metadata",
+			item:  -1,
+		},
+		{
+			name: "unsorted words",
+			mutate: func(raw []byte) []byte {
+				// apple becomes zpple
+				raw[offsetOf(raw, 0)+8] = 'z'
+				return raw
+			},
+			check: "sort order",
+			item:  1,
+		},
+		{
+			name: "missing NUL-termination",
+			mutate: func(raw []byte) []byte {
+				// cut off right after 'pie'
+				return raw[:offsetOf(raw, 2)+8+3]
+			},
+			check: "termination",
+			item:  2,
+		},
+		{
+			name: "non-zero padding",
+			mutate: func(raw []byte) []byte {
+				// 'pie' is followed by the NUL and the padding
+				raw[offsetOf(raw, 2)+8+4] = 'x'
+				return raw
+			},
+			check: "alignment",
+			item:  2,
+		},
+		{
+			name: "fewer words in the header than in the file",
+			mutate: func(raw []byte) []byte {
+				// the last word is now treated as padding of the previous one
+				binary.LittleEndian.PutUint64(raw[0:8], 2)
+				return raw
+			},
+			check: "alignment",
+			item:  1,
+		},
+		{
+			name: "a table exceeding the file",
+			mutate: func(raw []byte) []byte {
+				binary.LittleEndian.PutUint64(raw[0:8], 1<<40)
+				return raw
+			},
+			check: "header",
+			item:  -1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			raw := test.mutate(append([]byte(nil), valid...))
+			require.Nil(t, ioutil.WriteFile(tempdir+"/broken.idx", raw, 0644))
+
+			err := ValidateModel(knnFile, tempdir+"/broken.idx")
+			require.IsType(t, &ModelValidationError{}, err)
+			modelErr := err.(*ModelValidationError)
+			require.NotEmpty(t, modelErr.Errors)
+			assert.Equal(t, test.check, modelErr.Errors[0].Check, modelErr.Error())
+			assert.Equal(t, test.item, modelErr.Errors[0].Item, modelErr.Error())
+		})
+	}
+
+	t.Run("a knn file of a different model", func(t *testing.T) {
+		otherdir, err := ioutil.TempDir("", "weaviate-vector-test")
+		require.Nil(t, err)
+		defer os.RemoveAll(otherdir)
+		generateFromDataset(t, otherdir, "apple 1 0\npie 1 1\n", generator.Options{})
+
+		err = ValidateModel(otherdir+"/glove.knn", idxFile)
+		require.IsType(t, &ModelValidationError{}, err)
+		assert.Equal(t, "size", err.(*ModelValidationError).Errors[0].Check)
+	})
+
+	t.Run("missing files", func(t *testing.T) {
+		err := ValidateModel(knnFile, tempdir+"/missing.idx")
+		require.IsType(t, &ModelValidationError{}, err)
+		assert.Equal(t, "file", err.(*ModelValidationError).Errors[0].Check)
+	})
+}
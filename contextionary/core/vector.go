@@ -13,7 +13,6 @@ package contextionary
 
 import (
 	"fmt"
-	"math"
 )
 
 // Opque type that models a fixed-length vector.
@@ -26,6 +25,10 @@ type InputElement struct {
 	Concept    string
 	Weight     float64
 	Occurrence uint64
+
+	// Synthesized is set if the concept isn't in the contextionary and its
+	// vector was synthesized from its character n-grams
+	Synthesized bool
 }
 
 func NewVector(vector []float32) Vector {
@@ -94,18 +97,3 @@ func (v *Vector) ToArray() []float32 {
 
 	return returner
 }
-
-func (v *Vector) Distance(other *Vector) (float32, error) {
-	var sum float32
-
-	if len(v.vector) != len(other.vector) {
-		return 0.0, fmt.Errorf("Vectors have different dimensions")
-	}
-
-	for i := 0; i < len(v.vector); i++ {
-		x := v.vector[i] - other.vector[i]
-		sum += x * x
-	}
-
-	return float32(math.Sqrt(float64(sum))), nil
-}
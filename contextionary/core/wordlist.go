@@ -28,7 +28,11 @@ type Wordlist struct {
 	vectorWidth           uint64
 	numberOfWords         uint64
 	metadata              map[string]interface{}
+	metric                Metric
+	maxDistance           float32
+	principalComponent    []float32
 	occurrencePercentiles []uint64
+	totalOccurrence       uint64
 
 	file         os.File
 	startOfTable int
@@ -62,7 +66,28 @@ func LoadWordlist(path string) (*Wordlist, error) {
 	metadataBytes := mmap[24 : 24+metadataLength]
 	var metadata map[string]interface{}
 
-	json.Unmarshal(metadataBytes, &metadata)
+	if err := json.Unmarshal(metadataBytes, &metadata); err != nil {
+		return nil, fmt.Errorf("invalid metadata in wordlist at %s: %v", path, err)
+	}
+
+	metricName, _ := metadata["metric"].(string)
+	metric, err := ParseMetric(metricName)
+	if err != nil {
+		return nil, fmt.Errorf("invalid metadata in wordlist at %s: %v", path, err)
+	}
+
+	// optional, the certainty calibration is sampled at load time otherwise
+	maxDistance, _ := metadata["maxDistance"].(float64)
+	if maxDistance < 0 {
+		return nil, fmt.Errorf("invalid metadata in wordlist at %s: maxDistance must not be negative, got %f",
+			path, maxDistance)
+	}
+
+	// optional, written by the generator's --principal-component option
+	principalComponent, err := parsePrincipalComponent(metadata, int(vectorWidth))
+	if err != nil {
+		return nil, fmt.Errorf("invalid metadata in wordlist at %s: %v", path, err)
+	}
 
 	// Compute beginning of word list lookup table.
 	var start_of_table int = 24 + int(metadataLength)
@@ -73,8 +98,12 @@ func LoadWordlist(path string) (*Wordlist, error) {
 		vectorWidth:   vectorWidth,
 		numberOfWords: nrWords,
 		metadata:      metadata,
+		metric:        metric,
+		maxDistance:   float32(maxDistance),
 		startOfTable:  start_of_table,
-		mmap:          mmap,
+
+		principalComponent: principalComponent,
+		mmap:               mmap,
 	}
 
 	wl.initOccurrencePercentiles()
@@ -82,10 +111,42 @@ func LoadWordlist(path string) (*Wordlist, error) {
 	return wl, nil
 }
 
+// Close unmaps the wordlist, it must not be used afterwards
+func (w *Wordlist) Close() error {
+	if err := syscall.Munmap(w.mmap); err != nil {
+		return fmt.Errorf("munmap wordlist: %v", err)
+	}
+
+	return nil
+}
+
 func (w *Wordlist) GetNumberOfWords() ItemIndex {
 	return ItemIndex(w.numberOfWords)
 }
 
+// Metric is the distance metric the vectors were trained for, as recorded in
+// the metadata
+func (w *Wordlist) Metric() Metric {
+	return w.metric
+}
+
+// MaxDistance is the distance corresponding to a certainty of 0 as recorded
+// in the metadata, or 0 if the model doesn't specify one
+func (w *Wordlist) MaxDistance() float32 {
+	return w.maxDistance
+}
+
+// PrincipalComponent is the first principal component of the model as
+// recorded in the metadata, or nil if the model doesn't specify one
+func (w *Wordlist) PrincipalComponent() []float32 {
+	return w.principalComponent
+}
+
+// TotalOccurrence is the sum of the occurrences of all words
+func (w *Wordlist) TotalOccurrence() uint64 {
+	return w.totalOccurrence
+}
+
 func (w *Wordlist) OccurrencePercentile(percentile int) uint64 {
 	if percentile < 0 || percentile > 100 {
 		panic("incorrect usage of occurrence percentile, must be between 0 and 100")
@@ -160,6 +221,7 @@ func (w *Wordlist) initOccurrencePercentiles() {
 	for i := ItemIndex(0); int(i) < max; i++ {
 		_, occ := w.getWord(i)
 		allOccs[i] = occ
+		w.totalOccurrence += occ
 	}
 
 	sort.Slice(allOccs, func(a, b int) bool { return allOccs[a] < allOccs[b] })
@@ -179,3 +241,26 @@ func (w *Wordlist) initOccurrencePercentiles() {
 		w.occurrencePercentiles[i] = occ
 	}
 }
+
+func parsePrincipalComponent(metadata map[string]interface{}, vectorWidth int) ([]float32, error) {
+	raw, ok := metadata["principalComponent"]
+	if !ok {
+		return nil, nil
+	}
+
+	values, ok := raw.([]interface{})
+	if !ok || len(values) != vectorWidth {
+		return nil, fmt.Errorf("principalComponent must be a list of %d numbers", vectorWidth)
+	}
+
+	out := make([]float32, len(values))
+	for i, value := range values {
+		number, ok := value.(float64)
+		if !ok {
+			return nil, fmt.Errorf("principalComponent must be a list of %d numbers", vectorWidth)
+		}
+		out[i] = float32(number)
+	}
+
+	return out, nil
+}
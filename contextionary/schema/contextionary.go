@@ -10,18 +10,31 @@
  * CONTACT: hello@weaviate.io
  */package schema
 
-import contextionary "github.com/weaviate/contextionary/contextionary/core"
+import (
+	"strings"
+
+	contextionary "github.com/weaviate/contextionary/contextionary/core"
+)
 
 // Contextionary composes a regular contextionary with additional
 // schema-related query methods
 type Contextionary struct {
 	contextionary.Contextionary
+	normalize func(word string) string
 }
 
 // New creates a new Contextionary from a contextionary.Contextionary which it
 // extends with Schema-related search methods
 func New(c contextionary.Contextionary) *Contextionary {
+	return NewWithNormalizer(c, strings.ToLower)
+}
+
+// NewWithNormalizer creates a new Contextionary which normalizes the parts of
+// class and property names as well as keywords with normalize before they are
+// looked up
+func NewWithNormalizer(c contextionary.Contextionary, normalize func(word string) string) *Contextionary {
 	return &Contextionary{
 		Contextionary: c,
+		normalize:     normalize,
 	}
 }
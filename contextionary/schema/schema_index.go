@@ -0,0 +1,101 @@
+/*                          _       _
+ *__      _____  __ ___   ___  __ _| |_ ___
+ *\ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+ * \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+ *  \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+ *
+ * Copyright © 2016 - 2019 Weaviate. All rights reserved.
+ * LICENSE: https://github.com/weaviate/weaviate/blob/master/LICENSE
+ * DESIGN & CONCEPT: Bob van Luijt (@bobvanluijt)
+ * CONTACT: hello@weaviate.io
+ */package schema
+
+import (
+	"fmt"
+
+	pb "github.com/weaviate/contextionary/contextionary"
+	contextionary "github.com/weaviate/contextionary/contextionary/core"
+)
+
+// Class of the weaviate schema, only the parts which are relevant for the
+// schema search are parsed
+type Class struct {
+	Name       string     `json:"class"`
+	Keywords   []Keyword  `json:"keywords"`
+	Properties []Property `json:"properties"`
+}
+
+// Property of a Class
+type Property struct {
+	Name     string    `json:"name"`
+	Keywords []Keyword `json:"keywords"`
+}
+
+// Keyword to describe a class or property in addition to its name
+type Keyword struct {
+	Keyword string  `json:"keyword"`
+	Weight  float32 `json:"weight"`
+}
+
+// ClassWord is the word under which the centroid of a class is stored in the
+// schema index, this is what the class search extracts the class names from
+func ClassWord(class string) string {
+	return fmt.Sprintf("$OBJECT[%s]", class)
+}
+
+// PropertyWord is the word under which the centroid of a property is stored
+// in the schema index, this is what the property search extracts the
+// property names from
+func PropertyWord(class, property string) string {
+	return fmt.Sprintf("$OBJECT[%s][%s]", class, property)
+}
+
+// BuildIndex computes a centroid of the camelCased name and the keywords of
+// every class and property, the same way a SchemaSearch computes the
+// centroid of its search params. The index is meant to be combined with the
+// contextionary through contextionary.CombineVectorIndices.
+//
+// Classes and properties which can't be vectorized, e.g. because their name
+// isn't in the contextionary, are left out of the index and reported in the
+// returned errors. The index is nil if there is nothing to index.
+func (con *Contextionary) BuildIndex(classes []Class) (*contextionary.MemoryIndex, []error) {
+	builder := contextionary.InMemoryBuilderWithMetric(con.GetVectorLength(), con.Metric())
+	var errs []error
+	count := 0
+
+	add := func(word, name string, keywords []Keyword) {
+		centroid, err := con.centroidFromNameAndKeywords(SearchParams{&pb.SchemaSearchParams{
+			Name:     name,
+			Keywords: keywordsToProto(keywords),
+		}})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", word, err))
+			return
+		}
+
+		builder.AddWord(word, *centroid)
+		count++
+	}
+
+	for _, class := range classes {
+		add(ClassWord(class.Name), class.Name, class.Keywords)
+		for _, prop := range class.Properties {
+			add(PropertyWord(class.Name, prop.Name), prop.Name, prop.Keywords)
+		}
+	}
+
+	if count == 0 {
+		return nil, errs
+	}
+
+	return builder.Build(3), errs
+}
+
+func keywordsToProto(in []Keyword) []*pb.Keyword {
+	out := make([]*pb.Keyword, len(in))
+	for i, kw := range in {
+		out[i] = &pb.Keyword{Keyword: kw.Keyword, Weight: kw.Weight}
+	}
+
+	return out
+}
@@ -0,0 +1,84 @@
+/*                          _       _
+ *__      _____  __ ___   ___  __ _| |_ ___
+ *\ \ /\ / / _ \/ _` \ \ / / |/ _` | __/ _ \
+ * \ V  V /  __/ (_| |\ V /| | (_| | ||  __/
+ *  \_/\_/ \___|\__,_| \_/ |_|\__,_|\__\___|
+ *
+ * Copyright © 2016 - 2019 Weaviate. All rights reserved.
+ * LICENSE: https://github.com/weaviate/weaviate/blob/master/LICENSE
+ * DESIGN & CONCEPT: Bob van Luijt (@bobvanluijt)
+ * CONTACT: hello@weaviate.io
+ */package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	pb "github.com/weaviate/contextionary/contextionary"
+	contextionary "github.com/weaviate/contextionary/contextionary/core"
+)
+
+func Test__SchemaIndex(t *testing.T) {
+	builder := contextionary.InMemoryBuilder(3)
+	for word, vector := range map[string][]float32{
+		"city":       {5, 5, 5},
+		"town":       {5, 4, 5},
+		"population": {1, 5, 1},
+		"people":     {1, 4, 1},
+		"car":        {1, 1, 5},
+	} {
+		builder.AddWord(word, contextionary.NewVector(vector))
+	}
+	raw := builder.Build(3)
+
+	index, errs := New(raw).BuildIndex([]Class{
+		{
+			Name:     "City",
+			Keywords: []Keyword{{Keyword: "town", Weight: 0.5}},
+			Properties: []Property{
+				{Name: "population"},
+				{Name: "mayorName"},
+			},
+		},
+	})
+	require.NotNil(t, index)
+	assert.Len(t, errs, 1, "the unknown property should be reported")
+	assert.Equal(t, 2, index.GetNumberOfItems())
+	for _, word := range []string{"$OBJECT[City]", "$OBJECT[City][population]"} {
+		i := index.WordToItemIndex(word)
+		assert.True(t, i.IsPresent(), "%s should be in the index", word)
+	}
+
+	combined, err := contextionary.CombineVectorIndices([]contextionary.Contextionary{raw, index})
+	require.Nil(t, err)
+	c11y := New(combined)
+
+	t.Run("searching for a class", func(t *testing.T) {
+		res, err := c11y.SchemaSearch(&pb.SchemaSearchParams{
+			SearchType: pb.SearchType_CLASS,
+			Name:       "Town",
+			Certainty:  0.5,
+		})
+		require.Nil(t, err)
+		require.Len(t, res.Results, 1)
+		assert.Equal(t, "City", res.Results[0].Name)
+	})
+
+	t.Run("searching for a property", func(t *testing.T) {
+		res, err := c11y.SchemaSearch(&pb.SchemaSearchParams{
+			SearchType: pb.SearchType_PROPERTY,
+			Name:       "people",
+			Certainty:  0.5,
+		})
+		require.Nil(t, err)
+		require.Len(t, res.Results, 1)
+		assert.Equal(t, "population", res.Results[0].Name)
+	})
+
+	t.Run("without anything to index", func(t *testing.T) {
+		index, errs := New(raw).BuildIndex([]Class{{Name: "Spaceship"}})
+		assert.Nil(t, index)
+		assert.Len(t, errs, 1)
+	})
+}
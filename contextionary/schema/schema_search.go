@@ -13,7 +13,6 @@
 import (
 	"fmt"
 	"regexp"
-	"strings"
 
 	"github.com/fatih/camelcase"
 	pb "github.com/weaviate/contextionary/contextionary"
@@ -118,7 +117,7 @@ func (con *Contextionary) camelCaseWordToVector(w string) (*contextionary.Vector
 }
 
 func (con *Contextionary) wordToVector(w string) (*contextionary.Vector, error) {
-	w = strings.ToLower(w)
+	w = con.normalize(w)
 	itemIndex := con.WordToItemIndex(w)
 	if ok := itemIndex.IsPresent(); !ok {
 		return nil, fmt.Errorf(
@@ -137,14 +136,14 @@ func (con *Contextionary) wordToVector(w string) (*contextionary.Vector, error)
 func (con *Contextionary) handleClassSearch(p SearchParams, search rawResults) (*pb.SchemaSearchResults, error) {
 	return &pb.SchemaSearchResults{
 		Type:    p.SearchType,
-		Results: search.extractClassNames(p),
+		Results: search.extractClassNames(p, con.CertaintyCalibration()),
 	}, nil
 }
 
 func (con *Contextionary) handlePropertySearch(p SearchParams, search rawResults) (*pb.SchemaSearchResults, error) {
 	return &pb.SchemaSearchResults{
 		Type:    p.SearchType,
-		Results: search.extractPropertyNames(p),
+		Results: search.extractPropertyNames(p, con.CertaintyCalibration()),
 	}, nil
 }
 
@@ -181,13 +180,13 @@ type rawResult struct {
 
 type rawResults []rawResult
 
-func (r rawResults) extractClassNames(p SearchParams) []*pb.SchemaSearchResult {
+func (r rawResults) extractClassNames(p SearchParams, calibration contextionary.CertaintyCalibration) []*pb.SchemaSearchResult {
 	var results []*pb.SchemaSearchResult
 	regex := regexp.MustCompile(fmt.Sprintf("^\\$%s\\[([A-Za-z]+)\\]$", "OBJECT"))
 
 	for _, rawRes := range r {
 		if regex.MatchString(rawRes.name) {
-			certainty := distanceToCertainty(rawRes.distance)
+			certainty := calibration.DistanceToCertainty(rawRes.distance)
 			if certainty < p.Certainty {
 				continue
 			}
@@ -202,7 +201,7 @@ func (r rawResults) extractClassNames(p SearchParams) []*pb.SchemaSearchResult {
 	return results
 }
 
-func (r rawResults) extractPropertyNames(p SearchParams) []*pb.SchemaSearchResult {
+func (r rawResults) extractPropertyNames(p SearchParams, calibration contextionary.CertaintyCalibration) []*pb.SchemaSearchResult {
 	var results []*pb.SchemaSearchResult
 	regex := regexp.MustCompile("^\\$[A-Za-z]+\\[[A-Za-z]+\\]\\[([A-Za-z]+)\\]$")
 
@@ -211,7 +210,7 @@ func (r rawResults) extractPropertyNames(p SearchParams) []*pb.SchemaSearchResul
 	for _, rawRes := range r {
 		if regex.MatchString(rawRes.name) {
 			name := regex.FindStringSubmatch(rawRes.name)[1] //safe because we ran .MatchString before
-			certainty := distanceToCertainty(rawRes.distance)
+			certainty := calibration.DistanceToCertainty(rawRes.distance)
 			if certainty < p.Certainty {
 				continue
 			}
@@ -247,7 +246,3 @@ func meanCertainty(rs []*pb.SchemaSearchResult) float32 {
 
 	return compound / float32(len(rs))
 }
-
-func distanceToCertainty(d float32) float32 {
-	return 1 - d/12
-}
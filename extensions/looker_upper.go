@@ -1,13 +1,26 @@
 package extensions
 
 import (
+	"context"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 type LookerUpper struct {
+	// generation is incremented whenever the extensions change, first for
+	// its alignment
+	generation uint64
+
 	repo RetrieverRepo
 	sync.Mutex
 	db map[string]Extension
+
+	// lastSync is when the extensions were last received from the repo
+	lastSync time.Time
+
+	// synced is closed once the extensions were received for the first time
+	synced chan struct{}
 }
 
 type RetrieverRepo interface {
@@ -18,14 +31,19 @@ type RetrieverRepo interface {
 
 func NewLookerUpper(repo RetrieverRepo) *LookerUpper {
 	lu := &LookerUpper{
-		repo: repo,
-		db:   map[string]Extension{},
+		repo:   repo,
+		db:     map[string]Extension{},
+		synced: make(chan struct{}),
 	}
 	lu.initWatcher()
 	return lu
 }
 
-func (lu *LookerUpper) Lookup(concept string) (*Extension, error) {
+func (lu *LookerUpper) Lookup(ctx context.Context, concept string) (*Extension, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	lu.Lock()
 	defer lu.Unlock()
 
@@ -56,4 +74,38 @@ func (lu *LookerUpper) updateDB(list []Extension) {
 	for _, ext := range list {
 		lu.db[ext.Concept] = ext
 	}
+	atomic.AddUint64(&lu.generation, 1)
+	if lu.lastSync.IsZero() {
+		close(lu.synced)
+	}
+	lu.lastSync = time.Now()
+}
+
+// Len is the number of extensions
+func (lu *LookerUpper) Len() int {
+	lu.Lock()
+	defer lu.Unlock()
+
+	return len(lu.db)
+}
+
+// LastSync is when the extensions were last received from the repo, the zero
+// time if they never were
+func (lu *LookerUpper) LastSync() time.Time {
+	lu.Lock()
+	defer lu.Unlock()
+
+	return lu.lastSync
+}
+
+// Synced is closed once the extensions were received from the repo for the
+// first time, lookups before may miss extensions which exist
+func (lu *LookerUpper) Synced() <-chan struct{} {
+	return lu.synced
+}
+
+// Generation changes whenever the extensions change, anything derived from
+// the lookups of an earlier generation may be outdated
+func (lu *LookerUpper) Generation() uint64 {
+	return atomic.LoadUint64(&lu.generation)
 }
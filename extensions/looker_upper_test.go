@@ -1,6 +1,7 @@
 package extensions
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -12,11 +13,30 @@ func Test_LookerUpper(t *testing.T) {
 	t.Run("looking up a non-existant concept", func(t *testing.T) {
 		repo := newFakeRepo()
 		lu := NewLookerUpper(repo)
-		extension, err := lu.Lookup("non_existing_concept")
+		extension, err := lu.Lookup(context.Background(), "non_existing_concept")
 		require.Nil(t, err)
 		assert.Nil(t, extension)
 	})
 
+	t.Run("looking up with a cancelled context", func(t *testing.T) {
+		repo := newFakeRepo()
+		lu := NewLookerUpper(repo)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := lu.Lookup(ctx, "flux_capacitor")
+		assert.Equal(t, context.Canceled, err)
+	})
+
+	t.Run("before the first sync", func(t *testing.T) {
+		lu := NewLookerUpper(newFakeRepo())
+		select {
+		case <-lu.Synced():
+			t.Fatal("looker upper is synced before it received the extensions")
+		default:
+		}
+	})
+
 	t.Run("looking up existing concepts", func(t *testing.T) {
 		repo := newFakeRepo()
 		lu := NewLookerUpper(repo)
@@ -29,9 +49,16 @@ func Test_LookerUpper(t *testing.T) {
 			}
 			repo.add(ext)
 			time.Sleep(100 * time.Millisecond)
-			actual, err := lu.Lookup("flux_capacitor")
+			actual, err := lu.Lookup(context.Background(), "flux_capacitor")
 			require.Nil(t, err)
 			assert.Equal(t, &ext, actual)
+			assert.Equal(t, 1, lu.Len())
+			assert.False(t, lu.LastSync().IsZero())
+			select {
+			case <-lu.Synced():
+			default:
+				t.Fatal("looker upper isn't synced after it received the extensions")
+			}
 		})
 
 		t.Run("with second concept", func(t *testing.T) {
@@ -44,14 +71,14 @@ func Test_LookerUpper(t *testing.T) {
 			time.Sleep(100 * time.Millisecond)
 
 			t.Run("looking up the original concept", func(t *testing.T) {
-				actual, err := lu.Lookup("flux_capacitor")
+				actual, err := lu.Lookup(context.Background(), "flux_capacitor")
 				require.Nil(t, err)
 				require.NotNil(t, actual)
 				assert.Equal(t, "flux_capacitor", actual.Concept)
 			})
 
 			t.Run("looking up the second concept concept", func(t *testing.T) {
-				actual, err := lu.Lookup("clux_fapacitor")
+				actual, err := lu.Lookup(context.Background(), "clux_fapacitor")
 				require.Nil(t, err)
 				require.NotNil(t, actual)
 				assert.Equal(t, "clux_fapacitor", actual.Concept)
@@ -0,0 +1,86 @@
+package extensions
+
+import (
+	"context"
+	"strings"
+)
+
+// Repo can both store and watch extensions
+type Repo interface {
+	RetrieverRepo
+	StorerRepo
+}
+
+// namespaceSeparator can't be part of a concept, as concepts are validated
+// to be made up of lowercase letters, numbers and spaces only
+const namespaceSeparator = ":"
+
+// NamespacedRepo shares a single repo between several languages. The
+// extensions of a namespace are stored with the concept prefixed by
+// "<namespace>:". The empty namespace stores concepts without a prefix, so
+// that the extensions which were created before there were several
+// languages remain in place.
+type NamespacedRepo struct {
+	repo      Repo
+	namespace string
+}
+
+func NewNamespacedRepo(repo Repo, namespace string) *NamespacedRepo {
+	return &NamespacedRepo{repo: repo, namespace: namespace}
+}
+
+func (r *NamespacedRepo) Put(ctx context.Context, ext Extension) error {
+	ext.Concept = r.prefix() + ext.Concept
+	return r.repo.Put(ctx, ext)
+}
+
+// WatchAll only passes on the extensions of this namespace, with the prefix
+// removed from their concepts
+func (r *NamespacedRepo) WatchAll() chan WatchResponse {
+	in := r.repo.WatchAll()
+	out := make(chan WatchResponse)
+
+	go func() {
+		defer close(out)
+		for res := range in {
+			out <- r.filter(res)
+		}
+	}()
+
+	return out
+}
+
+func (r *NamespacedRepo) filter(in WatchResponse) WatchResponse {
+	out := WatchResponse{}
+	for _, ext := range in {
+		concept, ok := r.trim(ext.Concept)
+		if !ok {
+			continue
+		}
+
+		ext.Concept = concept
+		out = append(out, ext)
+	}
+
+	return out
+}
+
+func (r *NamespacedRepo) prefix() string {
+	if r.namespace == "" {
+		return ""
+	}
+
+	return r.namespace + namespaceSeparator
+}
+
+func (r *NamespacedRepo) trim(concept string) (string, bool) {
+	if r.namespace == "" {
+		return concept, !strings.Contains(concept, namespaceSeparator)
+	}
+
+	if !strings.HasPrefix(concept, r.prefix()) {
+		return "", false
+	}
+
+	return strings.TrimPrefix(concept, r.prefix()), true
+}
@@ -0,0 +1,51 @@
+package extensions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NamespacedRepo(t *testing.T) {
+	t.Run("storing extensions", func(t *testing.T) {
+		shared := &fakeSharedRepo{fakeRepo: newFakeRepo()}
+		english := NewNamespacedRepo(shared, "")
+		german := NewNamespacedRepo(shared, "de")
+
+		require.Nil(t, english.Put(context.Background(), Extension{Concept: "flux_capacitor"}))
+		require.Nil(t, german.Put(context.Background(), Extension{Concept: "fluxkompensator"}))
+
+		assert.Equal(t, []string{"flux_capacitor", "de:fluxkompensator"}, shared.stored)
+	})
+
+	t.Run("watching extensions", func(t *testing.T) {
+		all := WatchResponse{
+			{Concept: "flux_capacitor"},
+			{Concept: "de:fluxkompensator"},
+			{Concept: "nl:fluxcondensator"},
+		}
+
+		for namespace, expected := range map[string]WatchResponse{
+			"":   {{Concept: "flux_capacitor"}},
+			"de": {{Concept: "fluxkompensator"}},
+			"fr": {},
+		} {
+			shared := &fakeSharedRepo{fakeRepo: newFakeRepo()}
+			ch := NewNamespacedRepo(shared, namespace).WatchAll()
+			shared.ch <- all
+			assert.Equal(t, expected, <-ch, "namespace '%s'", namespace)
+		}
+	})
+}
+
+type fakeSharedRepo struct {
+	*fakeRepo
+	stored []string
+}
+
+func (f *fakeSharedRepo) Put(ctx context.Context, ext Extension) error {
+	f.stored = append(f.stored, ext.Concept)
+	return nil
+}
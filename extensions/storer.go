@@ -12,7 +12,7 @@ import (
 )
 
 type Vectorizer interface {
-	Corpi(corpi []string, overrides map[string]string) (*core.Vector, error)
+	Corpi(ctx context.Context, corpi []string, overrides map[string]string) (*core.Vector, error)
 }
 
 type StorerRepo interface {
@@ -40,8 +40,12 @@ func (s *Storer) Put(ctx context.Context, concept string, input ExtensionInput)
 		return errors.NewInvalidUserInputf("invalid extension: %v", err)
 	}
 
-	vector, err := s.vectorizer.Corpi([]string{input.Definition}, nil)
+	vector, err := s.vectorizer.Corpi(ctx, []string{input.Definition}, nil)
 	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
 		return errors.NewInternalf("vectorize definition: %v", err)
 	}
 
@@ -119,7 +119,7 @@ func Test_Storer(t *testing.T) {
 
 type fakeVectorizer struct{}
 
-func (f *fakeVectorizer) Corpi(corpi []string, overrides map[string]string) (*core.Vector, error) {
+func (f *fakeVectorizer) Corpi(ctx context.Context, corpi []string, overrides map[string]string) (*core.Vector, error) {
 	v := core.NewVector([]float32{1, 2, 3})
 	return &v, nil
 }
@@ -0,0 +1,107 @@
+// Package normalization brings words and corpi into a canonical form before
+// they are looked up in the contextionary, so that e.g. "Café", "CAFÉ" and
+// "Café" written with a combining accent all resolve to the same word.
+package normalization
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// NFC composes characters, it only unifies different encodings of the
+	// same character
+	NFC = "nfc"
+	// NFKC additionally replaces compatibility characters, such as full-width
+	// letters or superscript digits, with their plain equivalents
+	NFKC = "nfkc"
+)
+
+// Normalizer applies the following steps in order:
+//
+//  1. typographic quotes and ligatures are replaced with their plain
+//     equivalents, e.g. "’" with "'" and "ﬁ" with "fi"
+//  2. the unicode normalization form (NFC or NFKC)
+//  3. lowercasing by the rules of the language, e.g. "CAFÉ" becomes "café"
+//     and "İ" becomes "i" in Turkish. Unlike full case folding, it keeps
+//     letters such as "ß" and the Greek final sigma, which the words of the
+//     contextionaries are written with.
+//  4. optionally diacritic folding, e.g. "café" becomes "cafe". This only
+//     makes sense if the words of the contextionary are folded as well.
+//
+// A Normalizer is safe for concurrent use.
+type Normalizer struct {
+	form           norm.Form
+	language       language.Tag
+	foldDiacritics bool
+}
+
+// New creates a normalizer with the specified unicode normalization form,
+// which must be NFC or NFKC, for the language, e.g. "de". Languages which
+// can't be parsed are lowercased by the language independent rules.
+func New(form string, lang string, foldDiacritics bool) (*Normalizer, error) {
+	tag, err := language.Parse(lang)
+	if err != nil {
+		tag = language.Und
+	}
+
+	n := &Normalizer{language: tag, foldDiacritics: foldDiacritics}
+	switch form {
+	case NFC:
+		n.form = norm.NFC
+	case NFKC:
+		n.form = norm.NFKC
+	default:
+		return nil, fmt.Errorf("unknown unicode normalization form '%s', must be one of '%s' or '%s'",
+			form, NFC, NFKC)
+	}
+
+	return n, nil
+}
+
+var typographic = strings.NewReplacer(
+	"‘", "'", "’", "'", "‚", "'", "‛", "'", "′", "'",
+	"“", "\"", "”", "\"", "„", "\"", "‟", "\"", "″", "\"",
+	"ﬀ", "ff", "ﬁ", "fi", "ﬂ", "fl", "ﬃ", "ffi", "ﬄ", "ffl", "ﬅ", "st", "ﬆ", "st",
+)
+
+// Normalize returns the canonical form of the input
+func (n *Normalizer) Normalize(input string) string {
+	out := typographic.Replace(input)
+	out = n.form.String(out)
+	// a caser keeps state, so it can't be shared between goroutines
+	out = cases.Lower(n.language).String(out)
+
+	if n.foldDiacritics {
+		out = foldDiacritics(out)
+	}
+
+	// lowercasing and removing diacritics can both leave the string in a
+	// different normalization form
+	return n.form.String(out)
+}
+
+// letters which carry a diacritic, but don't decompose into a base letter and
+// a combining mark
+var withoutDecomposition = map[rune]rune{
+	'ø': 'o', 'ł': 'l', 'đ': 'd', 'ħ': 'h', 'ŧ': 't',
+}
+
+func foldDiacritics(in string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Mn, r) {
+			return -1
+		}
+
+		if base, ok := withoutDecomposition[r]; ok {
+			return base
+		}
+
+		return r
+	}, norm.NFD.String(in))
+}
@@ -0,0 +1,126 @@
+package normalization
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Normalizer(t *testing.T) {
+	type testcase struct {
+		name   string
+		input  []string
+		output string
+	}
+
+	run := func(t *testing.T, n *Normalizer, tests []testcase) {
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				for _, input := range test.input {
+					assert.Equal(t, test.output, n.Normalize(input), "normalizing %q", input)
+				}
+			})
+		}
+	}
+
+	t.Run("with nfkc and without diacritic folding", func(t *testing.T) {
+		n, err := New(NFKC, "de", false)
+		require.Nil(t, err)
+
+		run(t, n, []testcase{
+			{
+				name:   "lowercasing",
+				input:  []string{"café", "Café", "CAFÉ", "café"},
+				output: "café",
+			},
+			{
+				name:   "sharp s is kept",
+				input:  []string{"Straße", "straße"},
+				output: "straße",
+			},
+			{
+				name:   "uppercased sharp s",
+				input:  []string{"STRASSE"},
+				output: "strasse",
+			},
+			{
+				name:   "greek final sigma is kept",
+				input:  []string{"Οδός", "ΟΔΌΣ"},
+				output: "οδός",
+			},
+			{
+				name:   "compatibility characters",
+				input:  []string{"ｃａｒ", "Car"},
+				output: "car",
+			},
+			{
+				name:   "ligatures",
+				input:  []string{"ﬁnance", "Finance"},
+				output: "finance",
+			},
+			{
+				name:   "quotes",
+				input:  []string{"don’t", "don't", "DON‘T"},
+				output: "don't",
+			},
+			{
+				name:   "diacritics are kept",
+				input:  []string{"Über"},
+				output: "über",
+			},
+		})
+	})
+
+	t.Run("with nfc and diacritic folding", func(t *testing.T) {
+		n, err := New(NFC, "en", true)
+		require.Nil(t, err)
+
+		run(t, n, []testcase{
+			{
+				name:   "diacritics",
+				input:  []string{"café", "Cafe", "CAFÉ", "café"},
+				output: "cafe",
+			},
+			{
+				name:   "letters which don't decompose",
+				input:  []string{"Øresund", "oresund"},
+				output: "oresund",
+			},
+			{
+				name:   "ligatures without nfkc",
+				input:  []string{"ﬂower"},
+				output: "flower",
+			},
+		})
+	})
+
+	t.Run("with the turkish rules", func(t *testing.T) {
+		n, err := New(NFKC, "tr", false)
+		require.Nil(t, err)
+
+		run(t, n, []testcase{
+			{
+				name:   "dotted capital i",
+				input:  []string{"İstanbul"},
+				output: "istanbul",
+			},
+			{
+				name:   "dotless capital i",
+				input:  []string{"ISPARTA"},
+				output: "ısparta",
+			},
+		})
+	})
+
+	t.Run("with a language which can't be parsed", func(t *testing.T) {
+		n, err := New(NFKC, "not a language", false)
+		require.Nil(t, err)
+		assert.Equal(t, "straße", n.Normalize("Straße"))
+	})
+
+	t.Run("with an unknown form", func(t *testing.T) {
+		_, err := New("nfd", "en", false)
+		assert.NotNil(t, err)
+	})
+}
@@ -3,20 +3,26 @@ package main
 import (
 	"context"
 	"fmt"
-	"strings"
+	"sort"
 	"sync"
 
 	pb "github.com/weaviate/contextionary/contextionary"
 	core "github.com/weaviate/contextionary/contextionary/core"
 	schema "github.com/weaviate/contextionary/contextionary/schema"
+	errortypes "github.com/weaviate/contextionary/errors"
 	"github.com/weaviate/contextionary/extensions"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
 func (s *server) AddExtension(ctx context.Context, params *pb.ExtensionInput) (*pb.AddExtensionResult, error) {
-	err := s.extensionStorer.Put(ctx, params.Concept, extensions.ExtensionInput{
-		Definition: strings.ToLower(params.Definition),
+	l, err := s.language(params.Language)
+	if err != nil {
+		return nil, err
+	}
+
+	err = l.extensionStorer.Put(ctx, l.normalizer.Normalize(params.Concept), extensions.ExtensionInput{
+		Definition: l.normalizer.Normalize(params.Definition),
 		Weight:     params.Weight,
 	})
 	if err != nil {
@@ -27,14 +33,34 @@ func (s *server) AddExtension(ctx context.Context, params *pb.ExtensionInput) (*
 }
 
 func (s *server) Meta(ctx context.Context, params *pb.MetaParams) (*pb.MetaOverview, error) {
+	l, err := s.language(params.Language)
+	if err != nil {
+		return nil, err
+	}
+
+	m, release := l.models.acquire()
+	defer release()
+
 	return &pb.MetaOverview{
 		Version:   Version,
-		WordCount: int64(s.combinedContextionary.GetNumberOfItems()),
+		WordCount: int64(m.combined.GetNumberOfItems()),
+		Metric:    string(m.combined.Metric()),
+
+		CertaintyMaxDistance: m.combined.CertaintyCalibration().MaxDistance,
 	}, nil
 }
 
 func (s *server) IsWordPresent(ctx context.Context, word *pb.Word) (*pb.WordPresent, error) {
-	asExtension, err := s.extensionLookerUpper.Lookup(word.Word)
+	l, err := s.language(word.Language)
+	if err != nil {
+		return nil, err
+	}
+
+	m, release := l.models.acquire()
+	defer release()
+
+	normalized := l.normalizer.Normalize(word.Word)
+	asExtension, err := l.extensionLookerUpper.Lookup(ctx, normalized)
 	if err != nil {
 		return nil, GrpcErrFromTyped(err)
 	}
@@ -43,19 +69,77 @@ func (s *server) IsWordPresent(ctx context.Context, word *pb.Word) (*pb.WordPres
 		return &pb.WordPresent{Present: true}, nil // TODO: add note about extension
 	}
 
-	i := s.combinedContextionary.WordToItemIndex(word.Word)
+	i := m.combined.WordToItemIndex(normalized)
 	return &pb.WordPresent{Present: i.IsPresent()}, nil
 }
 
+func (s *server) SuggestWords(ctx context.Context, params *pb.SuggestWordsParams) (*pb.WordSuggestions, error) {
+	l, err := s.language(params.Language)
+	if err != nil {
+		return nil, err
+	}
+
+	maxEdits := int(params.MaxEdits)
+	if maxEdits == 0 {
+		maxEdits = 2
+	}
+	if maxEdits < 0 || maxEdits > 3 {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("maxEdits must be between 1 and 3, got %d", maxEdits))
+	}
+
+	limit := int(params.Limit)
+	if limit == 0 {
+		limit = 10
+	}
+	if limit < 0 {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("limit must be positive, got %d", limit))
+	}
+
+	m, release := l.models.acquire()
+	defer release()
+
+	suggestions, err := m.vectorizer.Suggest(l.normalizer.Normalize(params.Word), maxEdits, limit)
+	if err != nil {
+		return nil, GrpcErrFromTyped(err)
+	}
+
+	out := make([]*pb.WordSuggestion, len(suggestions))
+	for i, suggestion := range suggestions {
+		out[i] = &pb.WordSuggestion{
+			Word:       suggestion.word,
+			Distance:   int32(suggestion.distance),
+			Occurrence: suggestion.occurrence,
+		}
+	}
+
+	return &pb.WordSuggestions{Suggestions: out}, nil
+}
+
 func (s *server) IsWordStopword(ctx context.Context, word *pb.Word) (*pb.WordStopword, error) {
-	sw := s.stopwordDetector.IsStopWord(word.Word)
+	l, err := s.language(word.Language)
+	if err != nil {
+		return nil, err
+	}
+
+	sw := l.stopwordDetector.IsStopWord(l.normalizer.Normalize(word.Word))
 	return &pb.WordStopword{Stopword: sw}, nil
 }
 
 func (s *server) SchemaSearch(ctx context.Context, params *pb.SchemaSearchParams) (*pb.SchemaSearchResults, error) {
+	l, err := s.language(params.Language)
+	if err != nil {
+		return nil, err
+	}
+
+	m, release := l.models.acquire()
+	defer release()
+
+	if err := ctx.Err(); err != nil {
+		return nil, GrpcErrFromTyped(err)
+	}
 
 	s.logger.WithField("params", params).Info()
-	c := schema.New(s.combinedContextionary)
+	c := schema.NewWithNormalizer(m.combined, l.normalizer.Normalize)
 	res, err := c.SchemaSearch(params)
 	s.logger.
 		WithField("res", res).
@@ -64,7 +148,15 @@ func (s *server) SchemaSearch(ctx context.Context, params *pb.SchemaSearchParams
 }
 
 func (s *server) SafeGetSimilarWordsWithCertainty(ctx context.Context, params *pb.SimilarWordsParams) (*pb.SimilarWordsResults, error) {
-	words := s.combinedContextionary.SafeGetSimilarWordsWithCertainty(params.Word, params.Certainty)
+	l, err := s.language(params.Language)
+	if err != nil {
+		return nil, err
+	}
+
+	m, release := l.models.acquire()
+	defer release()
+
+	words := m.combined.SafeGetSimilarWordsWithCertainty(l.normalizer.Normalize(params.Word), params.Certainty)
 	return &pb.SimilarWordsResults{
 		Words: pbWordsFromStrings(words),
 	}, nil
@@ -80,12 +172,24 @@ func pbWordsFromStrings(input []string) []*pb.Word {
 }
 
 func (s *server) MultiVectorForWord(ctx context.Context, params *pb.WordList) (*pb.VectorList, error) {
+	l, err := s.language(params.Language)
+	if err != nil {
+		return nil, err
+	}
+
+	m, release := l.models.acquire()
+	defer release()
+
 	lock := &sync.Mutex{}
 	out := make([]*pb.Vector, len(params.Words))
-	var errors []error
+	var errors []*pb.ItemError
 
 	concurrent := s.config.MaximumBatchSize
 	for i := 0; i < len(params.Words); i += concurrent {
+		if err := ctx.Err(); err != nil {
+			return nil, GrpcErrFromTyped(err)
+		}
+
 		end := i + concurrent
 		if end > len(params.Words) {
 			end = len(params.Words)
@@ -98,11 +202,11 @@ func (s *server) MultiVectorForWord(ctx context.Context, params *pb.WordList) (*
 			wg.Add(1)
 			go func(i, j int, word string) {
 				defer wg.Done()
-				word = strings.ToLower(word)
-				vec, err := s.vectorizer.VectorForWord(word)
+				vec, err := m.vectorizer.VectorForWord(ctx, word)
 				if err != nil {
 					lock.Lock()
-					errors = append(errors, err)
+					out[i+j] = &pb.Vector{}
+					errors = append(errors, batchItemErrorToProto(i+j, err))
 					lock.Unlock()
 					return
 				}
@@ -115,7 +219,7 @@ func (s *server) MultiVectorForWord(ctx context.Context, params *pb.WordList) (*
 				}
 
 				lock.Lock()
-				out[i+j] = vectorToProto(vec.vector)
+				out[i+j] = wordVectorToProto(vec)
 				lock.Unlock()
 
 			}(i, j, elem.Word)
@@ -124,26 +228,48 @@ func (s *server) MultiVectorForWord(ctx context.Context, params *pb.WordList) (*
 		wg.Wait()
 	}
 
-	if len(errors) > 0 {
-		return nil, joinErrors(errors)
+	if err := ctx.Err(); err != nil {
+		// the errors of the items are the request's error
+		return nil, GrpcErrFromTyped(err)
 	}
 
+	sortItemErrors(errors)
 	return &pb.VectorList{
 		Vectors: out,
+		Errors:  errors,
 	}, nil
 }
 
-func joinErrors(in []error) error {
-	msgs := make([]string, len(in))
-	for i, err := range in {
-		msgs[i] = fmt.Sprintf("at pos %d: %v", i, err)
-	}
+// itemErrorToProto maps the error of a single item the same way as the
+// error of a whole request
+func itemErrorToProto(err error) *pb.ItemError {
+	st := status.Convert(GrpcErrFromTyped(err))
+	return &pb.ItemError{Code: uint32(st.Code()), Message: st.Message()}
+}
 
-	return fmt.Errorf("%s", strings.Join(msgs, ", "))
+func batchItemErrorToProto(index int, err error) *pb.ItemError {
+	out := itemErrorToProto(err)
+	out.Index = uint32(index)
+	return out
+}
+
+// sortItemErrors by their index, the items of a batch finish in any order
+func sortItemErrors(errors []*pb.ItemError) {
+	sort.Slice(errors, func(a, b int) bool {
+		return errors[a].Index < errors[b].Index
+	})
 }
 
 func (s *server) VectorForWord(ctx context.Context, params *pb.Word) (*pb.Vector, error) {
-	wo, err := s.vectorizer.VectorForWord(params.Word)
+	l, err := s.language(params.Language)
+	if err != nil {
+		return nil, err
+	}
+
+	m, release := l.models.acquire()
+	defer release()
+
+	wo, err := m.vectorizer.VectorForWord(ctx, params.Word)
 	if err != nil {
 		return nil, GrpcErrFromTyped(err)
 	}
@@ -152,17 +278,53 @@ func (s *server) VectorForWord(ctx context.Context, params *pb.Word) (*pb.Vector
 		return nil, status.Error(codes.NotFound, fmt.Sprintf("word %s is not in the contextionary", params.Word))
 	}
 
-	return vectorToProto(wo.vector), nil
+	return wordVectorToProto(wo), nil
 }
 
 func (s *server) VectorForCorpi(ctx context.Context, params *pb.Corpi) (*pb.Vector, error) {
+	return s.vectorForCorpi(ctx, params)
+}
+
+// vectorForCorpi is shared by VectorForCorpi and VectorizeStream, all errors
+// are gRPC status errors
+func (s *server) vectorForCorpi(ctx context.Context, params *pb.Corpi) (*pb.Vector, error) {
+	l, err := s.language(params.Language)
+	if err != nil {
+		return nil, err
+	}
+
+	m, release := l.models.acquire()
+	defer release()
+
+	t, err := l.tokenizerFor(params.Tokenizer)
+	if err != nil {
+		return nil, err
+	}
+
 	overrides := assembleOverrideMap(params.Overrides)
-	vector, err := s.vectorizer.Corpi(params.Corpi, overrides)
+	var weights []float32
+	if len(params.Weights) > 0 {
+		weights = params.Weights
+	}
+
+	vector, err := m.vectorizer.CorpiWithOptions(ctx, params.Corpi, overrides, CorpiOptions{
+		Tokenizer:      t,
+		UseSuggestions: params.UseSuggestions,
+		Weights:        weights,
+	})
 	if err != nil {
 		if err == ErrNoUsableWords {
 			return nil, status.Error(codes.InvalidArgument, err.Error())
 		}
 
+		if _, ok := err.(errortypes.InvalidUserInput); ok {
+			return nil, GrpcErrFromTyped(err)
+		}
+
+		if ctxErr := grpcErrFromContext(err); ctxErr != nil {
+			return nil, ctxErr
+		}
+
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
@@ -182,40 +344,13 @@ func assembleOverrideMap(in []*pb.Override) map[string]string {
 	return out
 }
 
-func (s *server) vectorForWords(words []string) (*core.Vector, error) {
-	var vectors []core.Vector
-	for _, word := range words {
-		vector, err := s.vectorForWord(word)
-		if err != nil {
-			return nil, GrpcErrFromTyped(err)
-		}
-
-		if vector == nil {
-			continue
-		}
-
-		vectors = append(vectors, *vector)
-	}
-
-	if len(vectors) == 0 {
-		return nil, nil
-	}
-
-	return core.ComputeCentroid(vectors)
-}
-
-func (s *server) vectorForWord(word string) (*core.Vector, error) {
-	wi := s.combinedContextionary.WordToItemIndex(word)
-	if s.stopwordDetector.IsStopWord(word) {
-		return nil, nil
-	}
-
-	if !wi.IsPresent() {
-		return nil, nil
-	}
-
-	res, err := s.combinedContextionary.GetVectorForItemIndex(wi)
-	return res, GrpcErrFromTyped(err)
+// wordVectorToProto includes the source of the vector of a single word, so
+// that clients can tell whether it was synthesized. The vector itself might
+// be cached, so it is copied instead of setting the source on it.
+func wordVectorToProto(in *vectorWithOccurrence) *pb.Vector {
+	vector := *in.vector
+	vector.Source = in.source
+	return vectorToProto(&vector)
 }
 
 func vectorToProto(in *core.Vector) *pb.Vector {
@@ -228,9 +363,10 @@ func vectorToProto(in *core.Vector) *pb.Vector {
 	source := make([]*pb.InputElement, len(in.Source))
 	for i, s := range in.Source {
 		source[i] = &pb.InputElement{
-			Concept:    s.Concept,
-			Occurrence: s.Occurrence,
-			Weight:     float32(s.Weight),
+			Concept:     s.Concept,
+			Occurrence:  s.Occurrence,
+			Weight:      float32(s.Weight),
+			Synthesized: s.Synthesized,
 		}
 	}
 
@@ -247,12 +383,19 @@ func vectorFromProto(in *pb.Vector) core.Vector {
 }
 
 func (s *server) NearestWordsByVector(ctx context.Context, params *pb.VectorNNParams) (*pb.NearestWords, error) {
+	l, err := s.language(params.Language)
+	if err != nil {
+		return nil, err
+	}
+
+	m, release := l.models.acquire()
+	defer release()
 
-	ii, dist, err := s.combinedContextionary.GetNnsByVector(vectorFromProto(params.Vector), int(params.N), int(params.K))
+	ii, dist, err := nnsByVector(ctx, m, vectorFromProto(params.Vector), int(params.N), int(params.K))
 	if err != nil {
 		return nil, GrpcErrFromTyped(err)
 	}
-	words, _, err := s.itemIndexesToWordsAndOccs(ii)
+	words, _, err := s.itemIndexesToWordsAndOccs(m, ii)
 	if err != nil {
 		return nil, GrpcErrFromTyped(err)
 	}
@@ -264,13 +407,31 @@ func (s *server) NearestWordsByVector(ctx context.Context, params *pb.VectorNNPa
 }
 
 func (s *server) MultiNearestWordsByVector(ctx context.Context, params *pb.VectorNNParamsList) (*pb.NearestWordsList, error) {
+	l, err := s.language(params.Language)
+	if err != nil {
+		return nil, err
+	}
+
+	m, release := l.models.acquire()
+	defer release()
+
 	lock := &sync.Mutex{}
 	out := make([]*pb.NearestWords, len(params.Params))
-	var errors []error
+	var errors []*pb.ItemError
+	fail := func(index int, err error) {
+		lock.Lock()
+		defer lock.Unlock()
+		out[index] = &pb.NearestWords{}
+		errors = append(errors, batchItemErrorToProto(index, err))
+	}
 
 	concurrent := s.config.MaximumBatchSize
-	requiredMinOcc := s.combinedContextionary.OccurrencePercentile(s.config.NeighborOccurrenceIgnorePercentile)
+	requiredMinOcc := m.combined.OccurrencePercentile(s.config.NeighborOccurrenceIgnorePercentile)
 	for i := 0; i < len(params.Params); i += concurrent {
+		if err := ctx.Err(); err != nil {
+			return nil, GrpcErrFromTyped(err)
+		}
+
 		end := i + concurrent
 		if end > len(params.Params) {
 			end = len(params.Params)
@@ -284,19 +445,15 @@ func (s *server) MultiNearestWordsByVector(ctx context.Context, params *pb.Vecto
 			go func(i, j int, elem *pb.VectorNNParams) {
 				defer wg.Done()
 
-				ii, dist, err := s.combinedContextionary.GetNnsByVector(vectorFromProto(elem.Vector), int(elem.N)*5, int(elem.K)) // multiply by 5 to account for filtering
+				ii, dist, err := nnsByVector(ctx, m, vectorFromProto(elem.Vector), int(elem.N)*5, int(elem.K)) // multiply by 5 to account for filtering
 				if err != nil {
-					lock.Lock()
-					errors = append(errors, GrpcErrFromTyped(err))
-					lock.Unlock()
+					fail(i+j, err)
 					return
 				}
 
-				words, occs, err := s.itemIndexesToWordsAndOccs(ii)
+				words, occs, err := s.itemIndexesToWordsAndOccs(m, ii)
 				if err != nil {
-					lock.Lock()
-					errors = append(errors, GrpcErrFromTyped(err))
-					lock.Unlock()
+					fail(i+j, err)
 					return
 				}
 
@@ -313,11 +470,9 @@ func (s *server) MultiNearestWordsByVector(ctx context.Context, params *pb.Vecto
 					}
 				}
 
-				vectors, err := s.itemIndexesToVectors(ii)
+				vectors, err := s.itemIndexesToVectors(m, ii)
 				if err != nil {
-					lock.Lock()
-					errors = append(errors, GrpcErrFromTyped(err))
-					lock.Unlock()
+					fail(i+j, err)
 					return
 				}
 
@@ -332,27 +487,37 @@ func (s *server) MultiNearestWordsByVector(ctx context.Context, params *pb.Vecto
 		wg.Wait()
 	}
 
-	if len(errors) > 0 {
-		return nil, joinErrors(errors)
+	if err := ctx.Err(); err != nil {
+		// the errors of the items are the request's error
+		return nil, GrpcErrFromTyped(err)
 	}
 
+	sortItemErrors(errors)
 	return &pb.NearestWordsList{
-		Words: out,
+		Words:  out,
+		Errors: errors,
 	}, nil
 }
 
-func (s *server) itemIndexesToWordsAndOccs(in []core.ItemIndex) ([]string, []uint64, error) {
+// nnsByVector searches the neighbors of the vector for a request, the HNSW
+// and quantized indices stop searching once the request is cancelled or
+// expired, annoy doesn't start searching then
+func nnsByVector(ctx context.Context, m *model, vector core.Vector, n, k int) ([]core.ItemIndex, []float32, error) {
+	return m.combined.GetNnsByVectorContext(ctx, vector, n, k)
+}
+
+func (s *server) itemIndexesToWordsAndOccs(m *model, in []core.ItemIndex) ([]string, []uint64, error) {
 	words := make([]string, len(in), len(in))
 	occs := make([]uint64, len(in), len(in))
 	for i, itemIndex := range in {
-		w, err := s.combinedContextionary.ItemIndexToWord(itemIndex)
+		w, err := m.combined.ItemIndexToWord(itemIndex)
 		if err != nil {
 			return nil, nil, GrpcErrFromTyped(err)
 		}
 
 		words[i] = w
 
-		occ, err := s.combinedContextionary.ItemIndexToOccurrence(itemIndex)
+		occ, err := m.combined.ItemIndexToOccurrence(itemIndex)
 		if err != nil {
 			return nil, nil, GrpcErrFromTyped(err)
 		}
@@ -363,13 +528,13 @@ func (s *server) itemIndexesToWordsAndOccs(in []core.ItemIndex) ([]string, []uin
 	return words, occs, nil
 }
 
-func (s *server) itemIndexesToVectors(in []core.ItemIndex) (*pb.VectorList, error) {
+func (s *server) itemIndexesToVectors(m *model, in []core.ItemIndex) (*pb.VectorList, error) {
 	out := &pb.VectorList{
 		Vectors: make([]*pb.Vector, len(in)),
 	}
 
 	for i, itemIndex := range in {
-		vector, err := s.combinedContextionary.GetVectorForItemIndex(itemIndex)
+		vector, err := m.combined.GetVectorForItemIndex(itemIndex)
 		if err != nil {
 			return nil, GrpcErrFromTyped(err)
 		}
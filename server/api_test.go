@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weaviate/contextionary/compoundsplitting"
+	pb "github.com/weaviate/contextionary/contextionary"
+	contextionary "github.com/weaviate/contextionary/contextionary/core"
+	errortypes "github.com/weaviate/contextionary/errors"
+	"github.com/weaviate/contextionary/server/config"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newTestServer serves a single language with the contextionary
+func newTestServer(t *testing.T, c11y contextionary.Contextionary) *server {
+	logger, _ := test.NewNullLogger()
+	cfg := &config.Config{
+		OccurrenceWeightLinearFactor: 0.5,
+		OccurrenceWeightStrategy:     OccurrenceStrategyLinear,
+		MaxCompoundWordLength:        1,
+		MaximumBatchSize:             2,
+		StreamWorkers:                2,
+		Languages:                    []config.LanguageConfig{{Language: "en"}},
+		DefaultLanguage:              "en",
+	}
+	v, err := NewVectorizer(c11y, &fakeStopwordDetector{}, cfg, logger, &primitiveSplitter{},
+		&fakeExtensionLookerUpper{}, compoundsplitting.NewEmptyTestSplitter(), &fakeNormalizer{}, nil, nil, nil)
+	require.Nil(t, err)
+
+	l := &language{config: config.LanguageConfig{Language: "en"}}
+	l.models.swap(&model{raw: c11y, combined: c11y, vectorizer: v})
+	return &server{config: cfg, logger: logger, languages: map[string]*language{"en": l}}
+}
+
+const brokenIndex = 42
+
+// batchC11y fails for the word "broken" and for vectors of the wrong
+// length, everything else is a neighbor of car
+type batchC11y struct {
+	fakeC11y
+}
+
+func (f *batchC11y) WordToItemIndex(word string) contextionary.ItemIndex {
+	if word == "broken" {
+		return brokenIndex
+	}
+
+	return f.fakeC11y.WordToItemIndex(word)
+}
+
+func (f *batchC11y) OccurrencePercentile(perc int) uint64 {
+	return 0
+}
+
+func (f *batchC11y) ItemIndexToWord(item contextionary.ItemIndex) (string, error) {
+	return "car", nil
+}
+
+func (f *batchC11y) GetNnsByVector(vector contextionary.Vector, n int, k int) ([]contextionary.ItemIndex, []float32, error) {
+	return f.GetNnsByVectorContext(context.Background(), vector, n, k)
+}
+
+func (f *batchC11y) GetNnsByVectorContext(ctx context.Context, vector contextionary.Vector, n int, k int) ([]contextionary.ItemIndex, []float32, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	if vector.Len() != 4 {
+		return nil, nil, errortypes.NewInvalidUserInputf("vector has length %d, expected 4", vector.Len())
+	}
+
+	return []contextionary.ItemIndex{5}, []float32{0.5}, nil
+}
+
+func TestMultiVectorForWord(t *testing.T) {
+	s := newTestServer(t, &batchC11y{})
+
+	res, err := s.MultiVectorForWord(context.Background(), &pb.WordList{Words: []*pb.Word{
+		{Word: "car"}, {Word: "broken"}, {Word: "mercedes"}, {Word: "broken"}, {Word: "car"},
+	}})
+	require.Nil(t, err)
+
+	t.Run("the words which could be vectorized", func(t *testing.T) {
+		require.Len(t, res.Vectors, 5)
+		for _, i := range []int{0, 2, 4} {
+			assert.Len(t, res.Vectors[i].Entries, 4, "word %d", i)
+		}
+	})
+
+	t.Run("an error for every word which couldn't", func(t *testing.T) {
+		require.Len(t, res.Errors, 2)
+		for i, index := range []uint32{1, 3} {
+			assert.Equal(t, index, res.Errors[i].Index)
+			assert.Equal(t, uint32(codes.Unknown), res.Errors[i].Code)
+			assert.NotEmpty(t, res.Errors[i].Message)
+			assert.Len(t, res.Vectors[index].Entries, 0)
+		}
+	})
+}
+
+func TestCancelledRequests(t *testing.T) {
+	s := newTestServer(t, &batchC11y{})
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancelExpired := context.WithTimeout(context.Background(), -time.Second)
+	defer cancelExpired()
+
+	t.Run("vectorizing corpi", func(t *testing.T) {
+		_, err := s.VectorForCorpi(cancelled, &pb.Corpi{Corpi: []string{"car is mercedes"}})
+		assert.Equal(t, codes.Canceled, status.Code(err))
+
+		_, err = s.VectorForCorpi(expired, &pb.Corpi{Corpi: []string{"car is mercedes"}})
+		assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	})
+
+	t.Run("vectorizing words", func(t *testing.T) {
+		_, err := s.VectorForWord(cancelled, &pb.Word{Word: "car"})
+		assert.Equal(t, codes.Canceled, status.Code(err))
+
+		_, err = s.MultiVectorForWord(expired, &pb.WordList{Words: []*pb.Word{{Word: "car"}}})
+		assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	})
+
+	t.Run("searching neighbors", func(t *testing.T) {
+		vector := &pb.Vector{Entries: []*pb.VectorEntry{{Entry: 1}, {Entry: 2}, {Entry: 0}, {Entry: 0}}}
+
+		_, err := s.NearestWordsByVector(cancelled, &pb.VectorNNParams{Vector: vector, N: 1, K: 1})
+		assert.Equal(t, codes.Canceled, status.Code(err))
+
+		_, err = s.MultiNearestWordsByVector(expired, &pb.VectorNNParamsList{
+			Params: []*pb.VectorNNParams{{Vector: vector, N: 1, K: 1}},
+		})
+		assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	})
+}
+
+// blockingC11y searches until the request is cancelled or expired
+type blockingC11y struct {
+	batchC11y
+}
+
+func (f *blockingC11y) GetNnsByVectorContext(ctx context.Context, vector contextionary.Vector, n int, k int) ([]contextionary.ItemIndex, []float32, error) {
+	<-ctx.Done()
+	return nil, nil, ctx.Err()
+}
+
+func TestCancellingRunningSearches(t *testing.T) {
+	s := newTestServer(t, &blockingC11y{})
+	vector := &pb.Vector{Entries: []*pb.VectorEntry{{Entry: 1}, {Entry: 2}, {Entry: 0}, {Entry: 0}}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := s.NearestWordsByVector(ctx, &pb.VectorNNParams{Vector: vector, N: 1, K: 1})
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = s.MultiNearestWordsByVector(ctx, &pb.VectorNNParamsList{
+		Params: []*pb.VectorNNParams{{Vector: vector, N: 1, K: 1}, {Vector: vector, N: 1, K: 1}},
+	})
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+}
+
+func TestMultiNearestWordsByVector(t *testing.T) {
+	s := newTestServer(t, &batchC11y{})
+
+	vector := func(entries ...float32) *pb.Vector {
+		out := &pb.Vector{}
+		for _, entry := range entries {
+			out.Entries = append(out.Entries, &pb.VectorEntry{Entry: entry})
+		}
+		return out
+	}
+
+	res, err := s.MultiNearestWordsByVector(context.Background(), &pb.VectorNNParamsList{Params: []*pb.VectorNNParams{
+		{Vector: vector(1, 2, 0, 0), N: 1, K: 1},
+		{Vector: vector(1, 2), N: 1, K: 1},
+		{Vector: vector(1, 0, 0, 4), N: 1, K: 1},
+	}})
+	require.Nil(t, err)
+
+	require.Len(t, res.Words, 3)
+	assert.Equal(t, []string{"car"}, res.Words[0].Words)
+	assert.Equal(t, []string{"car"}, res.Words[2].Words)
+	assert.Len(t, res.Words[1].Words, 0)
+
+	require.Len(t, res.Errors, 1)
+	assert.Equal(t, uint32(1), res.Errors[0].Index)
+	assert.Equal(t, uint32(codes.InvalidArgument), res.Errors[0].Code)
+	assert.Equal(t, "vector has length 2, expected 4", res.Errors[0].Message)
+}
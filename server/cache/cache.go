@@ -0,0 +1,198 @@
+// Package cache is a bounded least recently used cache, which is sharded so
+// that concurrent requests rarely wait for each other. Once a shard is full,
+// adding an entry evicts the entry of the shard which was used longest ago,
+// so the cache never has to be cleared as a whole.
+package cache
+
+import (
+	"container/list"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Options of a cache
+type Options struct {
+	// Size is the maximum number of entries, the cache is disabled if it
+	// is 0
+	Size int
+
+	// Shards the entries are spread over, every shard holds up to
+	// Size/Shards entries, so that all of them hold up to Size entries. 16 if
+	// not set.
+	Shards int
+
+	// TTL after which an entry expires, entries don't expire if it is 0
+	TTL time.Duration
+}
+
+const defaultShards = 16
+
+// Stats are counted since the cache was created
+type Stats struct {
+	Hits        uint64
+	Misses      uint64
+	Evictions   uint64
+	Expirations uint64
+
+	// Size is the number of entries at the time of the call
+	Size int
+}
+
+// Cache is safe for concurrent use
+type Cache struct {
+	// the counters are updated atomically, so they must stay at the start of
+	// the struct to be 64-bit aligned on 32-bit platforms
+	hits        uint64
+	misses      uint64
+	evictions   uint64
+	expirations uint64
+
+	shards []*shard
+	ttl    time.Duration
+
+	// now is replaced in tests
+	now func() time.Time
+}
+
+type shard struct {
+	sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+
+	// the most recently used entry is at the front
+	recency *list.List
+}
+
+type entry struct {
+	key     string
+	value   interface{}
+	expires time.Time
+}
+
+// New cache with the options
+func New(options Options) *Cache {
+	shards := options.Shards
+	if shards <= 0 {
+		shards = defaultShards
+	}
+	if options.Size > 0 && shards > options.Size {
+		shards = options.Size
+	}
+
+	c := &Cache{ttl: options.TTL, now: time.Now}
+	if options.Size <= 0 {
+		return c
+	}
+
+	// the capacities add up to exactly the size, the first shards hold one
+	// entry more if it can't be divided evenly
+	c.shards = make([]*shard, shards)
+	for i := range c.shards {
+		capacity := options.Size / shards
+		if i < options.Size%shards {
+			capacity++
+		}
+
+		c.shards[i] = &shard{
+			capacity: capacity,
+			entries:  map[string]*list.Element{},
+			recency:  list.New(),
+		}
+	}
+
+	return c
+}
+
+func (c *Cache) shard(key string) *shard {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return c.shards[h.Sum32()%uint32(len(c.shards))]
+}
+
+// Get the value of the key, ok is false if the key isn't cached or its entry
+// expired
+func (c *Cache) Get(key string) (value interface{}, ok bool) {
+	if c.shards == nil {
+		atomic.AddUint64(&c.misses, 1)
+		return nil, false
+	}
+
+	s := c.shard(key)
+	s.Lock()
+	defer s.Unlock()
+
+	elem, ok := s.entries[key]
+	if !ok {
+		atomic.AddUint64(&c.misses, 1)
+		return nil, false
+	}
+
+	e := elem.Value.(*entry)
+	if c.ttl > 0 && c.now().After(e.expires) {
+		s.remove(elem)
+		atomic.AddUint64(&c.expirations, 1)
+		atomic.AddUint64(&c.misses, 1)
+		return nil, false
+	}
+
+	s.recency.MoveToFront(elem)
+	atomic.AddUint64(&c.hits, 1)
+	return e.value, true
+}
+
+// Set the value of the key, which evicts the least recently used entry of
+// the key's shard if it is full
+func (c *Cache) Set(key string, value interface{}) {
+	if c.shards == nil {
+		return
+	}
+
+	var expires time.Time
+	if c.ttl > 0 {
+		expires = c.now().Add(c.ttl)
+	}
+
+	s := c.shard(key)
+	s.Lock()
+	defer s.Unlock()
+
+	if elem, ok := s.entries[key]; ok {
+		e := elem.Value.(*entry)
+		e.value = value
+		e.expires = expires
+		s.recency.MoveToFront(elem)
+		return
+	}
+
+	if s.recency.Len() >= s.capacity {
+		s.remove(s.recency.Back())
+		atomic.AddUint64(&c.evictions, 1)
+	}
+
+	s.entries[key] = s.recency.PushFront(&entry{key: key, value: value, expires: expires})
+}
+
+func (s *shard) remove(elem *list.Element) {
+	s.recency.Remove(elem)
+	delete(s.entries, elem.Value.(*entry).key)
+}
+
+// Stats of the cache
+func (c *Cache) Stats() Stats {
+	size := 0
+	for _, s := range c.shards {
+		s.Lock()
+		size += s.recency.Len()
+		s.Unlock()
+	}
+
+	return Stats{
+		Hits:        atomic.LoadUint64(&c.hits),
+		Misses:      atomic.LoadUint64(&c.misses),
+		Evictions:   atomic.LoadUint64(&c.evictions),
+		Expirations: atomic.LoadUint64(&c.expirations),
+		Size:        size,
+	}
+}
@@ -0,0 +1,105 @@
+package cache
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeastRecentlyUsed(t *testing.T) {
+	c := New(Options{Size: 2, Shards: 1})
+
+	c.Set("car", 1)
+	c.Set("mercedes", 2)
+
+	// car is now used more recently than mercedes
+	value, ok := c.Get("car")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+
+	c.Set("steam", 3)
+
+	_, ok = c.Get("mercedes")
+	assert.False(t, ok, "the least recently used entry is evicted")
+	_, ok = c.Get("car")
+	assert.True(t, ok)
+	_, ok = c.Get("steam")
+	assert.True(t, ok)
+
+	assert.Equal(t, Stats{Hits: 3, Misses: 1, Evictions: 1, Size: 2}, c.Stats())
+}
+
+func TestReplacingAValue(t *testing.T) {
+	c := New(Options{Size: 2, Shards: 1})
+
+	c.Set("car", 1)
+	c.Set("car", 2)
+
+	value, ok := c.Get("car")
+	assert.True(t, ok)
+	assert.Equal(t, 2, value)
+	assert.Equal(t, 1, c.Stats().Size)
+}
+
+func TestTTL(t *testing.T) {
+	now := time.Now()
+	c := New(Options{Size: 10, TTL: time.Minute})
+	c.now = func() time.Time { return now }
+
+	c.Set("car", 1)
+
+	now = now.Add(30 * time.Second)
+	_, ok := c.Get("car")
+	assert.True(t, ok)
+
+	now = now.Add(time.Minute)
+	_, ok = c.Get("car")
+	assert.False(t, ok)
+
+	assert.Equal(t, Stats{Hits: 1, Misses: 1, Expirations: 1, Size: 0}, c.Stats())
+}
+
+func TestDisabled(t *testing.T) {
+	c := New(Options{Size: 0})
+
+	c.Set("car", 1)
+	_, ok := c.Get("car")
+	assert.False(t, ok)
+	assert.Equal(t, Stats{Misses: 1}, c.Stats())
+}
+
+func TestSizeIsBounded(t *testing.T) {
+	c := New(Options{Size: 100})
+
+	wg := &sync.WaitGroup{}
+	for worker := 0; worker < 8; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				key := fmt.Sprintf("word-%d-%d", worker, i)
+				c.Set(key, i)
+				c.Get(key)
+			}
+		}(worker)
+	}
+	wg.Wait()
+
+	stats := c.Stats()
+	assert.True(t, stats.Size <= 100, "size %d exceeds the bound", stats.Size)
+	assert.Equal(t, uint64(8000), stats.Hits+stats.Misses)
+	assert.Equal(t, uint64(8000), uint64(stats.Size)+stats.Evictions)
+}
+
+func TestShardCapacitiesAddUpToTheSize(t *testing.T) {
+	c := New(Options{Size: 10, Shards: 4})
+
+	var capacities []int
+	for _, s := range c.shards {
+		capacities = append(capacities, s.capacity)
+	}
+	assert.Equal(t, []int{3, 3, 2, 2}, capacities)
+}
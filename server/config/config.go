@@ -3,39 +3,122 @@ package config
 import (
 	"fmt"
 	"os"
+	"runtime"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
+	"github.com/weaviate/contextionary/normalization"
+	"github.com/weaviate/contextionary/tokenizer"
 )
 
 // Config is used to load application wide config from the environment
 type Config struct {
-	logger        logrus.FieldLogger
-	KNNFile       string
-	IDXFile       string
-	StopwordsFile string
+	logger logrus.FieldLogger
+
+	// Languages holds the files of every language the server serves,
+	// requests which don't specify a language use the DefaultLanguage
+	Languages       []LanguageConfig
+	DefaultLanguage string
+
+	// ExtensionsLegacyLanguage is the language whose extensions are stored
+	// without a language prefix, as they were before there were several
+	// languages. It is empty if every language uses a prefix.
+	ExtensionsLegacyLanguage string
+
+	VectorIndexType    string
+	HNSWEf             int
+	HNSWMaxConnections int
+	HNSWEfConstruction int
+
+	ModelWatchInterval time.Duration
+
+	VectorQuantization       string
+	QuantizationRerankFactor int
 
 	SchemaProviderURL       string
 	SchemaProviderKey       string
+	SchemaWatchInterval     time.Duration
 	ExtensionsPrefix        string
 	ExtensionsStorageOrigin string
 	ExtensionsStorageMode   string
 
 	ServerPort int
 
+	// MetricsPort serves the prometheus metrics on /metrics, 0 disables them
+	MetricsPort int
+
+	// ShutdownTimeout is how long the in-flight requests may take to finish
+	// on shutdown before they are cancelled
+	ShutdownTimeout time.Duration
+
 	OccurrenceWeightStrategy           string
 	OccurrenceWeightLinearFactor       float32
+	SIFSmoothing                       float32
+	RemovePrincipalComponent           bool
 	MaxCompoundWordLength              int
 	MaximumBatchSize                   int
+	StreamWorkers                      int
 	MaximumVectorCacheSize             int
+	VectorCacheTTL                     time.Duration
 	NeighborOccurrenceIgnorePercentile int
 
-	EnableCompundSplitting          bool
-	CompoundSplittingDictionaryFile string
+	EnableCompundSplitting bool
+
+	// EnableSubwordVectors synthesizes vectors for words which are neither in
+	// the contextionary nor can be split into compounds
+	EnableSubwordVectors bool
 
 	LogLevel string
 }
 
+// LanguageConfig holds the files of a single language. If LANGUAGES is not
+// set, there is exactly one language which is configured through the
+// unsuffixed vars such as KNN_FILE. Otherwise every var is suffixed with the
+// uppercased language, e.g. KNN_FILE_DE.
+type LanguageConfig struct {
+	Language                        string
+	KNNFile                         string
+	IDXFile                         string
+	StopwordsFile                   string
+	CompoundSplittingDictionaryFile string
+	HNSWFile                        string
+	QuantizedFile                   string
+	SubwordFile                     string
+
+	// Tokenizer splits the corpi of requests which don't ask for a
+	// specific tokenizer
+	Tokenizer string
+
+	// UnicodeNormalization and FoldDiacritics configure how words are
+	// normalized before they are looked up, see normalization.Normalizer
+	UnicodeNormalization string
+	FoldDiacritics       bool
+
+	varSuffix string
+}
+
+func (l LanguageConfig) varName(name string) string {
+	return name + l.varSuffix
+}
+
+// Language returns the config of the specified language, the empty string
+// is the DefaultLanguage
+func (c *Config) Language(language string) (LanguageConfig, bool) {
+	if language == "" {
+		language = c.DefaultLanguage
+	}
+
+	for _, l := range c.Languages {
+		if l.Language == language {
+			return l, true
+		}
+	}
+
+	return LanguageConfig{}, false
+}
+
 // New Config from the environment. Errors if required env vars can't be found
 func New(logger logrus.FieldLogger) (*Config, error) {
 	cfg := &Config{logger: logger}
@@ -47,30 +130,47 @@ func New(logger logrus.FieldLogger) (*Config, error) {
 }
 
 func (c *Config) init() error {
-	knn, err := c.requiredString("KNN_FILE")
-	if err != nil {
+	if err := c.initLanguages(); err != nil {
 		return err
 	}
-	c.KNNFile = knn
 
-	idx, err := c.requiredString("IDX_FILE")
-	if err != nil {
+	if err := c.initVectorIndex(); err != nil {
+		return err
+	}
+
+	if err := c.initVectorQuantization(); err != nil {
 		return err
 	}
-	c.IDXFile = idx
 
-	sw, err := c.requiredString("STOPWORDS_FILE")
+	watchInterval, err := c.optionalInt("MODEL_WATCH_INTERVAL", 0)
 	if err != nil {
 		return err
 	}
-	c.StopwordsFile = sw
+	if watchInterval < 0 {
+		return fmt.Errorf("MODEL_WATCH_INTERVAL must not be negative, got: %d", watchInterval)
+	}
+	// in seconds, 0 disables reloading the model when its files change
+	c.ModelWatchInterval = time.Duration(watchInterval) * time.Second
 
+	// origin of the weaviate instance whose schema is combined with the
+	// contextionary for the schema search, the schema search has no results
+	// if it isn't set
 	sp := c.optionalString("SCHEMA_PROVIDER_URL", "")
 	c.SchemaProviderURL = sp
 
 	spk := c.optionalString("SCHEMA_PROVIDER_KEY", "/weaviate/schema/state")
 	c.SchemaProviderKey = spk
 
+	schemaWatchInterval, err := c.optionalInt("SCHEMA_WATCH_INTERVAL", 1)
+	if err != nil {
+		return err
+	}
+	if schemaWatchInterval < 1 {
+		return fmt.Errorf("SCHEMA_WATCH_INTERVAL must be at least 1, got: %d", schemaWatchInterval)
+	}
+	// in seconds, how often the schema is polled from SCHEMA_PROVIDER_URL
+	c.SchemaWatchInterval = time.Duration(schemaWatchInterval) * time.Second
+
 	ep := c.optionalString("EXTENSIONS_PREFIX", "/contextionary/")
 	c.ExtensionsPrefix = ep
 
@@ -86,6 +186,25 @@ func (c *Config) init() error {
 	}
 	c.ServerPort = port
 
+	metricsPort, err := c.optionalInt("METRICS_PORT", 2112)
+	if err != nil {
+		return err
+	}
+	if metricsPort < 0 {
+		return fmt.Errorf("METRICS_PORT must not be negative, got: %d", metricsPort)
+	}
+	c.MetricsPort = metricsPort
+
+	shutdownTimeout, err := c.optionalInt("SHUTDOWN_TIMEOUT", 30)
+	if err != nil {
+		return err
+	}
+	if shutdownTimeout < 0 {
+		return fmt.Errorf("SHUTDOWN_TIMEOUT must not be negative, got: %d", shutdownTimeout)
+	}
+	// in seconds, 0 cancels the in-flight requests right away
+	c.ShutdownTimeout = time.Duration(shutdownTimeout) * time.Second
+
 	factor, err := c.optionalFloat32("OCCURRENCE_WEIGHT_LINEAR_FACTOR", 0.5)
 	if err != nil {
 		return err
@@ -106,6 +225,19 @@ func (c *Config) init() error {
 	strategy := c.optionalString("OCCURRENCE_WEIGHT_STRATEGY", "log")
 	c.OccurrenceWeightStrategy = strategy
 
+	// the smoothing a of the sif strategy, words with a probability much
+	// larger than a are weighted close to 0
+	smoothing, err := c.optionalFloat32("SIF_SMOOTHING", 0.001)
+	if err != nil {
+		return err
+	}
+	if smoothing <= 0 {
+		return fmt.Errorf("SIF_SMOOTHING must be larger than 0, got: %f", smoothing)
+	}
+	c.SIFSmoothing = smoothing
+
+	c.RemovePrincipalComponent = c.optionalBool("SIF_REMOVE_PRINCIPAL_COMPONENT", false)
+
 	// this should match the underlying vector db file, a smaller value than in
 	// the vector file will lead to missing out on compound words, whereas a
 	// larger value will lead to unnecessary lookups slowing down the
@@ -122,20 +254,59 @@ func (c *Config) init() error {
 	}
 	c.MaximumBatchSize = batchSize
 
+	// the number of documents of a VectorizeStream which are vectorized
+	// concurrently, vectorizing is cpu bound
+	streamWorkers, err := c.optionalInt("STREAM_WORKERS", runtime.NumCPU())
+	if err != nil {
+		return err
+	}
+	if streamWorkers < 1 {
+		return fmt.Errorf("STREAM_WORKERS must be at least 1, got: %d", streamWorkers)
+	}
+	c.StreamWorkers = streamWorkers
+
 	vectorCacheSize, err := c.optionalInt("MAX_VECTORCACHE_SIZE", 10000)
 	if err != nil {
 		return err
 	}
+	if vectorCacheSize < 0 {
+		return fmt.Errorf("MAX_VECTORCACHE_SIZE must not be negative, got: %d", vectorCacheSize)
+	}
+	// 0 disables the cache
 	c.MaximumVectorCacheSize = vectorCacheSize
 
+	cacheTTL, err := c.optionalInt("VECTORCACHE_TTL", 0)
+	if err != nil {
+		return err
+	}
+	if cacheTTL < 0 {
+		return fmt.Errorf("VECTORCACHE_TTL must not be negative, got: %d", cacheTTL)
+	}
+	// in seconds, 0 keeps the vectors until they are evicted
+	c.VectorCacheTTL = time.Duration(cacheTTL) * time.Second
+
 	c.EnableCompundSplitting = c.optionalBool("ENABLE_COMPOUND_SPLITTING", false)
 
 	if c.EnableCompundSplitting {
-		compoundSplittingDictionaryFile, err := c.requiredString("COMPOUND_SPLITTING_DICTIONARY_FILE")
-		if err != nil {
-			return err
+		for i := range c.Languages {
+			l := &c.Languages[i]
+			compoundSplittingDictionaryFile, err := c.requiredString(l.varName("COMPOUND_SPLITTING_DICTIONARY_FILE"))
+			if err != nil {
+				return err
+			}
+			l.CompoundSplittingDictionaryFile = compoundSplittingDictionaryFile
+		}
+	}
+
+	c.EnableSubwordVectors = c.optionalBool("ENABLE_SUBWORD_VECTORS", false)
+
+	if c.EnableSubwordVectors {
+		// the subword vectors are written next to the .knn file by the
+		// generator's --subwords option
+		for i := range c.Languages {
+			l := &c.Languages[i]
+			l.SubwordFile = c.optionalString(l.varName("SUBWORD_FILE"), l.KNNFile+".subwords")
 		}
-		c.CompoundSplittingDictionaryFile = compoundSplittingDictionaryFile
 	}
 
 	loglevel := c.optionalString("LOG_LEVEL", "info")
@@ -144,6 +315,189 @@ func (c *Config) init() error {
 	return nil
 }
 
+func (c *Config) initLanguages() error {
+	names := c.optionalString("LANGUAGES", "")
+	if names == "" {
+		// a single language, configured through the unsuffixed vars
+		language := c.optionalString("DEFAULT_LANGUAGE", "en")
+		c.Languages = []LanguageConfig{{Language: language}}
+		c.DefaultLanguage = language
+		c.ExtensionsLegacyLanguage = c.optionalString("EXTENSIONS_LEGACY_LANGUAGE", language)
+		if c.ExtensionsLegacyLanguage != language {
+			return fmt.Errorf("EXTENSIONS_LEGACY_LANGUAGE '%s' is not the served language '%s'",
+				c.ExtensionsLegacyLanguage, language)
+		}
+	} else {
+		seen := map[string]bool{}
+		for _, name := range strings.Split(names, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				return fmt.Errorf("LANGUAGES must be a comma separated list of languages, got: '%s'", names)
+			}
+			if seen[name] {
+				return fmt.Errorf("LANGUAGES contains '%s' more than once", name)
+			}
+			seen[name] = true
+
+			c.Languages = append(c.Languages, LanguageConfig{
+				Language:  name,
+				varSuffix: "_" + strings.ToUpper(name),
+			})
+		}
+
+		c.DefaultLanguage = c.optionalString("DEFAULT_LANGUAGE", c.Languages[0].Language)
+		if !seen[c.DefaultLanguage] {
+			return fmt.Errorf("DEFAULT_LANGUAGE '%s' is not one of the LANGUAGES '%s'", c.DefaultLanguage, names)
+		}
+
+		// deliberately independent of the DEFAULT_LANGUAGE, so that changing
+		// the default doesn't move the extensions to another language
+		c.ExtensionsLegacyLanguage = c.optionalString("EXTENSIONS_LEGACY_LANGUAGE", "")
+		if c.ExtensionsLegacyLanguage != "" && !seen[c.ExtensionsLegacyLanguage] {
+			return fmt.Errorf("EXTENSIONS_LEGACY_LANGUAGE '%s' is not one of the LANGUAGES '%s'",
+				c.ExtensionsLegacyLanguage, names)
+		}
+	}
+
+	for i := range c.Languages {
+		l := &c.Languages[i]
+
+		knn, err := c.requiredString(l.varName("KNN_FILE"))
+		if err != nil {
+			return err
+		}
+		l.KNNFile = knn
+
+		idx, err := c.requiredString(l.varName("IDX_FILE"))
+		if err != nil {
+			return err
+		}
+		l.IDXFile = idx
+
+		sw, err := c.requiredString(l.varName("STOPWORDS_FILE"))
+		if err != nil {
+			return err
+		}
+		l.StopwordsFile = sw
+
+		l.Tokenizer = c.optionalString(l.varName("TOKENIZER"), tokenizer.UnicodeWord)
+		if _, err := tokenizer.New(l.Tokenizer); err != nil {
+			return fmt.Errorf("%s: %v", l.varName("TOKENIZER"), err)
+		}
+
+		l.UnicodeNormalization = c.optionalString(l.varName("UNICODE_NORMALIZATION"), normalization.NFKC)
+		if _, err := normalization.New(l.UnicodeNormalization, l.Language, false); err != nil {
+			return fmt.Errorf("%s: %v", l.varName("UNICODE_NORMALIZATION"), err)
+		}
+
+		// only enable this if the words of the contextionary are folded as
+		// well, otherwise words with diacritics can't be found anymore
+		l.FoldDiacritics = c.optionalBool(l.varName("FOLD_DIACRITICS"), false)
+	}
+
+	return nil
+}
+
+const (
+	// VectorIndexAnnoy uses the annoy trees of the .knn file for nearest
+	// neighbor searches
+	VectorIndexAnnoy = "annoy"
+	// VectorIndexHNSW uses an HNSW graph, which is built from the .knn file
+	// and persisted to HNSW_FILE
+	VectorIndexHNSW = "hnsw"
+)
+
+func (c *Config) initVectorIndex() error {
+	indexType := c.optionalString("VECTOR_INDEX_TYPE", VectorIndexAnnoy)
+	if indexType != VectorIndexAnnoy && indexType != VectorIndexHNSW {
+		return fmt.Errorf("VECTOR_INDEX_TYPE must be one of '%s' or '%s', got: '%s'",
+			VectorIndexAnnoy, VectorIndexHNSW, indexType)
+	}
+	c.VectorIndexType = indexType
+
+	if indexType != VectorIndexHNSW {
+		return nil
+	}
+
+	for i := range c.Languages {
+		l := &c.Languages[i]
+		l.HNSWFile = c.optionalString(l.varName("HNSW_FILE"), l.KNNFile+".hnsw")
+	}
+
+	ef, err := c.optionalInt("HNSW_EF", 128)
+	if err != nil {
+		return err
+	}
+	if ef < 1 {
+		return fmt.Errorf("HNSW_EF must be at least 1, got: %d", ef)
+	}
+	c.HNSWEf = ef
+
+	maxConnections, err := c.optionalInt("HNSW_MAX_CONNECTIONS", 16)
+	if err != nil {
+		return err
+	}
+	if maxConnections < 2 {
+		return fmt.Errorf("HNSW_MAX_CONNECTIONS must be at least 2, got: %d", maxConnections)
+	}
+	c.HNSWMaxConnections = maxConnections
+
+	efConstruction, err := c.optionalInt("HNSW_EF_CONSTRUCTION", 128)
+	if err != nil {
+		return err
+	}
+	if efConstruction < 1 {
+		return fmt.Errorf("HNSW_EF_CONSTRUCTION must be at least 1, got: %d", efConstruction)
+	}
+	c.HNSWEfConstruction = efConstruction
+
+	return nil
+}
+
+const (
+	// VectorQuantizationNone serves nearest neighbor searches from the exact
+	// vectors through the configured VECTOR_INDEX_TYPE
+	VectorQuantizationNone = "none"
+	// VectorQuantizationInt8 serves nearest neighbor searches by scanning int8
+	// quantized vectors from QUANTIZED_FILE and re-ranking the best candidates
+	// with the exact vectors
+	VectorQuantizationInt8 = "int8"
+)
+
+func (c *Config) initVectorQuantization() error {
+	quantization := c.optionalString("VECTOR_QUANTIZATION", VectorQuantizationNone)
+	if quantization != VectorQuantizationNone && quantization != VectorQuantizationInt8 {
+		return fmt.Errorf("VECTOR_QUANTIZATION must be one of '%s' or '%s', got: '%s'",
+			VectorQuantizationNone, VectorQuantizationInt8, quantization)
+	}
+	c.VectorQuantization = quantization
+
+	if quantization != VectorQuantizationInt8 {
+		return nil
+	}
+
+	if c.VectorIndexType == VectorIndexHNSW {
+		return fmt.Errorf("VECTOR_QUANTIZATION '%s' replaces the vector index and can't be combined "+
+			"with VECTOR_INDEX_TYPE '%s'", VectorQuantizationInt8, VectorIndexHNSW)
+	}
+
+	for i := range c.Languages {
+		l := &c.Languages[i]
+		l.QuantizedFile = c.optionalString(l.varName("QUANTIZED_FILE"), l.KNNFile+".q8")
+	}
+
+	rerankFactor, err := c.optionalInt("QUANTIZATION_RERANK_FACTOR", 4)
+	if err != nil {
+		return err
+	}
+	if rerankFactor < 1 {
+		return fmt.Errorf("QUANTIZATION_RERANK_FACTOR must be at least 1, got: %d", rerankFactor)
+	}
+	c.QuantizationRerankFactor = rerankFactor
+
+	return nil
+}
+
 func (c *Config) optionalInt(varName string, defaultValue int) (int, error) {
 	value := os.Getenv(varName)
 	if value == "" {
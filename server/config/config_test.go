@@ -0,0 +1,169 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// withEnv sets the env vars and returns a func to unset them again
+func withEnv(t *testing.T, env map[string]string) func() {
+	for key, value := range env {
+		require.Nil(t, os.Setenv(key, value))
+	}
+
+	return func() {
+		for key := range env {
+			os.Unsetenv(key)
+		}
+	}
+}
+
+func TestLanguages(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+
+	t.Run("a single language from the unsuffixed vars", func(t *testing.T) {
+		defer withEnv(t, map[string]string{
+			"KNN_FILE":       "c11y.knn",
+			"IDX_FILE":       "c11y.idx",
+			"STOPWORDS_FILE": "stopwords.json",
+		})()
+
+		cfg, err := New(logger)
+		require.Nil(t, err)
+		assert.Equal(t, "en", cfg.DefaultLanguage)
+		assert.Equal(t, "en", cfg.ExtensionsLegacyLanguage)
+		assert.Equal(t, []LanguageConfig{{
+			Language:      "en",
+			KNNFile:       "c11y.knn",
+			IDXFile:       "c11y.idx",
+			StopwordsFile: "stopwords.json",
+			Tokenizer:     "unicode-word",
+
+			UnicodeNormalization: "nfkc",
+		}}, cfg.Languages)
+	})
+
+	t.Run("several languages from the suffixed vars", func(t *testing.T) {
+		defer withEnv(t, map[string]string{
+			"LANGUAGES":                             "en, de",
+			"DEFAULT_LANGUAGE":                      "de",
+			"VECTOR_INDEX_TYPE":                     "hnsw",
+			"KNN_FILE_EN":                           "en.knn",
+			"IDX_FILE_EN":                           "en.idx",
+			"STOPWORDS_FILE_EN":                     "en.json",
+			"KNN_FILE_DE":                           "de.knn",
+			"IDX_FILE_DE":                           "de.idx",
+			"STOPWORDS_FILE_DE":                     "de.json",
+			"HNSW_FILE_DE":                          "de.graph",
+			"TOKENIZER_DE":                          "whitespace",
+			"UNICODE_NORMALIZATION_DE":              "nfc",
+			"FOLD_DIACRITICS_DE":                    "true",
+			"ENABLE_COMPOUND_SPLITTING":             "true",
+			"COMPOUND_SPLITTING_DICTIONARY_FILE_EN": "en.dic",
+			"COMPOUND_SPLITTING_DICTIONARY_FILE_DE": "de.dic",
+			"ENABLE_SUBWORD_VECTORS":                "true",
+			"SUBWORD_FILE_DE":                       "de.subwords",
+		})()
+
+		cfg, err := New(logger)
+		require.Nil(t, err)
+		assert.Equal(t, "de", cfg.DefaultLanguage)
+		assert.Equal(t, "", cfg.ExtensionsLegacyLanguage)
+
+		de, ok := cfg.Language("")
+		require.True(t, ok)
+		assert.Equal(t, LanguageConfig{
+			Language:                        "de",
+			KNNFile:                         "de.knn",
+			IDXFile:                         "de.idx",
+			StopwordsFile:                   "de.json",
+			CompoundSplittingDictionaryFile: "de.dic",
+			HNSWFile:                        "de.graph",
+			SubwordFile:                     "de.subwords",
+			Tokenizer:                       "whitespace",
+			UnicodeNormalization:            "nfc",
+			FoldDiacritics:                  true,
+			varSuffix:                       "_DE",
+		}, de)
+
+		en, ok := cfg.Language("en")
+		require.True(t, ok)
+		assert.Equal(t, "en.knn.hnsw", en.HNSWFile)
+		assert.Equal(t, "en.dic", en.CompoundSplittingDictionaryFile)
+		assert.Equal(t, "en.knn.subwords", en.SubwordFile)
+		assert.Equal(t, "unicode-word", en.Tokenizer)
+
+		_, ok = cfg.Language("fr")
+		assert.False(t, ok)
+	})
+
+	t.Run("a language without files", func(t *testing.T) {
+		defer withEnv(t, map[string]string{
+			"LANGUAGES":         "en,de",
+			"KNN_FILE_EN":       "en.knn",
+			"IDX_FILE_EN":       "en.idx",
+			"STOPWORDS_FILE_EN": "en.json",
+		})()
+
+		_, err := New(logger)
+		assert.EqualError(t, err, "could not load config from env: "+
+			"required variable 'KNN_FILE_DE' is not set")
+	})
+
+	t.Run("an unknown tokenizer", func(t *testing.T) {
+		defer withEnv(t, map[string]string{
+			"KNN_FILE":       "c11y.knn",
+			"IDX_FILE":       "c11y.idx",
+			"STOPWORDS_FILE": "stopwords.json",
+			"TOKENIZER":      "sentencepiece",
+		})()
+
+		_, err := New(logger)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("a default language which isn't served", func(t *testing.T) {
+		defer withEnv(t, map[string]string{
+			"LANGUAGES":        "en,de",
+			"DEFAULT_LANGUAGE": "nl",
+		})()
+
+		_, err := New(logger)
+		assert.EqualError(t, err, "could not load config from env: "+
+			"DEFAULT_LANGUAGE 'nl' is not one of the LANGUAGES 'en,de'")
+	})
+
+	t.Run("the language of the unprefixed extensions", func(t *testing.T) {
+		defer withEnv(t, map[string]string{
+			"LANGUAGES":                  "en,de",
+			"DEFAULT_LANGUAGE":           "de",
+			"EXTENSIONS_LEGACY_LANGUAGE": "en",
+			"KNN_FILE_EN":                "en.knn",
+			"IDX_FILE_EN":                "en.idx",
+			"STOPWORDS_FILE_EN":          "en.json",
+			"KNN_FILE_DE":                "de.knn",
+			"IDX_FILE_DE":                "de.idx",
+			"STOPWORDS_FILE_DE":          "de.json",
+		})()
+
+		cfg, err := New(logger)
+		require.Nil(t, err)
+		assert.Equal(t, "de", cfg.DefaultLanguage)
+		assert.Equal(t, "en", cfg.ExtensionsLegacyLanguage)
+	})
+
+	t.Run("unprefixed extensions of a language which isn't served", func(t *testing.T) {
+		defer withEnv(t, map[string]string{
+			"LANGUAGES":                  "en,de",
+			"EXTENSIONS_LEGACY_LANGUAGE": "nl",
+		})()
+
+		_, err := New(logger)
+		assert.EqualError(t, err, "could not load config from env: "+
+			"EXTENSIONS_LEGACY_LANGUAGE 'nl' is not one of the LANGUAGES 'en,de'")
+	})
+}
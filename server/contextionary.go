@@ -2,80 +2,273 @@ package main
 
 import (
 	"fmt"
+	"sync"
 	"time"
 
+	"github.com/sirupsen/logrus"
 	"github.com/weaviate/contextionary/compoundsplitting"
 
 	"github.com/weaviate/contextionary/adapters/repos"
 	core "github.com/weaviate/contextionary/contextionary/core"
+	"github.com/weaviate/contextionary/contextionary/core/fuzzy"
 	"github.com/weaviate/contextionary/contextionary/core/stopwords"
+	"github.com/weaviate/contextionary/contextionary/core/subword"
 	"github.com/weaviate/contextionary/extensions"
+	"github.com/weaviate/contextionary/normalization"
+	"github.com/weaviate/contextionary/server/config"
+	"github.com/weaviate/contextionary/tokenizer"
 )
 
 func (s *server) init() error {
 	s.logger.WithField("config", s.config).Debugf("starting up with this config")
 
-	if err := s.loadRawContextionary(); err != nil {
-		return err
+	// ExtensionsStorageMode == "weaviate" is now a default storage option
+	er := repos.NewExtensionsRepo(s.logger, s.config, 1*time.Second)
+
+	s.languages = map[string]*language{}
+	for _, cfg := range s.config.Languages {
+		l, err := s.initLanguage(cfg, er)
+		if err != nil {
+			return fmt.Errorf("language '%s': %v", cfg.Language, err)
+		}
+
+		s.languages[cfg.Language] = l
+	}
+
+	if s.config.SchemaProviderURL != "" {
+		schemaRepo := repos.NewSchemaRepo(s.logger, s.config, s.config.SchemaWatchInterval)
+		go s.watchSchema(schemaRepo.WatchAll(s.stop), s.stop)
+	}
+
+	if s.config.ModelWatchInterval > 0 {
+		for _, l := range s.languages {
+			go s.watchModel(l, s.config.ModelWatchInterval, s.stop)
+		}
 	}
 
-	swDetector, err := stopwords.NewFromFile(s.config.StopwordsFile)
+	return nil
+}
+
+func (s *server) initLanguage(cfg config.LanguageConfig, er extensions.Repo) (*language, error) {
+	l := &language{config: cfg}
+
+	normalizer, err := normalization.New(cfg.UnicodeNormalization, cfg.Language, cfg.FoldDiacritics)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	s.stopwordDetector = swDetector
+	l.normalizer = normalizer
 
-	if err := s.buildContextionary(); err != nil {
-		return err
+	swDetector, err := stopwords.NewFromFile(cfg.StopwordsFile)
+	if err != nil {
+		return nil, err
 	}
+	swDetector.Normalize(normalizer.Normalize)
+	l.stopwordDetector = swDetector
 
-	var er extensionRepo
-	var extensionRetriever extensionLookerUpper
+	namespaced := extensions.NewNamespacedRepo(er, s.extensionsNamespace(l))
+	l.extensionLookerUpper = extensions.NewLookerUpper(namespaced)
 
-	// ExtensionsStorageMode == "weaviate" is now a default storage option
-	er = repos.NewExtensionsRepo(s.logger, s.config, 1*time.Second)
-	extensionRetriever = extensions.NewLookerUpper(er)
+	t, err := tokenizer.New(cfg.Tokenizer)
+	if err != nil {
+		return nil, err
+	}
+	l.tokenizer = t
 
-	compoundSplitter, err := s.initCompoundSplitter()
+	compoundSplitter, err := s.initCompoundSplitter(cfg)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	vectorizer, err := NewVectorizer(s.rawContextionary, s.stopwordDetector, s.config, s.logger,
-		NewSplitter(), extensionRetriever, compoundSplitter)
+	l.compoundSplitter = compoundSplitter
+
+	m, err := s.loadModel(l)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	l.models.swap(m)
 
-	s.vectorizer = vectorizer
-	s.extensionStorer = extensions.NewStorer(s.vectorizer, er, s.logger)
-	s.extensionLookerUpper = extensionRetriever
+	l.extensionStorer = extensions.NewStorer(&currentModelVectorizer{&l.models}, namespaced, s.logger)
 
-	return nil
+	s.logger.WithField("action", "startup").WithField("language", cfg.Language).
+		Info("loaded language")
+
+	return l, nil
 }
 
-func (s *server) loadRawContextionary() error {
-	c, err := core.LoadVectorFromDisk(s.config.KNNFile, s.config.IDXFile)
+// loadModel loads the contextionary files and builds everything which
+// depends on them
+func (s *server) loadModel(l *language) (*model, error) {
+	raw, err := s.loadRawContextionary(l.config)
 	if err != nil {
-		return fmt.Errorf("could not initialize (raw) contextionary: %v", err)
+		return nil, err
 	}
 
-	s.rawContextionary = c
-	return nil
+	combined, err := s.buildContextionary(l, raw)
+	if err != nil {
+		closeContextionary(raw)
+		return nil, err
+	}
+
+	m := &model{raw: raw, combined: combined, files: newSharedFiles()}
+
+	// the vectorizer must not be handed a nil *subword.Model, which would
+	// be a non-nil interface
+	var subwords subwordVectorizer
+	if s.config.EnableSubwordVectors {
+		m.subwords, err = subword.Load(l.config.SubwordFile, raw.GetVectorLength())
+		if err != nil {
+			closeContextionary(raw)
+			return nil, fmt.Errorf("could not load subword vectors: %v", err)
+		}
+		subwords = m.subwords
+	}
+
+	// most requests never need suggestions, so the index is only built once
+	// they are first asked for
+	suggestions := &lazySuggestionIndex{
+		build:  func() (*fuzzy.Index, error) { return s.buildSuggestionIndex(l, raw) },
+		logger: s.logger.WithField("language", l.config.Language),
+	}
+
+	m.vectorizer, err = NewVectorizer(raw, l.stopwordDetector, s.config, s.logger,
+		l.tokenizer, l.extensionLookerUpper, l.compoundSplitter, l.normalizer, subwords, suggestions,
+		s.metrics.wordCounter(l.config.Language))
+	if err != nil {
+		closeContextionary(raw)
+		if m.subwords != nil {
+			m.subwords.Close()
+		}
+		return nil, err
+	}
+
+	return m, nil
 }
 
-type stopwordDetector interface {
-	IsStopWord(word string) bool
+// buildSuggestionIndex indexes all words of the raw contextionary, so that
+// words which are not in it can be matched with the ones which are
+func (s *server) buildSuggestionIndex(l *language, raw core.Contextionary) (*fuzzy.Index, error) {
+	before := time.Now()
+	words := make([]string, raw.GetNumberOfItems())
+	for i := range words {
+		word, err := raw.ItemIndexToWord(core.ItemIndex(i))
+		if err != nil {
+			return nil, fmt.Errorf("build suggestion index: %v", err)
+		}
+		words[i] = word
+	}
+
+	index := fuzzy.NewIndex(words)
+	s.logger.WithField("action", "build_suggestion_index").
+		WithField("language", l.config.Language).
+		WithField("took", time.Since(before)).
+		Info("built suggestion index")
+
+	return index, nil
 }
 
-// any time the schema changes the contextionary needs to be rebuilt.
-func (s *server) buildContextionary() error {
-	s.combinedContextionary = s.rawContextionary
-	return nil
+// lazySuggestionIndex builds the suggestion index on the first search, so
+// that models whose suggestions are never used don't pay for it on every
+// load. Searches wait for the index while it is built.
+type lazySuggestionIndex struct {
+	once   sync.Once
+	build  func() (*fuzzy.Index, error)
+	logger logrus.FieldLogger
+	index  *fuzzy.Index
 }
 
-func (s *server) initCompoundSplitter() (compoundSplitter, error) {
+// Search finds the words within maxEdits of word. There are no matches if
+// the index couldn't be built.
+func (i *lazySuggestionIndex) Search(word string, maxEdits int) []fuzzy.Match {
+	i.once.Do(func() {
+		index, err := i.build()
+		if err != nil {
+			i.logger.WithField("action", "build_suggestion_index").WithError(err).
+				Error("could not build suggestion index, there are no suggestions")
+			return
+		}
+		i.index = index
+	})
+
+	if i.index == nil {
+		return nil
+	}
+
+	return i.index.Search(word, maxEdits)
+}
+
+func (s *server) loadRawContextionary(cfg config.LanguageConfig) (core.Contextionary, error) {
+	if err := s.validateModel(cfg); err != nil {
+		return nil, err
+	}
+
+	var c core.Contextionary
+	var err error
+
+	switch {
+	case s.config.VectorQuantization == config.VectorQuantizationInt8:
+		s.logger.WithField("action", "startup").WithField("file", cfg.QuantizedFile).
+			Info("loading quantized vectors, they will be built first if they don't exist or don't match the model")
+		c, err = core.LoadQuantizedVectorFromDisk(cfg.KNNFile, cfg.IDXFile, core.QuantizedConfig{
+			File:         cfg.QuantizedFile,
+			RerankFactor: s.config.QuantizationRerankFactor,
+			Logger:       s.logger.WithField("action", "startup").WithField("language", cfg.Language),
+		})
+	case s.config.VectorIndexType == config.VectorIndexHNSW:
+		s.logger.WithField("action", "startup").WithField("file", cfg.HNSWFile).
+			Info("loading hnsw index, it will be built first if it doesn't exist or doesn't match the model")
+		c, err = core.LoadHNSWVectorFromDisk(cfg.KNNFile, cfg.IDXFile, core.HNSWConfig{
+			File:           cfg.HNSWFile,
+			Ef:             s.config.HNSWEf,
+			MaxConnections: s.config.HNSWMaxConnections,
+			EfConstruction: s.config.HNSWEfConstruction,
+			Logger:         s.logger.WithField("action", "startup").WithField("language", cfg.Language),
+		})
+	default:
+		c, err = core.LoadVectorFromDisk(cfg.KNNFile, cfg.IDXFile)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not initialize (raw) contextionary: %v", err)
+	}
+
+	s.logger.WithField("action", "startup").
+		WithField("language", cfg.Language).
+		WithField("metric", c.Metric()).
+		WithField("certainty_max_distance", c.CertaintyCalibration().MaxDistance).
+		Info("loaded contextionary")
+
+	return c, nil
+}
+
+// validateModel makes sure the model files are consistent before they are
+// mmapped, a corrupt model would otherwise lead to panics or wrong results
+// at query time
+func (s *server) validateModel(cfg config.LanguageConfig) error {
+	err := core.ValidateModel(cfg.KNNFile, cfg.IDXFile)
+	if err == nil {
+		return nil
+	}
+
+	if modelErr, ok := err.(*core.ModelValidationError); ok {
+		for _, e := range modelErr.Errors {
+			s.logger.WithField("action", "startup_validate_model").
+				WithField("file", e.File).
+				WithField("check", e.Check).
+				WithField("item", e.Item).
+				Error(e.Message)
+		}
+
+		return fmt.Errorf("contextionary model is invalid, found %d problems", len(modelErr.Errors))
+	}
+
+	return fmt.Errorf("validate contextionary model: %v", err)
+}
+
+type stopwordDetector interface {
+	IsStopWord(word string) bool
+}
+
+func (s *server) initCompoundSplitter(cfg config.LanguageConfig) (compoundSplitter, error) {
 	if s.config.EnableCompundSplitting {
-		dict, err := compoundsplitting.NewContextionaryDict(s.config.CompoundSplittingDictionaryFile)
+		dict, err := compoundsplitting.NewContextionaryDict(cfg.CompoundSplittingDictionaryFile)
 		if err != nil {
 			return nil, err
 		}
@@ -84,8 +277,3 @@ func (s *server) initCompoundSplitter() (compoundSplitter, error) {
 		return compoundsplitting.NewNoopSplitter(), nil
 	}
 }
-
-type extensionRepo interface {
-	extensions.RetrieverRepo
-	extensions.StorerRepo
-}
@@ -1,19 +1,23 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math"
+	"sort"
+	"strconv"
 	"strings"
-	"sync"
-	"sync/atomic"
-	"time"
+	"unicode/utf8"
 
 	"github.com/sirupsen/logrus"
 	core "github.com/weaviate/contextionary/contextionary/core"
+	"github.com/weaviate/contextionary/contextionary/core/fuzzy"
 	errortypes "github.com/weaviate/contextionary/errors"
 	"github.com/weaviate/contextionary/extensions"
+	"github.com/weaviate/contextionary/server/cache"
 	"github.com/weaviate/contextionary/server/config"
+	"github.com/weaviate/contextionary/tokenizer"
 )
 
 type Vectorizer struct {
@@ -21,44 +25,81 @@ type Vectorizer struct {
 	stopwordDetector     stopwordDetector
 	config               *config.Config
 	logger               logrus.FieldLogger
-	splitter             splitter
+	tokenizer            tokenizer.Tokenizer
 	extensions           extensionLookerUpper
-	cache                *sync.Map
-	cacheCount           int32
+	cache                *cache.Cache
 	compoundWordSplitter compoundSplitter
+	normalizer           normalizer
+	subwords             subwordVectorizer
+	suggestions          fuzzyIndex
+	words                *wordCounter
 }
 
 const (
 	OccurrenceStrategyLog    = "log"
 	OccurrenceStrategyLinear = "linear"
+
+	// OccurrenceStrategySIF weighs words by their smooth inverse frequency
+	// a/(a+p(w)), where p(w) is the probability of the word in the
+	// contextionary
+	OccurrenceStrategySIF = "sif"
 )
 
-type splitter interface {
-	Split(corpus string) []string
+type compoundSplitter interface {
+	Split(ctx context.Context, word string) ([]string, error)
 }
 
-type compoundSplitter interface {
-	Split(word string) ([]string, error)
+type normalizer interface {
+	Normalize(input string) string
+}
+
+// subwordVectorizer synthesizes the vector of a word from its character
+// n-grams, it returns nil if none of them are known
+type subwordVectorizer interface {
+	Vector(word string) []float32
+}
+
+// synthesizedOccurrence is the occurrence of words whose vectors were
+// synthesized. They aren't in the contextionary, so they are treated as very
+// rare, but not so rare that the log weighing of a corpus which consists of
+// nothing else breaks.
+const synthesizedOccurrence uint64 = 2
+
+// fuzzyIndex finds the words of the contextionary within an edit distance
+// of a word, the IDs of the matches are item indices
+type fuzzyIndex interface {
+	Search(word string, maxEdits int) []fuzzy.Match
 }
 
 type extensionLookerUpper interface {
-	Lookup(concept string) (*extensions.Extension, error)
+	Lookup(ctx context.Context, concept string) (*extensions.Extension, error)
+
+	// Generation changes whenever the extensions change
+	Generation() uint64
 }
 
 func NewVectorizer(c11y core.Contextionary, sw stopwordDetector,
 	config *config.Config, logger logrus.FieldLogger,
-	splitter splitter, extensions extensionLookerUpper,
-	compoundWordSplitter compoundSplitter) (*Vectorizer, error) {
+	tokenizer tokenizer.Tokenizer, extensions extensionLookerUpper,
+	compoundWordSplitter compoundSplitter, normalizer normalizer,
+	subwords subwordVectorizer, suggestions fuzzyIndex, words *wordCounter) (*Vectorizer, error) {
 
 	v := &Vectorizer{
-		c11y:                 c11y,
-		stopwordDetector:     sw,
-		config:               config,
-		splitter:             splitter,
-		logger:               logger,
-		extensions:           extensions,
-		cache:                &sync.Map{},
+		c11y:             c11y,
+		stopwordDetector: sw,
+		config:           config,
+		tokenizer:        tokenizer,
+		logger:           logger,
+		extensions:       extensions,
+		cache: cache.New(cache.Options{
+			Size: config.MaximumVectorCacheSize,
+			TTL:  config.VectorCacheTTL,
+		}),
 		compoundWordSplitter: compoundWordSplitter,
+		normalizer:           normalizer,
+		subwords:             subwords,
+		suggestions:          suggestions,
+		words:                words,
 	}
 
 	if err := v.validateConfig(); err != nil {
@@ -71,41 +112,104 @@ func NewVectorizer(c11y core.Contextionary, sw stopwordDetector,
 func (cv *Vectorizer) validateConfig() error {
 	s := cv.config.OccurrenceWeightStrategy
 	switch s {
-	case OccurrenceStrategyLinear, OccurrenceStrategyLog:
+	case OccurrenceStrategyLinear, OccurrenceStrategyLog, OccurrenceStrategySIF:
 		// valid
 	default:
 		return fmt.Errorf("invalid config option: occurrence weight strategy: uncrecoginzed strategy '%s'", s)
 	}
 
+	if cv.config.RemovePrincipalComponent {
+		if s != OccurrenceStrategySIF {
+			return fmt.Errorf("invalid config option: removing the principal component requires "+
+				"the occurrence weight strategy '%s', got '%s'", OccurrenceStrategySIF, s)
+		}
+
+		if cv.c11y.SIFParameters().PrincipalComponent == nil {
+			return fmt.Errorf("invalid config option: removing the principal component requires " +
+				"a contextionary generated with --principal-component")
+		}
+	}
+
 	return nil
 }
 
 var ErrNoUsableWords = errors.New("all words in corpus were either stopwords" +
 	" or not present in the contextionary, cannot build vector")
 
-func (cv *Vectorizer) Corpi(corpi []string, weightOverrides map[string]string) (*core.Vector, error) {
+// CorpiOptions are the options of a single request to vectorize corpi
+type CorpiOptions struct {
+	// Tokenizer splits the corpi, the tokenizer of the vectorizer if nil
+	Tokenizer tokenizer.Tokenizer
+
+	// UseSuggestions replaces words which are not in the contextionary with
+	// the closest word that is, see Suggest
+	UseSuggestions bool
+
+	// Weights of the corpi, in the same order. The corpus vectors are
+	// combined with these weights and the weights of their source elements
+	// are multiplied by them. All corpi are weighted equally if nil.
+	Weights []float32
+}
+
+// Corpi vectorizes the corpi with the tokenizer of the vectorizer
+func (cv *Vectorizer) Corpi(ctx context.Context, corpi []string,
+	weightOverrides map[string]string) (*core.Vector, error) {
+	return cv.CorpiWithOptions(ctx, corpi, weightOverrides, CorpiOptions{})
+}
+
+// CorpiWithOptions vectorizes the corpi with the options of a request. It
+// stops with the context's error once the context is cancelled or expired.
+func (cv *Vectorizer) CorpiWithOptions(ctx context.Context, corpi []string,
+	weightOverrides map[string]string, opts CorpiOptions) (*core.Vector, error) {
+	if opts.Tokenizer == nil {
+		opts.Tokenizer = cv.tokenizer
+	}
+
+	if err := validateCorpusWeights(opts.Weights, len(corpi)); err != nil {
+		return nil, err
+	}
+
 	var corpusVectors []core.Vector
-	if weightOverrides == nil {
-		// so we don't have to do no nil checks down the line
-		weightOverrides = map[string]string{}
+	var corpusWeights []float32
+	// the overrides refer to the words of the corpi, so they need to be
+	// normalized the same way. This also saves us nil checks down the line.
+	overrides := map[string]string{}
+	for word, expression := range weightOverrides {
+		overrides[cv.normalizer.Normalize(word)] = expression
 	}
 
 	var source []core.InputElement
 
 	for i, corpus := range corpi {
-		parts := cv.splitter.Split(corpus)
+		parts := opts.Tokenizer.Tokenize(cv.normalizer.Normalize(corpus))
 		if len(parts) == 0 {
 			continue
 		}
 
-		v, err := cv.vectorForWordOrWords(parts, weightOverrides)
+		v, err := cv.vectorForWordOrWords(ctx, parts, overrides, opts)
 		if err != nil {
-			return nil, fmt.Errorf("at corpus %d: %v", i, err)
+			return nil, fmt.Errorf("at corpus %d: %w", i, err)
 		}
 
-		if v != nil {
-			corpusVectors = append(corpusVectors, *v.vector)
-			source = append(source, v.source...)
+		if v == nil {
+			continue
+		}
+
+		weight := float32(1)
+		if opts.Weights != nil {
+			weight = opts.Weights[i]
+		}
+
+		if weight == 0 {
+			// the corpus doesn't contribute anything
+			continue
+		}
+
+		corpusVectors = append(corpusVectors, *v.vector)
+		corpusWeights = append(corpusWeights, weight)
+		for _, elem := range v.source {
+			elem.Weight *= float64(weight)
+			source = append(source, elem)
 		}
 	}
 
@@ -113,21 +217,53 @@ func (cv *Vectorizer) Corpi(corpi []string, weightOverrides map[string]string) (
 		return nil, ErrNoUsableWords
 	}
 
-	vector, err := core.ComputeCentroid(corpusVectors)
+	vector, err := core.ComputeWeightedCentroid(corpusVectors, corpusWeights)
 	if err != nil {
 		return nil, err
 	}
 
+	if cv.config.RemovePrincipalComponent {
+		removed := core.NewVector(cv.c11y.SIFParameters().RemovePrincipalComponent(vector.ToArray()))
+		vector = &removed
+	}
+
 	vector.Source = source
 	return vector, nil
 }
 
-func (cv *Vectorizer) vectorForWordOrWords(parts []string, overrides map[string]string) (*vectorWithOccurrence, error) {
+func validateCorpusWeights(weights []float32, corpi int) error {
+	if weights == nil {
+		return nil
+	}
+
+	if len(weights) != corpi {
+		return errortypes.NewInvalidUserInputf("got %d weights for %d corpi, "+
+			"there must be exactly one weight per corpus", len(weights), corpi)
+	}
+
+	var sum float32
+	for i, weight := range weights {
+		if weight < 0 || math.IsNaN(float64(weight)) || math.IsInf(float64(weight), 0) {
+			return errortypes.NewInvalidUserInputf("weight of corpus %d must be a "+
+				"non-negative number, got %f", i, weight)
+		}
+		sum += weight
+	}
+
+	if sum == 0 && corpi > 0 {
+		return errortypes.NewInvalidUserInputf("at least one corpus must have a weight larger than 0")
+	}
+
+	return nil
+}
+
+func (cv *Vectorizer) vectorForWordOrWords(ctx context.Context, parts []string,
+	overrides map[string]string, opts CorpiOptions) (*vectorWithOccurrence, error) {
 	if len(parts) > 1 {
-		return cv.vectorForWords(parts, overrides)
+		return cv.vectorForWords(ctx, parts, overrides, opts)
 	}
 
-	return cv.VectorForWord(parts[0])
+	return cv.vectorForSingleWord(ctx, parts[0], opts.UseSuggestions)
 }
 
 type vectorWithOccurrence struct {
@@ -136,8 +272,9 @@ type vectorWithOccurrence struct {
 	source     []core.InputElement
 }
 
-func (cv *Vectorizer) vectorForWords(words []string, overrides map[string]string) (*vectorWithOccurrence, error) {
-	vectors, occurrences, words, err := cv.vectorsAndOccurrences(words)
+func (cv *Vectorizer) vectorForWords(ctx context.Context, words []string,
+	overrides map[string]string, opts CorpiOptions) (*vectorWithOccurrence, error) {
+	vectors, occurrences, words, synthesized, err := cv.vectorsAndOccurrences(ctx, words, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -159,16 +296,18 @@ func (cv *Vectorizer) vectorForWords(words []string, overrides map[string]string
 
 	return &vectorWithOccurrence{
 		vector: centroid,
-		source: buildVectorInputElements(words, weights, occurrences),
+		source: buildVectorInputElements(words, weights, occurrences, synthesized),
 	}, nil
 }
 
-func buildVectorInputElements(words []string, weights []float64, occurrences []uint64) []core.InputElement {
+func buildVectorInputElements(words []string, weights []float64, occurrences []uint64,
+	synthesized []bool) []core.InputElement {
 	out := make([]core.InputElement, len(words))
 	for i := range words {
 		out[i].Concept = words[i]
 		out[i].Weight = weights[i]
 		out[i].Occurrence = occurrences[i]
+		out[i].Synthesized = synthesized[i]
 	}
 
 	return out
@@ -182,12 +321,18 @@ func float64SliceTofloat32(in []float64) []float32 {
 	return out
 }
 
-func (cv *Vectorizer) vectorsAndOccurrences(words []string) ([]core.Vector, []uint64, []string, error) {
+func (cv *Vectorizer) vectorsAndOccurrences(ctx context.Context, words []string,
+	opts CorpiOptions) ([]core.Vector, []uint64, []string, []bool, error) {
 	var vectors []core.Vector
 	var occurrences []uint64
 	var debugOutput []string
+	var synthesized []bool
 
 	for wordPos := 0; wordPos < len(words); wordPos++ {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, nil, nil, err
+		}
+
 	additionalWordLoop:
 		for additionalWords := cv.config.MaxCompoundWordLength - 1; additionalWords >= 0; additionalWords-- {
 			if (wordPos + additionalWords) < len(words) {
@@ -196,20 +341,34 @@ func (cv *Vectorizer) vectorsAndOccurrences(words []string) ([]core.Vector, []ui
 				// check.
 				// Note that n goes all the way down to zero, so once we didn't find
 				// any compound words, we're checking the individual word.
-				compound := cv.compound(cv.nextWords(words, wordPos, additionalWords)...)
-				vector, err := cv.VectorForWord(compound)
+				compound, ok := opts.Tokenizer.Compound(cv.nextWords(words, wordPos, additionalWords))
+				if !ok {
+					// the tokenizer knows these words can't form a compound word
+					continue
+				}
+
+				var vector *vectorWithOccurrence
+				var err error
+				if additionalWords == 0 {
+					vector, err = cv.vectorForSingleWord(ctx, compound, opts.UseSuggestions)
+				} else {
+					vector, err = cv.vectorForWord(ctx, compound)
+				}
 				if err != nil {
-					return nil, nil, nil, err
+					return nil, nil, nil, nil, err
 				}
 
 				if vector != nil {
 					// this compound word exists, use its vector and occurrence
 					vectors = append(vectors, *vector.vector)
 					occurrences = append(occurrences, vector.occurrence)
+					isSynthesized := false
 					if len(vector.source) > 0 {
 						compound = vector.source[0].Concept
+						isSynthesized = vector.source[0].Synthesized
 					}
 					debugOutput = append(debugOutput, compound)
+					synthesized = append(synthesized, isSynthesized)
 
 					// however, now we must make sure to skip the additionalWords
 					wordPos += additionalWords
@@ -224,7 +383,7 @@ func (cv *Vectorizer) vectorsAndOccurrences(words []string) ([]core.Vector, []ui
 		WithField("interpreted_as", strings.Join(debugOutput, " ")).
 		Debug()
 
-	return vectors, occurrences, debugOutput, nil
+	return vectors, occurrences, debugOutput, synthesized, nil
 }
 
 func (cv *Vectorizer) nextWords(words []string, startPos int, additional int) []string {
@@ -232,24 +391,236 @@ func (cv *Vectorizer) nextWords(words []string, startPos int, additional int) []
 	return words[startPos:endPos]
 }
 
-func (cv *Vectorizer) compound(words ...string) string {
-	return strings.Join(words, "_")
+// VectorForWord normalizes the word and returns its vector, nil if the word
+// is neither an extension nor in the contextionary and its vector can't be
+// synthesized either
+func (cv *Vectorizer) VectorForWord(ctx context.Context, word string) (*vectorWithOccurrence, error) {
+	return cv.vectorForSingleWord(ctx, cv.normalizer.Normalize(word), false)
+}
+
+// vectorForSingleWord falls back to the closest suggestion, if the request
+// allows it, and then to synthesizing the vector from the character n-grams
+// for words which can't be found otherwise. This must only be used for
+// single words, otherwise any sequence of words would be taken for a
+// compound word. Neither fallback is cached, as both depend on the request
+// and neither is a word of the contextionary.
+func (cv *Vectorizer) vectorForSingleWord(ctx context.Context, word string,
+	useSuggestions bool) (*vectorWithOccurrence, error) {
+	v, err := cv.vectorForWord(ctx, word)
+	if err != nil {
+		return nil, err
+	}
+	if v != nil {
+		cv.words.count(outcomePresent)
+		return v, nil
+	}
+
+	if cv.stopwordDetector.IsStopWord(word) {
+		cv.words.count(outcomeStopword)
+		return nil, nil
+	}
+
+	if useSuggestions {
+		v, err := cv.vectorFromSuggestion(ctx, word)
+		if err != nil {
+			return nil, err
+		}
+		if v != nil {
+			cv.words.count(outcomeSuggestion)
+			return v, nil
+		}
+	}
+
+	v = cv.vectorFromSubwords(word)
+	if v == nil {
+		cv.words.count(outcomeUnknown)
+		return nil, nil
+	}
+
+	cv.words.count(outcomeSynthesized)
+	return v, nil
+}
+
+// correctionMaxEdits is how many edits a word may be away from the
+// suggestion which replaces it. Short words are too close to too many other
+// words to be corrected reliably.
+func correctionMaxEdits(word string) int {
+	maxEdits := utf8.RuneCountInString(word) / 4
+	if maxEdits > 2 {
+		maxEdits = 2
+	}
+
+	return maxEdits
+}
+
+// suggestionMaxEdits caps maxEdits at a third of the word's length, but
+// allows at least one edit. A word is within a few edits of nearly every
+// short word, so the fuzzy index couldn't narrow down the words to compare
+// it with and the suggestions would be noise anyway.
+func suggestionMaxEdits(word string, maxEdits int) int {
+	limit := utf8.RuneCountInString(word) / 3
+	if limit < 1 {
+		limit = 1
+	}
+	if maxEdits > limit {
+		return limit
+	}
+
+	return maxEdits
+}
+
+func (cv *Vectorizer) vectorFromSuggestion(ctx context.Context, word string) (*vectorWithOccurrence, error) {
+	maxEdits := correctionMaxEdits(word)
+	if maxEdits == 0 {
+		return nil, nil
+	}
+
+	suggestions, err := cv.Suggest(word, maxEdits, 1)
+	if err != nil || len(suggestions) == 0 {
+		return nil, err
+	}
+
+	suggestion := suggestions[0].word
+	v, err := cv.vectorForWord(ctx, suggestion)
+	if err != nil || v == nil {
+		return nil, err
+	}
+
+	cv.logger.WithField("action", "vectorize_suggestion").
+		WithField("word", word).
+		WithField("suggestion", suggestion).
+		WithField("distance", suggestions[0].distance).
+		Debug("not present - replaced with suggestion")
+
+	return &vectorWithOccurrence{
+		vector:     v.vector,
+		occurrence: v.occurrence,
+		source: []core.InputElement{
+			{
+				Concept:    word + " (" + suggestion + ")",
+				Occurrence: v.occurrence,
+				Weight:     1,
+			},
+		},
+	}, nil
+}
+
+type wordSuggestion struct {
+	word       string
+	distance   int
+	occurrence uint64
+}
+
+// Suggest returns up to limit words of the contextionary within maxEdits of
+// the word, the closest and then the most common ones first. maxEdits is
+// capped by the length of the word, see suggestionMaxEdits. There are no
+// suggestions if the vectorizer doesn't have a fuzzy index.
+func (cv *Vectorizer) Suggest(word string, maxEdits, limit int) ([]wordSuggestion, error) {
+	if cv.suggestions == nil {
+		return nil, nil
+	}
+
+	matches := cv.suggestions.Search(word, suggestionMaxEdits(word, maxEdits))
+	out := make([]wordSuggestion, len(matches))
+	for i, match := range matches {
+		occurrence, err := cv.c11y.ItemIndexToOccurrence(core.ItemIndex(match.ID))
+		if err != nil {
+			return nil, err
+		}
+
+		out[i] = wordSuggestion{word: match.Word, distance: match.Distance, occurrence: occurrence}
+	}
+
+	sort.SliceStable(out, func(a, b int) bool {
+		if out[a].distance != out[b].distance {
+			return out[a].distance < out[b].distance
+		}
+		return out[a].occurrence > out[b].occurrence
+	})
+
+	if len(out) > limit {
+		out = out[:limit]
+	}
+
+	return out, nil
+}
+
+func (cv *Vectorizer) vectorFromSubwords(word string) *vectorWithOccurrence {
+	if cv.subwords == nil {
+		return nil
+	}
+
+	vector := cv.subwords.Vector(word)
+	cv.logger.WithField("action", "vectorize_subwords").
+		WithField("word", word).
+		WithField("synthesized", vector != nil).
+		Debug("not present - synthesizing from character n-grams")
+	if vector == nil {
+		return nil
+	}
+
+	synthesized := core.NewVector(vector)
+	return &vectorWithOccurrence{
+		vector:     &synthesized,
+		occurrence: synthesizedOccurrence,
+		source: []core.InputElement{
+			{
+				Concept:     word,
+				Occurrence:  synthesizedOccurrence,
+				Weight:      1,
+				Synthesized: true,
+			},
+		},
+	}
+}
+
+// cachedVector is the result of vectorForWord, vector is nil for words which
+// aren't in the contextionary, so that they aren't split into compounds again
+type cachedVector struct {
+	vector *vectorWithOccurrence
+}
+
+// vectorForWord is cached for every generation of the extensions, so that the
+// vectors of words don't outlive a change of their extensions
+func (cv *Vectorizer) vectorForWord(ctx context.Context, word string) (*vectorWithOccurrence, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	key := strconv.FormatUint(cv.extensions.Generation(), 10) + ":" + word
+	if cached, ok := cv.cache.Get(key); ok {
+		return cached.(cachedVector).vector, nil
+	}
+
+	v, err := cv.uncachedVectorForWord(ctx, word)
+	if err != nil {
+		// e.g. a cancelled request, which says nothing about the word
+		return nil, err
+	}
+
+	cv.cache.Set(key, cachedVector{vector: v})
+	return v, nil
+}
+
+// CacheStats of the vector cache
+func (cv *Vectorizer) CacheStats() cache.Stats {
+	return cv.cache.Stats()
 }
 
-func (cv *Vectorizer) VectorForWord(word string) (*vectorWithOccurrence, error) {
-	ext, err := cv.extensions.Lookup(word)
+func (cv *Vectorizer) uncachedVectorForWord(ctx context.Context, word string) (*vectorWithOccurrence, error) {
+	ext, err := cv.extensions.Lookup(ctx, word)
 	if err != nil {
-		return nil, fmt.Errorf("lookup custom word: %s", err)
+		return nil, fmt.Errorf("lookup custom word: %w", err)
 	}
 
 	if ext == nil {
-		return cv.vectorForLibraryWord(word)
+		return cv.vectorForLibraryWord(ctx, word)
 	}
 
 	return cv.vectorFromExtension(ext)
 }
 
-func (cv *Vectorizer) vectorForLibraryWord(word string) (*vectorWithOccurrence, error) {
+func (cv *Vectorizer) vectorForLibraryWord(ctx context.Context, word string) (*vectorWithOccurrence, error) {
 	if cv.stopwordDetector.IsStopWord(word) {
 		cv.logger.WithField("action", "vectorize_library_word").
 			WithField("word", word).
@@ -259,26 +630,6 @@ func (cv *Vectorizer) vectorForLibraryWord(word string) (*vectorWithOccurrence,
 		return nil, nil
 	}
 
-	if int(cv.cacheCount) > cv.config.MaximumVectorCacheSize {
-		before := time.Now()
-		cv.logger.WithField("action", "vectorize_start_purge_cache").
-			Debug("start purging vectorization cache")
-
-		cv.cache.Range(func(key, value interface{}) bool {
-			cv.cache.Delete(key)
-			atomic.AddInt32(&cv.cacheCount, -1)
-			return true
-		})
-
-		cv.logger.WithField("action", "vectorize_complete_purge_cache").
-			WithField("took", time.Since(before)).
-			Debug("complete purging vectorization cache")
-	}
-	cached, ok := cv.cache.Load(word)
-	if ok {
-		return cached.(*vectorWithOccurrence), nil
-	}
-
 	wi := cv.c11y.WordToItemIndex(word)
 	if wi.IsPresent() {
 		// create vector out of it
@@ -295,11 +646,11 @@ func (cv *Vectorizer) vectorForLibraryWord(word string) (*vectorWithOccurrence,
 			WithField("occurence", o).
 			Debug("present including")
 
-		return cv.newCachedVectorWithOccurence(word, v, o), nil
+		return newVectorWithOccurence(word, v, o), nil
 	}
 
 	// Word not in contextioanry try to compound split
-	compoundWords, splitterErr := cv.compoundWordSplitter.Split(word)
+	compoundWords, splitterErr := cv.compoundWordSplitter.Split(ctx, word)
 	if splitterErr != nil {
 		return nil, splitterErr
 	}
@@ -362,7 +713,7 @@ func (cv *Vectorizer) compoundToVectorWithOccurence(words []string) (*vectorWith
 	if err != nil {
 		return nil, err
 	}
-	return cv.newCachedVectorWithOccurence(strings.Join(words, ""), centroid, occurenceAvg, words...), nil
+	return newVectorWithOccurence(strings.Join(words, ""), centroid, occurenceAvg, words...), nil
 }
 
 func (cv *Vectorizer) itemIndexToVectorAndOccurence(wi core.ItemIndex) (*core.Vector, uint64, error) {
@@ -380,7 +731,7 @@ func (cv *Vectorizer) itemIndexToVectorAndOccurence(wi core.ItemIndex) (*core.Ve
 	return v, o, nil
 }
 
-func (cv *Vectorizer) newCachedVectorWithOccurence(word string, vector *core.Vector, occurence uint64, parts ...string) *vectorWithOccurrence {
+func newVectorWithOccurence(word string, vector *core.Vector, occurence uint64, parts ...string) *vectorWithOccurrence {
 	inputWord := word
 	if len(parts) > 0 {
 		allParts := strings.Join(parts, ", ")
@@ -399,8 +750,6 @@ func (cv *Vectorizer) newCachedVectorWithOccurence(word string, vector *core.Vec
 		},
 	}
 
-	cv.cache.Store(word, vo)
-	atomic.AddInt32(&cv.cacheCount, 1)
 	return vo
 }
 
@@ -435,6 +784,8 @@ func (cv *Vectorizer) occurrencesToWeight(occs []uint64, words []string,
 	case OccurrenceStrategyLinear:
 		linFactor := cv.config.OccurrenceWeightLinearFactor
 		weigher = makeLinWeigher(min, max, linFactor)
+	case OccurrenceStrategySIF:
+		weigher = makeSIFWeigher(cv.c11y.SIFParameters(), cv.config.SIFSmoothing)
 	default:
 		panic(fmt.Sprintf("vectorizer config validation is broken, impossible option '%s'",
 			cv.config.OccurrenceWeightStrategy))
@@ -480,6 +831,13 @@ func makeLinWeigher(min, max uint64, factor float32) func(uint64) float64 {
 	}
 }
 
+func makeSIFWeigher(params core.SIFParameters, smoothing float32) func(uint64) float64 {
+	return func(occ uint64) float64 {
+		// w = a / (a + p(w))
+		return params.Weight(occ, float64(smoothing))
+	}
+}
+
 func makeLogWeigher(min, max uint64) func(uint64) float64 {
 	return func(occ uint64) float64 {
 		// Note the 1.05 that's 1 + minimal weight of 0.05. This way, the most common
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"testing"
@@ -12,8 +13,11 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	contextionary "github.com/weaviate/contextionary/contextionary/core"
+	"github.com/weaviate/contextionary/contextionary/core/fuzzy"
+	errortypes "github.com/weaviate/contextionary/errors"
 	"github.com/weaviate/contextionary/extensions"
 	"github.com/weaviate/contextionary/server/config"
+	"github.com/weaviate/contextionary/tokenizer"
 )
 
 func Test_CorpusVectorizing_WithLogWeighting(t *testing.T) {
@@ -29,10 +33,10 @@ func Test_CorpusVectorizing_WithLogWeighting(t *testing.T) {
 		logger := logrus.New()
 		logger.SetLevel(logrus.DebugLevel)
 		compoundSplitter := compoundsplitting.NewEmptyTestSplitter()
-		v, err := NewVectorizer(c11y, swd, config, logger, split, extensions, compoundSplitter)
+		v, err := NewVectorizer(c11y, swd, config, logger, split, extensions, compoundSplitter, &fakeNormalizer{}, nil, nil, nil)
 		require.Nil(t, err)
 
-		vector, err := v.Corpi([]string{"car is mercedes"}, nil)
+		vector, err := v.Corpi(context.Background(), []string{"car is mercedes"}, nil)
 		require.Nil(t, err)
 		assert.Equal(t, []float32{1, 0.15748154, 0, 3.685037}, vector.ToArray())
 	})
@@ -49,7 +53,7 @@ func Test_CorpusVectorizing_WithLogWeighting(t *testing.T) {
 		logger := logrus.New()
 		logger.SetLevel(logrus.DebugLevel)
 		compoundSplitter := compoundsplitting.NewEmptyTestSplitter()
-		v, err := NewVectorizer(c11y, swd, config, logger, split, extensions, compoundSplitter)
+		v, err := NewVectorizer(c11y, swd, config, logger, split, extensions, compoundSplitter, &fakeNormalizer{}, nil, nil, nil)
 		require.Nil(t, err)
 
 		overrides := map[string]string{
@@ -57,7 +61,7 @@ func Test_CorpusVectorizing_WithLogWeighting(t *testing.T) {
 			"mercedes": "3",
 		}
 
-		vector, err := v.Corpi([]string{"car is mercedes"}, overrides)
+		vector, err := v.Corpi(context.Background(), []string{"car is mercedes"}, overrides)
 		require.Nil(t, err)
 		assert.Equal(t, []float32{1, 0.5, 0, 3}, vector.ToArray())
 	})
@@ -74,10 +78,10 @@ func Test_CorpusVectorizing_WithLogWeighting(t *testing.T) {
 		logger := logrus.New()
 		logger.SetLevel(logrus.DebugLevel)
 		compoundSplitter := compoundsplitting.NewEmptyTestSplitter()
-		v, err := NewVectorizer(c11y, swd, config, logger, split, extensions, compoundSplitter)
+		v, err := NewVectorizer(c11y, swd, config, logger, split, extensions, compoundSplitter, &fakeNormalizer{}, nil, nil, nil)
 		require.Nil(t, err)
 
-		vector, err := v.Corpi([]string{"mercedes"}, nil)
+		vector, err := v.Corpi(context.Background(), []string{"mercedes"}, nil)
 		require.Nil(t, err)
 		assert.Equal(t, []float32{1, 0, 0, 4}, vector.ToArray())
 	})
@@ -96,10 +100,10 @@ func Test_CorpusVectorizing_WithLinearWeighting(t *testing.T) {
 		extensions := &fakeExtensionLookerUpper{}
 		logger, _ := test.NewNullLogger()
 		compoundSplitter := compoundsplitting.NewEmptyTestSplitter()
-		v, err := NewVectorizer(c11y, swd, config, logger, split, extensions, compoundSplitter)
+		v, err := NewVectorizer(c11y, swd, config, logger, split, extensions, compoundSplitter, &fakeNormalizer{}, nil, nil, nil)
 		require.Nil(t, err)
 
-		vector, err := v.Corpi([]string{"car is mercedes"}, nil)
+		vector, err := v.Corpi(context.Background(), []string{"car is mercedes"}, nil)
 		require.Nil(t, err)
 		assert.Equal(t, []float32{1, 1, 0, 2}, vector.ToArray())
 	})
@@ -116,10 +120,10 @@ func Test_CorpusVectorizing_WithLinearWeighting(t *testing.T) {
 		logger, _ := test.NewNullLogger()
 		extensions := &fakeExtensionLookerUpper{}
 		compoundSplitter := compoundsplitting.NewEmptyTestSplitter()
-		v, err := NewVectorizer(c11y, swd, config, logger, split, extensions, compoundSplitter)
+		v, err := NewVectorizer(c11y, swd, config, logger, split, extensions, compoundSplitter, &fakeNormalizer{}, nil, nil, nil)
 		require.Nil(t, err)
 
-		vector, err := v.Corpi([]string{"car is mercedes"}, nil)
+		vector, err := v.Corpi(context.Background(), []string{"car is mercedes"}, nil)
 		require.Nil(t, err)
 		assert.Equal(t, []float32{1, 0, 0, 4}, vector.ToArray())
 	})
@@ -137,15 +141,110 @@ func Test_CorpusVectorizing_WithLinearWeighting(t *testing.T) {
 		logger.SetLevel(logrus.DebugLevel)
 		extensions := &fakeExtensionLookerUpper{}
 		compoundSplitter := compoundsplitting.NewEmptyTestSplitter()
-		v, err := NewVectorizer(c11y, swd, config, logger, split, extensions, compoundSplitter)
+		v, err := NewVectorizer(c11y, swd, config, logger, split, extensions, compoundSplitter, &fakeNormalizer{}, nil, nil, nil)
 		require.Nil(t, err)
 
-		vector, err := v.Corpi([]string{"car is mercedes"}, nil)
+		vector, err := v.Corpi(context.Background(), []string{"car is mercedes"}, nil)
 		require.Nil(t, err)
 		assert.Equal(t, []float32{1, 0.6666667, 0, 2.6666667}, vector.ToArray())
 	})
 }
 
+func Test_CorpusVectorizing_WithSIFWeighting(t *testing.T) {
+	// p(car) = 0.2, p(mercedes) = 0.001
+	sif := contextionary.SIFParameters{TotalOccurrence: 100000}
+	newVectorizer := func(c11y *fakeC11y, removePC bool) (*Vectorizer, error) {
+		config := &config.Config{
+			OccurrenceWeightStrategy: OccurrenceStrategySIF,
+			SIFSmoothing:             0.001,
+			RemovePrincipalComponent: removePC,
+			MaxCompoundWordLength:    1,
+		}
+		logger, _ := test.NewNullLogger()
+		return NewVectorizer(c11y, &fakeStopwordDetector{}, config, logger, &primitiveSplitter{},
+			&fakeExtensionLookerUpper{}, compoundsplitting.NewEmptyTestSplitter(), &fakeNormalizer{}, nil, nil, nil)
+	}
+
+	t.Run("frequent words are weighted close to 0", func(t *testing.T) {
+		v, err := newVectorizer(&fakeC11y{sif: sif}, false)
+		require.Nil(t, err)
+
+		vector, err := v.Corpi(context.Background(), []string{"car is mercedes"}, nil)
+		require.Nil(t, err)
+		// w(car) = 1/201, w(mercedes) = 1/2
+		assert.InDeltaSlice(t, []float32{1, 4.0 / 203, 0, 804.0 / 203}, vector.ToArray(), 0.00001)
+	})
+
+	t.Run("removing the principal component", func(t *testing.T) {
+		withPC := sif
+		withPC.PrincipalComponent = []float32{0, 0, 0, 1}
+		v, err := newVectorizer(&fakeC11y{sif: withPC}, true)
+		require.Nil(t, err)
+
+		vector, err := v.Corpi(context.Background(), []string{"car is mercedes"}, nil)
+		require.Nil(t, err)
+		assert.InDeltaSlice(t, []float32{1, 4.0 / 203, 0, 0}, vector.ToArray(), 0.00001)
+		assert.Len(t, vector.Source, 2)
+	})
+
+	t.Run("removing the principal component without one in the contextionary", func(t *testing.T) {
+		_, err := newVectorizer(&fakeC11y{sif: sif}, true)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("removing the principal component with another strategy", func(t *testing.T) {
+		config := &config.Config{
+			OccurrenceWeightStrategy: OccurrenceStrategyLog,
+			RemovePrincipalComponent: true,
+			MaxCompoundWordLength:    1,
+		}
+		logger, _ := test.NewNullLogger()
+		withPC := sif
+		withPC.PrincipalComponent = []float32{0, 0, 0, 1}
+		_, err := NewVectorizer(&fakeC11y{sif: withPC}, &fakeStopwordDetector{}, config, logger, &primitiveSplitter{},
+			&fakeExtensionLookerUpper{}, compoundsplitting.NewEmptyTestSplitter(), &fakeNormalizer{}, nil, nil, nil)
+		assert.NotNil(t, err)
+	})
+}
+
+func Test_CorpusVectorizing_WithCorpusWeights(t *testing.T) {
+	config := &config.Config{
+		OccurrenceWeightLinearFactor: 0.5,
+		OccurrenceWeightStrategy:     OccurrenceStrategyLinear,
+		MaxCompoundWordLength:        1,
+	}
+	logger, _ := test.NewNullLogger()
+	v, err := NewVectorizer(&fakeC11y{}, &fakeStopwordDetector{}, config, logger, &primitiveSplitter{},
+		&fakeExtensionLookerUpper{}, compoundsplitting.NewEmptyTestSplitter(), &fakeNormalizer{}, nil, nil, nil)
+	require.Nil(t, err)
+
+	t.Run("the first corpus weighted higher", func(t *testing.T) {
+		vector, err := v.CorpiWithOptions(context.Background(), []string{"car", "mercedes"}, nil, CorpiOptions{Weights: []float32{3, 1}})
+		require.Nil(t, err)
+		assert.Equal(t, []float32{1, 1.5, 0, 1}, vector.ToArray())
+
+		require.Len(t, vector.Source, 2)
+		assert.Equal(t, "car", vector.Source[0].Concept)
+		assert.Equal(t, 3.0, vector.Source[0].Weight)
+		assert.Equal(t, "mercedes", vector.Source[1].Concept)
+		assert.Equal(t, 1.0, vector.Source[1].Weight)
+	})
+
+	t.Run("a corpus with a weight of 0", func(t *testing.T) {
+		vector, err := v.CorpiWithOptions(context.Background(), []string{"car", "mercedes"}, nil, CorpiOptions{Weights: []float32{0, 1}})
+		require.Nil(t, err)
+		assert.Equal(t, mercedesVector, vector.ToArray())
+		assert.Len(t, vector.Source, 1)
+	})
+
+	t.Run("invalid weights", func(t *testing.T) {
+		for _, weights := range [][]float32{{1}, {1, -1}, {0, 0}} {
+			_, err := v.CorpiWithOptions(context.Background(), []string{"car", "mercedes"}, nil, CorpiOptions{Weights: weights})
+			assert.IsType(t, errortypes.InvalidUserInput{}, err, "weights %v", weights)
+		}
+	})
+}
+
 func Test_CorpusVectorizing_WithCompoundWords(t *testing.T) {
 	// these tests use weight factor 0, this makes the vector position
 	// calculation a bit easier to understand, weighting itself is already
@@ -164,10 +263,10 @@ func Test_CorpusVectorizing_WithCompoundWords(t *testing.T) {
 		logger.SetLevel(logrus.DebugLevel)
 		extensions := &fakeExtensionLookerUpper{}
 		compoundSplitter := compoundsplitting.NewEmptyTestSplitter()
-		v, err := NewVectorizer(c11y, swd, config, logger, split, extensions, compoundSplitter)
+		v, err := NewVectorizer(c11y, swd, config, logger, split, extensions, compoundSplitter, &fakeNormalizer{}, nil, nil, nil)
 		require.Nil(t, err)
 
-		vector, err := v.Corpi([]string{"the mercedes is a fast car"}, nil)
+		vector, err := v.Corpi(context.Background(), []string{"the mercedes is a fast car"}, nil)
 		require.Nil(t, err)
 		assert.Equal(t, equalWeight(fastCarVector, mercedesVector), vector.ToArray(),
 			"vector position is the centroid of 'mercedes' and 'fast_car'")
@@ -185,10 +284,10 @@ func Test_CorpusVectorizing_WithCompoundWords(t *testing.T) {
 		logger, _ := test.NewNullLogger()
 		extensions := &fakeExtensionLookerUpper{}
 		compoundSplitter := compoundsplitting.NewEmptyTestSplitter()
-		v, err := NewVectorizer(c11y, swd, config, logger, split, extensions, compoundSplitter)
+		v, err := NewVectorizer(c11y, swd, config, logger, split, extensions, compoundSplitter, &fakeNormalizer{}, nil, nil, nil)
 		require.Nil(t, err)
 
-		vector, err := v.Corpi([]string{"the mercedes is like a formula 1 racing car"}, nil)
+		vector, err := v.Corpi(context.Background(), []string{"the mercedes is like a formula 1 racing car"}, nil)
 		require.Nil(t, err)
 		assert.Equal(t, equalWeight(mercedesVector, formula1RacingCarVector), vector.ToArray(),
 			"vector position is the centroid of 'mercedes' and 'formula_1_racing_car'")
@@ -206,10 +305,57 @@ func Test_CorpusVectorizing_WithCompoundWords(t *testing.T) {
 		logger, _ := test.NewNullLogger()
 		extensions := &fakeExtensionLookerUpper{}
 		compoundSplitter := compoundsplitting.NewEmptyTestSplitter()
-		v, err := NewVectorizer(c11y, swd, config, logger, split, extensions, compoundSplitter)
+		v, err := NewVectorizer(c11y, swd, config, logger, split, extensions, compoundSplitter, &fakeNormalizer{}, nil, nil, nil)
 		require.Nil(t, err)
 
-		vector, err := v.Corpi([]string{"fast car mercedes"}, nil)
+		vector, err := v.Corpi(context.Background(), []string{"fast car mercedes"}, nil)
+		require.Nil(t, err)
+		assert.Equal(t, equalWeight(mercedesVector, fastCarVector), vector.ToArray(),
+			"vector position is the centroid of 'mercedes' and 'fast_car'")
+	})
+
+	t.Run("with words which need to be normalized", func(t *testing.T) {
+		c11y := &fakeC11y{}
+		swd := &fakeStopwordDetector{}
+		config := &config.Config{
+			OccurrenceWeightLinearFactor: 0,
+			OccurrenceWeightStrategy:     OccurrenceStrategyLinear,
+			MaxCompoundWordLength:        4,
+		}
+		split := &primitiveSplitter{}
+		logger, _ := test.NewNullLogger()
+		extensions := &fakeExtensionLookerUpper{}
+		compoundSplitter := compoundsplitting.NewEmptyTestSplitter()
+		v, err := NewVectorizer(c11y, swd, config, logger, split, extensions, compoundSplitter, &fakeNormalizer{}, nil, nil, nil)
+		require.Nil(t, err)
+
+		vector, err := v.Corpi(context.Background(), []string{"Fast Car MERCEDES"}, nil)
+		require.Nil(t, err)
+		assert.Equal(t, equalWeight(mercedesVector, fastCarVector), vector.ToArray(),
+			"vector position is the centroid of 'mercedes' and 'fast_car'")
+
+		word, err := v.VectorForWord(context.Background(), "Mercedes")
+		require.Nil(t, err)
+		require.NotNil(t, word)
+		assert.Equal(t, mercedesVector, word.vector.ToArray())
+	})
+
+	t.Run("with the tokenizer of the request", func(t *testing.T) {
+		c11y := &fakeC11y{}
+		swd := &fakeStopwordDetector{}
+		config := &config.Config{
+			OccurrenceWeightLinearFactor: 0,
+			OccurrenceWeightStrategy:     OccurrenceStrategyLinear,
+			MaxCompoundWordLength:        4,
+		}
+		split := &primitiveSplitter{}
+		logger, _ := test.NewNullLogger()
+		extensions := &fakeExtensionLookerUpper{}
+		compoundSplitter := compoundsplitting.NewEmptyTestSplitter()
+		v, err := NewVectorizer(c11y, swd, config, logger, split, extensions, compoundSplitter, &fakeNormalizer{}, nil, nil, nil)
+		require.Nil(t, err)
+
+		vector, err := v.CorpiWithOptions(context.Background(), []string{"mercedes, fast car!"}, nil, CorpiOptions{Tokenizer: tokenizer.NewWhitespace()})
 		require.Nil(t, err)
 		assert.Equal(t, equalWeight(mercedesVector, fastCarVector), vector.ToArray(),
 			"vector position is the centroid of 'mercedes' and 'fast_car'")
@@ -233,10 +379,10 @@ func Test_CorpusVectorizing_WithCustomWords(t *testing.T) {
 		logger, _ := test.NewNullLogger()
 		extensions := &fakeExtensionLookerUpper{}
 		compoundSplitter := compoundsplitting.NewEmptyTestSplitter()
-		v, err := NewVectorizer(c11y, swd, config, logger, split, extensions, compoundSplitter)
+		v, err := NewVectorizer(c11y, swd, config, logger, split, extensions, compoundSplitter, &fakeNormalizer{}, nil, nil, nil)
 		require.Nil(t, err)
 
-		vector, err := v.Corpi([]string{"the mercedes is a zebra"}, nil)
+		vector, err := v.Corpi(context.Background(), []string{"the mercedes is a zebra"}, nil)
 		require.Nil(t, err)
 		assert.Equal(t, []float32{0.5, 2, 0, 2}, vector.ToArray(),
 			"vector position is the centroid of 'mercedes' and custom word 'zebra'")
@@ -254,10 +400,10 @@ func Test_CorpusVectorizing_WithCustomWords(t *testing.T) {
 		logger, _ := test.NewNullLogger()
 		extensions := &fakeExtensionLookerUpper{}
 		compoundSplitter := compoundsplitting.NewEmptyTestSplitter()
-		v, err := NewVectorizer(c11y, swd, config, logger, split, extensions, compoundSplitter)
+		v, err := NewVectorizer(c11y, swd, config, logger, split, extensions, compoundSplitter, &fakeNormalizer{}, nil, nil, nil)
 		require.Nil(t, err)
 
-		vector, err := v.Corpi([]string{"the mercedes is a zebra carrier"}, nil)
+		vector, err := v.Corpi(context.Background(), []string{"the mercedes is a zebra carrier"}, nil)
 		require.Nil(t, err)
 		assert.Equal(t, []float32{0.5, -2, 0, 2}, vector.ToArray(),
 			"vector position is the centroid of 'mercedes' and custom word 'zebra carrier'")
@@ -281,9 +427,9 @@ func Test_CorpusVectorizing_UnknownCompoundWords(t *testing.T) {
 		logger := logrus.New()
 		logger.SetLevel(logrus.DebugLevel)
 		compoundSplitter := compoundsplitting.NewEmptyTestSplitter()
-		v, err := NewVectorizer(c11y, swd, config, logger, split, extensions, compoundSplitter)
+		v, err := NewVectorizer(c11y, swd, config, logger, split, extensions, compoundSplitter, &fakeNormalizer{}, nil, nil, nil)
 		require.Nil(t, err)
-		_, err = v.Corpi([]string{"steammachine"}, nil)
+		_, err = v.Corpi(context.Background(), []string{"steammachine"}, nil)
 		require.NotNil(t, err)
 
 	})
@@ -303,9 +449,9 @@ func Test_CorpusVectorizing_UnknownCompoundWords(t *testing.T) {
 			"steam":   1.0,
 			"machine": 1.0,
 		})
-		v, err := NewVectorizer(c11y, swd, config, logger, split, extensions, compoundSplitter)
+		v, err := NewVectorizer(c11y, swd, config, logger, split, extensions, compoundSplitter, &fakeNormalizer{}, nil, nil, nil)
 		require.Nil(t, err)
-		vec, err := v.Corpi([]string{"steammachine"}, nil)
+		vec, err := v.Corpi(context.Background(), []string{"steammachine"}, nil)
 		require.Nil(t, err)
 		require.NotNil(t, vec)
 		assert.Equal(t, []float32{1, 0.5, 0, 0}, vec.ToArray())
@@ -328,14 +474,219 @@ func Test_CorpusVectorizing_UnknownCompoundWords(t *testing.T) {
 			"roller": 1.0,
 			"blade":  1.0,
 		})
-		v, err := NewVectorizer(c11y, swd, config, logger, split, extensions, compoundSplitter)
+		v, err := NewVectorizer(c11y, swd, config, logger, split, extensions, compoundSplitter, &fakeNormalizer{}, nil, nil, nil)
 		require.Nil(t, err)
-		_, err = v.Corpi([]string{"rollerblade"}, nil)
+		_, err = v.Corpi(context.Background(), []string{"rollerblade"}, nil)
 		require.NotNil(t, err)
 	})
 }
 
-type fakeC11y struct{}
+func Test_CorpusVectorizing_Cache(t *testing.T) {
+	config := &config.Config{
+		OccurrenceWeightLinearFactor: 0.5,
+		OccurrenceWeightStrategy:     OccurrenceStrategyLinear,
+		MaxCompoundWordLength:        1,
+		MaximumVectorCacheSize:       10,
+	}
+	extensions := &changingExtensions{generation: 1}
+	logger, _ := test.NewNullLogger()
+	v, err := NewVectorizer(&fakeC11y{}, &fakeStopwordDetector{}, config, logger, &primitiveSplitter{},
+		extensions, compoundsplitting.NewEmptyTestSplitter(), &fakeNormalizer{}, nil, nil, nil)
+	require.Nil(t, err)
+
+	vectorOf := func(t *testing.T, word string) []float32 {
+		vector, err := v.VectorForWord(context.Background(), word)
+		require.Nil(t, err)
+		if vector == nil {
+			return nil
+		}
+		return vector.vector.ToArray()
+	}
+
+	t.Run("words are looked up once", func(t *testing.T) {
+		assert.Equal(t, mercedesVector, vectorOf(t, "mercedes"))
+		assert.Equal(t, mercedesVector, vectorOf(t, "mercedes"))
+		assert.Equal(t, uint64(1), v.CacheStats().Misses)
+		assert.Equal(t, uint64(1), v.CacheStats().Hits)
+	})
+
+	t.Run("words which are not in the contextionary are cached too", func(t *testing.T) {
+		assert.Nil(t, vectorOf(t, "rollerblade"))
+		assert.Nil(t, vectorOf(t, "rollerblade"))
+		assert.Equal(t, uint64(2), v.CacheStats().Hits)
+	})
+
+	t.Run("extensions are cached until they change", func(t *testing.T) {
+		assert.Equal(t, []float32{0, 1, 0, 0}, vectorOf(t, "zebra"))
+		assert.Equal(t, []float32{0, 1, 0, 0}, vectorOf(t, "zebra"))
+
+		extensions.generation = 2
+		assert.Equal(t, []float32{0, 2, 0, 0}, vectorOf(t, "zebra"))
+	})
+}
+
+func Test_CorpusVectorizing_SubwordVectors(t *testing.T) {
+	// these tests use weight factor 0, this makes the vector position
+	// calculation a bit easier to understand, weighting itself is already
+	// tested separately
+
+	c11y := &fakeC11y{}
+	swd := &fakeStopwordDetector{}
+	config := &config.Config{
+		OccurrenceWeightLinearFactor: 0,
+		OccurrenceWeightStrategy:     OccurrenceStrategyLinear,
+		MaxCompoundWordLength:        4,
+	}
+	split := &primitiveSplitter{}
+	logger, _ := test.NewNullLogger()
+	extensions := &fakeExtensionLookerUpper{}
+	compoundSplitter := compoundsplitting.NewEmptyTestSplitter()
+	v, err := NewVectorizer(c11y, swd, config, logger, split, extensions, compoundSplitter,
+		&fakeNormalizer{}, &fakeSubwords{}, nil, nil)
+	require.Nil(t, err)
+
+	t.Run("with a single unknown word", func(t *testing.T) {
+		vector, err := v.Corpi(context.Background(), []string{"mercedez"}, nil)
+		require.Nil(t, err)
+		assert.Equal(t, mercedesVector, vector.ToArray())
+		require.Len(t, vector.Source, 1)
+		assert.True(t, vector.Source[0].Synthesized)
+	})
+
+	t.Run("with known, unknown and stop words", func(t *testing.T) {
+		vector, err := v.Corpi(context.Background(), []string{"the fast car is a mercedez"}, nil)
+		require.Nil(t, err)
+		assert.Equal(t, equalWeight(mercedesVector, fastCarVector), vector.ToArray(),
+			"vector position is the centroid of 'fast_car' and the synthesized 'mercedez'")
+
+		require.Len(t, vector.Source, 2)
+		assert.Equal(t, "fast_car", vector.Source[0].Concept)
+		assert.False(t, vector.Source[0].Synthesized)
+		assert.Equal(t, "mercedez", vector.Source[1].Concept)
+		assert.True(t, vector.Source[1].Synthesized)
+	})
+
+	t.Run("looking up a single unknown word", func(t *testing.T) {
+		word, err := v.VectorForWord(context.Background(), "Mercedez")
+		require.Nil(t, err)
+		require.NotNil(t, word)
+		assert.Equal(t, mercedesVector, word.vector.ToArray())
+		assert.True(t, word.source[0].Synthesized)
+	})
+}
+
+func Test_CorpusVectorizing_Suggestions(t *testing.T) {
+	// these tests use weight factor 0, this makes the vector position
+	// calculation a bit easier to understand, weighting itself is already
+	// tested separately
+
+	c11y := &fakeC11y{}
+	swd := &fakeStopwordDetector{}
+	config := &config.Config{
+		OccurrenceWeightLinearFactor: 0,
+		OccurrenceWeightStrategy:     OccurrenceStrategyLinear,
+		MaxCompoundWordLength:        4,
+	}
+	split := &primitiveSplitter{}
+	logger, _ := test.NewNullLogger()
+	extensions := &fakeExtensionLookerUpper{}
+	compoundSplitter := compoundsplitting.NewEmptyTestSplitter()
+	suggestions := &fakeFuzzyIndex{}
+	v, err := NewVectorizer(c11y, swd, config, logger, split, extensions, compoundSplitter,
+		&fakeNormalizer{}, nil, suggestions, nil)
+	require.Nil(t, err)
+
+	t.Run("suggestions are sorted by distance and occurrence", func(t *testing.T) {
+		suggestions, err := v.Suggest("mercedez", 2, 2)
+		require.Nil(t, err)
+		assert.Equal(t, []wordSuggestion{
+			{word: "mercedes", distance: 1, occurrence: 100},
+			{word: "car", distance: 2, occurrence: 20000},
+		}, suggestions)
+	})
+
+	t.Run("an unknown word without suggestions", func(t *testing.T) {
+		vector, err := v.Corpi(context.Background(), []string{"fast car mercedez"}, nil)
+		require.Nil(t, err)
+		assert.Equal(t, fastCarVector, vector.ToArray())
+	})
+
+	t.Run("an unknown word with suggestions", func(t *testing.T) {
+		vector, err := v.CorpiWithOptions(context.Background(), []string{"fast car mercedez"}, nil, CorpiOptions{UseSuggestions: true})
+		require.Nil(t, err)
+		assert.Equal(t, equalWeight(mercedesVector, fastCarVector), vector.ToArray(),
+			"vector position is the centroid of 'fast_car' and 'mercedes'")
+
+		require.Len(t, vector.Source, 2)
+		assert.Equal(t, "mercedez (mercedes)", vector.Source[1].Concept)
+	})
+
+	t.Run("the edits are capped by the length of the word", func(t *testing.T) {
+		for word, expected := range map[string]int{
+			"ab":          1,
+			"mrc":         1,
+			"mercedez":    2,
+			"mercedezz":   3,
+			"größe":       1,
+			"straßenbahn": 3,
+		} {
+			_, err := v.Suggest(word, 3, 10)
+			require.Nil(t, err)
+			assert.Equal(t, expected, suggestions.maxEdits, word)
+		}
+
+		_, err := v.Suggest("mercedezz", 2, 10)
+		require.Nil(t, err)
+		assert.Equal(t, 2, suggestions.maxEdits, "fewer edits than the cap")
+	})
+
+	t.Run("a word which is too short to be corrected", func(t *testing.T) {
+		_, err := v.CorpiWithOptions(context.Background(), []string{"mrc"}, nil, CorpiOptions{UseSuggestions: true})
+		assert.Equal(t, ErrNoUsableWords, err)
+	})
+}
+
+func TestBuildingTheSuggestionIndexOnFirstUse(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+
+	t.Run("the index is built once", func(t *testing.T) {
+		builds := 0
+		index := &lazySuggestionIndex{
+			build: func() (*fuzzy.Index, error) {
+				builds++
+				return fuzzy.NewIndex([]string{"car", "mercedes"}), nil
+			},
+			logger: logger,
+		}
+		assert.Equal(t, 0, builds)
+
+		matches := index.Search("mercedez", 1)
+		require.Len(t, matches, 1)
+		assert.Equal(t, "mercedes", matches[0].Word)
+
+		index.Search("cat", 1)
+		assert.Equal(t, 1, builds)
+	})
+
+	t.Run("an index which can't be built", func(t *testing.T) {
+		builds := 0
+		index := &lazySuggestionIndex{
+			build: func() (*fuzzy.Index, error) {
+				builds++
+				return nil, fmt.Errorf("no words")
+			},
+			logger: logger,
+		}
+
+		assert.Len(t, index.Search("mercedez", 1), 0)
+		assert.Len(t, index.Search("mercedez", 1), 0)
+		assert.Equal(t, 1, builds)
+	})
+}
+
+type fakeC11y struct {
+	sif contextionary.SIFParameters
+}
 
 func (f *fakeC11y) GetNumberOfItems() int {
 	panic("not implemented")
@@ -345,10 +696,22 @@ func (f *fakeC11y) GetVectorLength() int {
 	panic("not implemented")
 }
 
+func (f *fakeC11y) Metric() contextionary.Metric {
+	return contextionary.MetricEuclidean
+}
+
+func (f *fakeC11y) CertaintyCalibration() contextionary.CertaintyCalibration {
+	return contextionary.CertaintyCalibration{MaxDistance: 12}
+}
+
 func (f *fakeC11y) OccurrencePercentile(foo int) uint64 {
 	panic("not implemented")
 }
 
+func (f *fakeC11y) SIFParameters() contextionary.SIFParameters {
+	return f.sif
+}
+
 const (
 	notInContextionary     = -1
 	machineIndex           = 10
@@ -387,7 +750,7 @@ func (f *fakeC11y) WordToItemIndex(word string) contextionary.ItemIndex {
 		return steamIndex
 	case "machine":
 		return machineIndex
-	case "steammachine", "rollerblade", "roller", "blade":
+	case "steammachine", "rollerblade", "roller", "blade", "mercedez", "mrc":
 		return notInContextionary
 	default:
 		panic(fmt.Sprintf("no behavior for word '%s' in fake", word))
@@ -454,6 +817,10 @@ func (f *fakeC11y) GetNnsByVector(vector contextionary.Vector, n int, k int) ([]
 	panic("not implemented")
 }
 
+func (f *fakeC11y) GetNnsByVectorContext(ctx context.Context, vector contextionary.Vector, n int, k int) ([]contextionary.ItemIndex, []float32, error) {
+	panic("not implemented")
+}
+
 func (f *fakeC11y) SafeGetSimilarWords(word string, n int, k int) ([]string, []float32) {
 	panic("not implemented")
 }
@@ -470,13 +837,48 @@ func (f *fakeStopwordDetector) IsStopWord(word string) bool {
 
 type primitiveSplitter struct{}
 
-func (s *primitiveSplitter) Split(corpus string) []string {
+func (s *primitiveSplitter) Tokenize(corpus string) []string {
 	return strings.Split(corpus, " ")
 }
 
+func (s *primitiveSplitter) Compound(words []string) (string, bool) {
+	return strings.Join(words, "_"), true
+}
+
+type fakeNormalizer struct{}
+
+func (f *fakeNormalizer) Normalize(input string) string {
+	return strings.ToLower(input)
+}
+
+// fakeSubwords synthesizes the same vector for every word
+type fakeSubwords struct{}
+
+func (f *fakeSubwords) Vector(word string) []float32 {
+	return mercedesVector
+}
+
+// fakeFuzzyIndex remembers the maxEdits of the last search
+type fakeFuzzyIndex struct {
+	maxEdits int
+}
+
+func (f *fakeFuzzyIndex) Search(word string, maxEdits int) []fuzzy.Match {
+	f.maxEdits = maxEdits
+	if word != "mercedez" {
+		return nil
+	}
+
+	return []fuzzy.Match{
+		{ID: steamIndex, Word: "steam", Distance: 2},
+		{ID: mercedesIndex, Word: "mercedes", Distance: 1},
+		{ID: 5, Word: "car", Distance: 2},
+	}
+}
+
 type fakeExtensionLookerUpper struct{}
 
-func (f *fakeExtensionLookerUpper) Lookup(word string) (*extensions.Extension, error) {
+func (f *fakeExtensionLookerUpper) Lookup(ctx context.Context, word string) (*extensions.Extension, error) {
 	switch word {
 	case "zebra":
 		return &extensions.Extension{
@@ -495,6 +897,32 @@ func (f *fakeExtensionLookerUpper) Lookup(word string) (*extensions.Extension, e
 	}
 }
 
+func (f *fakeExtensionLookerUpper) Generation() uint64 {
+	return 0
+}
+
+// changingExtensions has a single extension, which changes with every
+// generation
+type changingExtensions struct {
+	generation uint64
+}
+
+func (f *changingExtensions) Lookup(ctx context.Context, word string) (*extensions.Extension, error) {
+	if word != "zebra" {
+		return nil, nil
+	}
+
+	return &extensions.Extension{
+		Concept:    "zebra",
+		Occurrence: 1000,
+		Vector:     []float32{0, float32(f.generation), 0, 0},
+	}, nil
+}
+
+func (f *changingExtensions) Generation() uint64 {
+	return f.generation
+}
+
 func equalWeight(vectors ...[]float32) []float32 {
 	// no sanity checks as this will only be used in tests, we'll notice panics
 	// then
@@ -1,7 +1,10 @@
 package main
 
 import (
-	"github.com/weaviate/contextionary/errors"
+	"context"
+	"errors"
+
+	errortypes "github.com/weaviate/contextionary/errors"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -11,14 +14,36 @@ func GrpcErrFromTyped(err error) error {
 		return nil
 	}
 
+	if _, ok := status.FromError(err); ok {
+		// already mapped
+		return err
+	}
+
+	if ctxErr := grpcErrFromContext(err); ctxErr != nil {
+		return ctxErr
+	}
+
 	switch err.(type) {
-	case errors.InvalidUserInput:
+	case errortypes.InvalidUserInput:
 		return status.Error(codes.InvalidArgument, err.Error())
-	case errors.Internal:
+	case errortypes.Internal:
 		return status.Error(codes.Internal, err.Error())
-	case errors.NotFound:
+	case errortypes.NotFound:
 		return status.Error(codes.NotFound, err.Error())
 	default:
 		return status.Error(codes.Unknown, err.Error())
 	}
 }
+
+// grpcErrFromContext maps the errors of requests which were cancelled or
+// whose deadline expired, it is nil for any other error
+func grpcErrFromContext(err error) error {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	pb "github.com/weaviate/contextionary/contextionary"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// serviceName of the contextionary in the health service
+const serviceName = "contextionary.Contextionary"
+
+// newGRPCServer serves the contextionary, the grpc.health.v1 service and
+// server reflection
+func (s *server) newGRPCServer() *grpc.Server {
+	grpcServer := grpc.NewServer(
+		grpc.UnaryInterceptor(s.unaryInterceptor),
+		grpc.StreamInterceptor(s.streamInterceptor),
+	)
+
+	pb.RegisterContextionaryServer(grpcServer, s)
+	healthpb.RegisterHealthServer(grpcServer, s.health)
+	reflection.Register(grpcServer)
+
+	return grpcServer
+}
+
+// newHealthServer reports NOT_SERVING until the server becomes ready
+func newHealthServer() *health.Server {
+	h := health.NewServer()
+	h.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	h.SetServingStatus(serviceName, healthpb.HealthCheckResponse_NOT_SERVING)
+	return h
+}
+
+// becomeReady once every language received its extensions for the first
+// time, init must have loaded the models, stopwords and compound splitting
+// dictionaries before
+func (s *server) becomeReady() {
+	for name, l := range s.languages {
+		synced, ok := l.extensionLookerUpper.(interface{ Synced() <-chan struct{} })
+		if !ok {
+			continue
+		}
+
+		s.logger.WithField("action", "startup").WithField("language", name).
+			Debug("waiting for the extensions")
+		select {
+		case <-synced.Synced():
+		case <-s.stop:
+			return
+		}
+	}
+
+	atomic.StoreInt32(&s.ready, 1)
+	s.health.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	s.health.SetServingStatus(serviceName, healthpb.HealthCheckResponse_SERVING)
+	s.logger.WithField("action", "startup").Info("ready to serve requests")
+}
+
+func (s *server) isReady() bool {
+	return atomic.LoadInt32(&s.ready) == 1
+}
+
+// checkReady rejects calls to the contextionary until the server is ready,
+// the health and reflection services are always available
+func (s *server) checkReady(method string) error {
+	if s.isReady() || !strings.HasPrefix(method, "/"+serviceName+"/") {
+		return nil
+	}
+
+	return status.Error(codes.Unavailable, "the contextionary is starting up")
+}
+
+func (s *server) unaryInterceptor(ctx context.Context, req interface{},
+	info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return s.metrics.unaryInterceptor(ctx, req, info,
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			if err := s.checkReady(info.FullMethod); err != nil {
+				return nil, err
+			}
+
+			return handler(ctx, req)
+		})
+}
+
+func (s *server) streamInterceptor(srv interface{}, stream grpc.ServerStream,
+	info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return s.metrics.streamInterceptor(srv, stream, info,
+		func(srv interface{}, stream grpc.ServerStream) error {
+			if err := s.checkReady(info.FullMethod); err != nil {
+				return err
+			}
+
+			return handler(srv, stream)
+		})
+}
+
+// shutdownOnSignal shuts down on SIGTERM or an interrupt
+func (s *server) shutdownOnSignal(grpcServer *grpc.Server) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, os.Interrupt)
+
+	sig := <-signals
+	s.logger.WithField("action", "shutdown").WithField("signal", sig.String()).
+		Info("shutting down, waiting for in-flight requests")
+	s.shutdown(grpcServer)
+}
+
+// shutdown reports NOT_SERVING, stops the watchers and waits up to
+// SHUTDOWN_TIMEOUT for the in-flight requests to finish, after which
+// grpcServer.Serve returns. New requests are refused right away, requests
+// which are still running after the timeout are cancelled.
+func (s *server) shutdown(grpcServer *grpc.Server) {
+	s.health.Shutdown()
+	close(s.stop)
+
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	timeout := time.NewTimer(s.config.ShutdownTimeout)
+	defer timeout.Stop()
+
+	select {
+	case <-stopped:
+	case <-timeout.C:
+		s.logger.WithField("action", "shutdown").
+			Warn("in-flight requests didn't finish in time, cancelling them")
+		grpcServer.Stop()
+		<-stopped
+	}
+}
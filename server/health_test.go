@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	pb "github.com/weaviate/contextionary/contextionary"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// unsyncedExtensions are synced once synced is closed
+type unsyncedExtensions struct {
+	fakeExtensionLookerUpper
+	synced chan struct{}
+}
+
+func (f *unsyncedExtensions) Synced() <-chan struct{} {
+	return f.synced
+}
+
+// testGRPCServer serves s over an in-memory connection
+type testGRPCServer struct {
+	grpcServer *grpc.Server
+	conn       *grpc.ClientConn
+	served     chan error
+}
+
+func serveTestServer(t *testing.T, s *server) *testGRPCServer {
+	s.metrics = newMetrics(s)
+	s.health = newHealthServer()
+	s.stop = make(chan struct{})
+
+	lis := bufconn.Listen(1 << 20)
+	ts := &testGRPCServer{grpcServer: s.newGRPCServer(), served: make(chan error, 1)}
+	go func() {
+		ts.served <- ts.grpcServer.Serve(lis)
+	}()
+
+	conn, err := grpc.Dial("bufconn", grpc.WithInsecure(),
+		grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			return lis.Dial()
+		}))
+	require.Nil(t, err)
+	ts.conn = conn
+
+	return ts
+}
+
+func TestHealthAndShutdown(t *testing.T) {
+	s := newTestServer(t, &fakeC11y{})
+	s.config.ShutdownTimeout = time.Minute
+	extensions := &unsyncedExtensions{synced: make(chan struct{})}
+	s.languages["en"].extensionLookerUpper = extensions
+
+	ts := serveTestServer(t, s)
+	grpcServer, conn, served := ts.grpcServer, ts.conn, ts.served
+	defer conn.Close()
+
+	client := pb.NewContextionaryClient(conn)
+	healthClient := healthpb.NewHealthClient(conn)
+	ctx := context.Background()
+
+	checkHealth := func(t *testing.T, expected healthpb.HealthCheckResponse_ServingStatus) {
+		for _, service := range []string{"", serviceName} {
+			res, err := healthClient.Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+			require.Nil(t, err)
+			assert.Equal(t, expected, res.Status, service)
+		}
+	}
+
+	ready := make(chan struct{})
+	go func() {
+		s.becomeReady()
+		close(ready)
+	}()
+
+	t.Run("before the extensions were received", func(t *testing.T) {
+		checkHealth(t, healthpb.HealthCheckResponse_NOT_SERVING)
+
+		_, err := client.VectorForWord(ctx, &pb.Word{Word: "car"})
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	})
+
+	close(extensions.synced)
+	<-ready
+
+	t.Run("once the server is ready", func(t *testing.T) {
+		checkHealth(t, healthpb.HealthCheckResponse_SERVING)
+
+		res, err := client.VectorForWord(ctx, &pb.Word{Word: "car"})
+		require.Nil(t, err)
+		assert.Len(t, res.Entries, 4)
+	})
+
+	t.Run("listing the services through reflection", func(t *testing.T) {
+		stream, err := rpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+		require.Nil(t, err)
+		err = stream.Send(&rpb.ServerReflectionRequest{
+			MessageRequest: &rpb.ServerReflectionRequest_ListServices{},
+		})
+		require.Nil(t, err)
+		res, err := stream.Recv()
+		require.Nil(t, err)
+		require.Nil(t, stream.CloseSend())
+
+		var services []string
+		for _, service := range res.GetListServicesResponse().Service {
+			services = append(services, service.Name)
+		}
+		assert.Contains(t, services, serviceName)
+		assert.Contains(t, services, "grpc.health.v1.Health")
+	})
+
+	t.Run("shutting down drains the open streams", func(t *testing.T) {
+		stream, err := client.VectorizeStream(ctx)
+		require.Nil(t, err)
+		require.Nil(t, stream.Send(&pb.VectorizeRequest{Id: "1", Corpi: &pb.Corpi{Corpi: []string{"car"}}}))
+		res, err := stream.Recv()
+		require.Nil(t, err)
+		assert.Equal(t, "1", res.Id)
+
+		stopped := make(chan struct{})
+		go func() {
+			s.shutdown(grpcServer)
+			close(stopped)
+		}()
+
+		select {
+		case <-stopped:
+			t.Fatal("server stopped while a stream was open")
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		health, err := s.health.Check(ctx, &healthpb.HealthCheckRequest{Service: serviceName})
+		require.Nil(t, err)
+		assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, health.Status)
+
+		require.Nil(t, stream.CloseSend())
+		_, err = stream.Recv()
+		assert.Equal(t, io.EOF, err)
+
+		<-stopped
+		assert.Nil(t, <-served)
+	})
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	s := newTestServer(t, &fakeC11y{})
+	s.config.ShutdownTimeout = 50 * time.Millisecond
+	s.ready = 1
+
+	ts := serveTestServer(t, s)
+	defer ts.conn.Close()
+
+	// the client never closes the stream
+	stream, err := pb.NewContextionaryClient(ts.conn).VectorizeStream(context.Background())
+	require.Nil(t, err)
+	require.Nil(t, stream.Send(&pb.VectorizeRequest{Id: "1", Corpi: &pb.Corpi{Corpi: []string{"car"}}}))
+	_, err = stream.Recv()
+	require.Nil(t, err)
+
+	stopped := make(chan struct{})
+	go func() {
+		s.shutdown(ts.grpcServer)
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("shutdown didn't cancel the open stream after the timeout")
+	}
+
+	_, err = stream.Recv()
+	assert.NotNil(t, err)
+	assert.Nil(t, <-ts.served)
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/weaviate/contextionary/extensions"
+	"github.com/weaviate/contextionary/server/config"
+	"github.com/weaviate/contextionary/tokenizer"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// language bundles everything the server needs to serve requests in a
+// single language. Nothing is shared between languages apart from the
+// extensions repo, in which every language has its own namespace.
+type language struct {
+	config config.LanguageConfig
+
+	// the contextionary and everything derived from it, loaded at startup
+	// and replaced when the model files change
+	models modelHolder
+
+	// reload serializes replacing the model, which happens when the model
+	// files or the schema change
+	reload sync.Mutex
+
+	extensionStorer      *extensions.Storer
+	extensionLookerUpper extensionLookerUpper
+	stopwordDetector     stopwordDetector
+	compoundSplitter     compoundSplitter
+	tokenizer            tokenizer.Tokenizer
+	normalizer           normalizer
+}
+
+// language returns the language a request asked for, an empty name is the
+// configured default language
+func (s *server) language(name string) (*language, error) {
+	if name == "" {
+		name = s.config.DefaultLanguage
+	}
+
+	l, ok := s.languages[name]
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("language '%s' is not served, "+
+			"available languages are %s", name, s.languageNames()))
+	}
+
+	return l, nil
+}
+
+func (s *server) languageNames() []string {
+	names := make([]string, len(s.config.Languages))
+	for i, l := range s.config.Languages {
+		names[i] = l.Language
+	}
+
+	return names
+}
+
+// extensionsNamespace keeps the extensions of the ExtensionsLegacyLanguage
+// where they were stored before there were several languages
+func (s *server) extensionsNamespace(l *language) string {
+	if l.config.Language == s.config.ExtensionsLegacyLanguage {
+		return ""
+	}
+
+	return l.config.Language
+}
+
+// tokenizerFor returns the tokenizer a request asked for, an empty name is the
+// configured tokenizer of the language
+func (l *language) tokenizerFor(name string) (tokenizer.Tokenizer, error) {
+	if name == "" {
+		return l.tokenizer, nil
+	}
+
+	t, err := tokenizer.New(name)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return t, nil
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/weaviate/contextionary/compoundsplitting"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// how a single word of a corpus was vectorized, see vectorForSingleWord
+const (
+	outcomePresent     = "present"
+	outcomeStopword    = "stopword"
+	outcomeSuggestion  = "suggestion"
+	outcomeSynthesized = "synthesized"
+	outcomeUnknown     = "unknown"
+)
+
+// metrics of the server, which are served in the prometheus format on
+// METRICS_PORT
+type metrics struct {
+	registry *prometheus.Registry
+
+	requestDuration *prometheus.HistogramVec
+	requests        *prometheus.CounterVec
+	words           *prometheus.CounterVec
+}
+
+func newMetrics(s *server) *metrics {
+	m := &metrics{
+		registry: prometheus.NewRegistry(),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "contextionary_grpc_request_duration_seconds",
+			Help:    "Duration of the gRPC requests, streams last until they are closed",
+			Buckets: prometheus.ExponentialBuckets(0.0005, 2, 16),
+		}, []string{"method"}),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "contextionary_grpc_requests_total",
+			Help: "Finished gRPC requests by their status code",
+		}, []string{"method", "code"}),
+		words: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "contextionary_vectorized_words_total",
+			Help: "Single words of vectorized corpi by how they were vectorized: present in the " +
+				"contextionary or an extension, skipped as a stopword, replaced with a suggestion, " +
+				"synthesized from subwords or skipped as unknown",
+		}, []string{"language", "outcome"}),
+	}
+
+	m.registry.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		m.requestDuration,
+		m.requests,
+		m.words,
+		&languageCollector{s},
+	)
+
+	return m
+}
+
+// observeRequest records a finished request, nil metrics record nothing
+func (m *metrics) observeRequest(method string, start time.Time, err error) {
+	if m == nil {
+		return
+	}
+
+	m.requestDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+	m.requests.WithLabelValues(method, status.Code(err).String()).Inc()
+}
+
+func (m *metrics) unaryInterceptor(ctx context.Context, req interface{},
+	info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	res, err := handler(ctx, req)
+	m.observeRequest(info.FullMethod, start, err)
+	return res, err
+}
+
+func (m *metrics) streamInterceptor(srv interface{}, stream grpc.ServerStream,
+	info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, stream)
+	m.observeRequest(info.FullMethod, start, err)
+	return err
+}
+
+// wordCounter of the language, nil if there are no metrics
+func (m *metrics) wordCounter(language string) *wordCounter {
+	if m == nil {
+		return nil
+	}
+
+	return &wordCounter{m.words.MustCurryWith(prometheus.Labels{"language": language})}
+}
+
+// wordCounter counts how the words of a language were vectorized, a nil
+// counter counts nothing
+type wordCounter struct {
+	counter *prometheus.CounterVec
+}
+
+func (c *wordCounter) count(outcome string) {
+	if c == nil {
+		return
+	}
+
+	c.counter.WithLabelValues(outcome).Inc()
+}
+
+// serveMetrics on the metrics port until the server fails
+func (s *server) serveMetrics() {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(s.metrics.registry, promhttp.HandlerOpts{}))
+
+	err := http.ListenAndServe(fmt.Sprintf(":%d", s.config.MetricsPort), mux)
+	s.logger.WithField("action", "serve_metrics").WithError(err).
+		Error("metrics endpoint stopped")
+}
+
+type splitterStats interface {
+	Stats() compoundsplitting.Stats
+}
+
+type extensionStats interface {
+	Len() int
+	LastSync() time.Time
+}
+
+var (
+	cacheHitsDesc = prometheus.NewDesc("contextionary_vector_cache_hits_total",
+		"Lookups of the vector cache which were cached, reset when the model is reloaded",
+		[]string{"language"}, nil)
+	cacheMissesDesc = prometheus.NewDesc("contextionary_vector_cache_misses_total",
+		"Lookups of the vector cache which weren't cached, reset when the model is reloaded",
+		[]string{"language"}, nil)
+	cacheEvictionsDesc = prometheus.NewDesc("contextionary_vector_cache_evictions_total",
+		"Vectors evicted from the full cache, reset when the model is reloaded",
+		[]string{"language"}, nil)
+	cacheExpirationsDesc = prometheus.NewDesc("contextionary_vector_cache_expirations_total",
+		"Vectors which expired in the cache, reset when the model is reloaded",
+		[]string{"language"}, nil)
+	cacheSizeDesc = prometheus.NewDesc("contextionary_vector_cache_size",
+		"Vectors in the cache", []string{"language"}, nil)
+	cacheHitRatioDesc = prometheus.NewDesc("contextionary_vector_cache_hit_ratio",
+		"Hits of the vector cache per lookup since the model was loaded", []string{"language"}, nil)
+
+	splitAttemptsDesc = prometheus.NewDesc("contextionary_compound_split_attempts_total",
+		"Attempts to split a word into compounds", []string{"language"}, nil)
+	splitsDesc = prometheus.NewDesc("contextionary_compound_splits_total",
+		"Words which were split into compounds", []string{"language"}, nil)
+	splitTimeoutsDesc = prometheus.NewDesc("contextionary_compound_split_timeouts_total",
+		"Attempts to split a word into compounds which took too long", []string{"language"}, nil)
+
+	extensionsDesc = prometheus.NewDesc("contextionary_extensions",
+		"Extensions of the language", []string{"language"}, nil)
+	extensionsSyncAgeDesc = prometheus.NewDesc("contextionary_extensions_last_sync_age_seconds",
+		"Time since the extensions were last received from the extension storage",
+		[]string{"language"}, nil)
+
+	modelWordsDesc = prometheus.NewDesc("contextionary_model_words",
+		"Words in the contextionary", []string{"language"}, nil)
+	modelDimensionsDesc = prometheus.NewDesc("contextionary_model_dimensions",
+		"Dimensions of the vectors of the contextionary", []string{"language"}, nil)
+)
+
+// languageCollector reads the state of every language whenever the metrics
+// are scraped, nothing before the server is ready
+type languageCollector struct {
+	s *server
+}
+
+func (c *languageCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{
+		cacheHitsDesc, cacheMissesDesc, cacheEvictionsDesc, cacheExpirationsDesc, cacheSizeDesc,
+		cacheHitRatioDesc, splitAttemptsDesc, splitsDesc, splitTimeoutsDesc, extensionsDesc,
+		extensionsSyncAgeDesc, modelWordsDesc, modelDimensionsDesc,
+	} {
+		ch <- desc
+	}
+}
+
+func (c *languageCollector) Collect(ch chan<- prometheus.Metric) {
+	if !c.s.isReady() {
+		// the languages are still being loaded
+		return
+	}
+
+	for name, l := range c.s.languages {
+		c.collectModel(ch, name, l)
+
+		if splitter, ok := l.compoundSplitter.(splitterStats); ok {
+			stats := splitter.Stats()
+			ch <- prometheus.MustNewConstMetric(splitAttemptsDesc, prometheus.CounterValue, float64(stats.Attempts), name)
+			ch <- prometheus.MustNewConstMetric(splitsDesc, prometheus.CounterValue, float64(stats.Splits), name)
+			ch <- prometheus.MustNewConstMetric(splitTimeoutsDesc, prometheus.CounterValue, float64(stats.Timeouts), name)
+		}
+
+		if ext, ok := l.extensionLookerUpper.(extensionStats); ok {
+			ch <- prometheus.MustNewConstMetric(extensionsDesc, prometheus.GaugeValue, float64(ext.Len()), name)
+			if lastSync := ext.LastSync(); !lastSync.IsZero() {
+				ch <- prometheus.MustNewConstMetric(extensionsSyncAgeDesc, prometheus.GaugeValue,
+					time.Since(lastSync).Seconds(), name)
+			}
+		}
+	}
+}
+
+func (c *languageCollector) collectModel(ch chan<- prometheus.Metric, name string, l *language) {
+	m, release := l.models.acquire()
+	defer release()
+
+	ch <- prometheus.MustNewConstMetric(modelWordsDesc, prometheus.GaugeValue, float64(m.raw.GetNumberOfItems()), name)
+	ch <- prometheus.MustNewConstMetric(modelDimensionsDesc, prometheus.GaugeValue, float64(m.raw.GetVectorLength()), name)
+
+	stats := m.vectorizer.CacheStats()
+	ch <- prometheus.MustNewConstMetric(cacheHitsDesc, prometheus.CounterValue, float64(stats.Hits), name)
+	ch <- prometheus.MustNewConstMetric(cacheMissesDesc, prometheus.CounterValue, float64(stats.Misses), name)
+	ch <- prometheus.MustNewConstMetric(cacheEvictionsDesc, prometheus.CounterValue, float64(stats.Evictions), name)
+	ch <- prometheus.MustNewConstMetric(cacheExpirationsDesc, prometheus.CounterValue, float64(stats.Expirations), name)
+	ch <- prometheus.MustNewConstMetric(cacheSizeDesc, prometheus.GaugeValue, float64(stats.Size), name)
+	if lookups := stats.Hits + stats.Misses; lookups > 0 {
+		ch <- prometheus.MustNewConstMetric(cacheHitRatioDesc, prometheus.GaugeValue,
+			float64(stats.Hits)/float64(lookups), name)
+	}
+}
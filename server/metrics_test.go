@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weaviate/contextionary/compoundsplitting"
+	pb "github.com/weaviate/contextionary/contextionary"
+	"github.com/weaviate/contextionary/server/cache"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// metricsC11y knows its size, which the metrics report
+type metricsC11y struct {
+	fakeC11y
+}
+
+func (f *metricsC11y) GetNumberOfItems() int {
+	return 11
+}
+
+func (f *metricsC11y) GetVectorLength() int {
+	return 4
+}
+
+// metricsExtensions were synced a minute ago
+type metricsExtensions struct {
+	fakeExtensionLookerUpper
+}
+
+func (f *metricsExtensions) Len() int {
+	return 3
+}
+
+func (f *metricsExtensions) LastSync() time.Time {
+	return time.Now().Add(-time.Minute)
+}
+
+func newMetricsTestServer(t *testing.T) *server {
+	s := newTestServer(t, &metricsC11y{})
+	s.metrics = newMetrics(s)
+	s.ready = 1
+
+	l := s.languages["en"]
+	l.compoundSplitter = compoundsplitting.NewEmptyTestSplitter()
+	l.extensionLookerUpper = &metricsExtensions{}
+
+	m, release := l.models.acquire()
+	m.vectorizer.words = s.metrics.wordCounter("en")
+	m.vectorizer.cache = cache.New(cache.Options{Size: 10})
+	release()
+
+	return s
+}
+
+func TestRequestMetrics(t *testing.T) {
+	s := newMetricsTestServer(t)
+	info := &grpc.UnaryServerInfo{FullMethod: "/contextionary.Contextionary/VectorForCorpi"}
+	vectorForCorpi := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.VectorForCorpi(ctx, req.(*pb.Corpi))
+	}
+
+	_, err := s.metrics.unaryInterceptor(context.Background(),
+		&pb.Corpi{Corpi: []string{"car"}}, info, vectorForCorpi)
+	require.Nil(t, err)
+	_, err = s.metrics.unaryInterceptor(context.Background(),
+		&pb.Corpi{Corpi: []string{"the"}}, info, vectorForCorpi)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(
+		s.metrics.requests.WithLabelValues(info.FullMethod, "OK")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(
+		s.metrics.requests.WithLabelValues(info.FullMethod, "InvalidArgument")))
+	assert.Equal(t, 1, testutil.CollectAndCount(s.metrics.requestDuration))
+}
+
+func TestWithoutMetrics(t *testing.T) {
+	s := newTestServer(t, &fakeC11y{})
+	s.ready = 1
+	info := &grpc.UnaryServerInfo{FullMethod: "/contextionary.Contextionary/VectorForWord"}
+
+	res, err := s.unaryInterceptor(context.Background(), &pb.Word{Word: "car"}, info,
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return s.VectorForWord(ctx, req.(*pb.Word))
+		})
+	require.Nil(t, err)
+	assert.NotNil(t, res)
+	assert.Nil(t, s.metrics.wordCounter("en"))
+}
+
+func TestWordMetrics(t *testing.T) {
+	s := newMetricsTestServer(t)
+
+	_, err := s.VectorForCorpi(context.Background(), &pb.Corpi{
+		Corpi: []string{"the car is a mercedes", "mercedez"},
+	})
+	require.Nil(t, err)
+
+	expected := `
+# HELP contextionary_vectorized_words_total Single words of vectorized corpi by how they were vectorized: present in the contextionary or an extension, skipped as a stopword, replaced with a suggestion, synthesized from subwords or skipped as unknown
+# TYPE contextionary_vectorized_words_total counter
+contextionary_vectorized_words_total{language="en",outcome="present"} 2
+contextionary_vectorized_words_total{language="en",outcome="stopword"} 3
+contextionary_vectorized_words_total{language="en",outcome="unknown"} 1
+`
+	assert.Nil(t, testutil.CollectAndCompare(s.metrics.words, strings.NewReader(expected)))
+}
+
+func TestLanguageMetrics(t *testing.T) {
+	s := newMetricsTestServer(t)
+
+	for i := 0; i < 3; i++ {
+		_, err := s.VectorForWord(context.Background(), &pb.Word{Word: "car"})
+		require.Nil(t, err)
+	}
+
+	expected := `
+# HELP contextionary_compound_split_attempts_total Attempts to split a word into compounds
+# TYPE contextionary_compound_split_attempts_total counter
+contextionary_compound_split_attempts_total{language="en"} 0
+# HELP contextionary_extensions Extensions of the language
+# TYPE contextionary_extensions gauge
+contextionary_extensions{language="en"} 3
+# HELP contextionary_model_dimensions Dimensions of the vectors of the contextionary
+# TYPE contextionary_model_dimensions gauge
+contextionary_model_dimensions{language="en"} 4
+# HELP contextionary_model_words Words in the contextionary
+# TYPE contextionary_model_words gauge
+contextionary_model_words{language="en"} 11
+# HELP contextionary_vector_cache_hit_ratio Hits of the vector cache per lookup since the model was loaded
+# TYPE contextionary_vector_cache_hit_ratio gauge
+contextionary_vector_cache_hit_ratio{language="en"} 0.6666666666666666
+# HELP contextionary_vector_cache_hits_total Lookups of the vector cache which were cached, reset when the model is reloaded
+# TYPE contextionary_vector_cache_hits_total counter
+contextionary_vector_cache_hits_total{language="en"} 2
+# HELP contextionary_vector_cache_misses_total Lookups of the vector cache which weren't cached, reset when the model is reloaded
+# TYPE contextionary_vector_cache_misses_total counter
+contextionary_vector_cache_misses_total{language="en"} 1
+# HELP contextionary_vector_cache_size Vectors in the cache
+# TYPE contextionary_vector_cache_size gauge
+contextionary_vector_cache_size{language="en"} 1
+`
+	assert.Nil(t, testutil.GatherAndCompare(s.metrics.registry, strings.NewReader(expected),
+		"contextionary_compound_split_attempts_total", "contextionary_extensions",
+		"contextionary_model_dimensions", "contextionary_model_words",
+		"contextionary_vector_cache_hit_ratio", "contextionary_vector_cache_hits_total",
+		"contextionary_vector_cache_misses_total", "contextionary_vector_cache_size"))
+
+	age := testutil.CollectAndCount(&languageCollector{s}, "contextionary_extensions_last_sync_age_seconds")
+	assert.Equal(t, 1, age)
+}
@@ -0,0 +1,293 @@
+package main
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	core "github.com/weaviate/contextionary/contextionary/core"
+	"github.com/weaviate/contextionary/contextionary/core/subword"
+	"github.com/weaviate/contextionary/server/config"
+)
+
+// model bundles the contextionary and everything that is derived from it.
+// When the model files change, a new model is loaded and swapped in as a
+// whole, so a request never mixes item indices or vectors of two models.
+type model struct {
+	// raw is the contextionary as loaded from disk
+	raw core.Contextionary
+
+	// combined is used to serve rpc requests, combination of the raw
+	// contextionary and the schema
+	combined core.Contextionary
+
+	// vectorizer has its own cache, so it is rebuilt with every model
+	vectorizer *Vectorizer
+
+	// subwords synthesize vectors for unknown words, nil unless
+	// ENABLE_SUBWORD_VECTORS is set
+	subwords *subword.Model
+
+	// files is shared by all models with the same raw contextionary and
+	// subword vectors, which are only closed once the last of them is retired
+	files *sharedFiles
+
+	// inFlight counts the requests which are still using this model
+	inFlight sync.WaitGroup
+}
+
+// sharedFiles counts the models which use the same mmapped files. A nil
+// *sharedFiles belongs to a single model.
+type sharedFiles struct {
+	models int32
+}
+
+func newSharedFiles() *sharedFiles {
+	return &sharedFiles{models: 1}
+}
+
+// share the files with one more model
+func (f *sharedFiles) share() *sharedFiles {
+	atomic.AddInt32(&f.models, 1)
+	return f
+}
+
+// release the files of a retired model, true if no other model uses them
+func (f *sharedFiles) release() bool {
+	if f == nil {
+		return true
+	}
+
+	return atomic.AddInt32(&f.models, -1) == 0
+}
+
+// modelHolder holds the current model. Requests acquire the model for their
+// whole duration, so that a replaced model is only closed once all requests
+// which were using it have finished.
+type modelHolder struct {
+	sync.Mutex
+	current *model
+}
+
+// acquire returns the current model and a function which must be called once
+// the caller no longer uses the model
+func (h *modelHolder) acquire() (*model, func()) {
+	h.Lock()
+	m := h.current
+	m.inFlight.Add(1)
+	h.Unlock()
+
+	return m, m.inFlight.Done
+}
+
+// swap makes m the current model and returns the previous one, which can't
+// be acquired anymore
+func (h *modelHolder) swap(m *model) *model {
+	h.Lock()
+	defer h.Unlock()
+
+	old := h.current
+	h.current = m
+	return old
+}
+
+// currentModelVectorizer vectorizes with whichever model is current at the
+// time of the call, it is used by components which outlive a single model
+type currentModelVectorizer struct {
+	models *modelHolder
+}
+
+func (v *currentModelVectorizer) Corpi(ctx context.Context, corpi []string,
+	overrides map[string]string) (*core.Vector, error) {
+	m, release := v.models.acquire()
+	defer release()
+
+	return m.vectorizer.Corpi(ctx, corpi, overrides)
+}
+
+// retire waits for all in-flight requests of the replaced model to finish
+// and releases its mmapped files afterwards, unless they are still used by
+// another model
+func (s *server) retire(old *model) {
+	old.inFlight.Wait()
+
+	if !old.files.release() {
+		s.logger.WithField("action", "model_reload").
+			Debug("released the replaced model, its files are still in use")
+		return
+	}
+
+	released := true
+	if err := closeContextionary(old.raw); err != nil {
+		s.logger.WithField("action", "model_reload").WithError(err).
+			Error("could not release the replaced model")
+		released = false
+	}
+
+	if old.subwords != nil {
+		if err := old.subwords.Close(); err != nil {
+			s.logger.WithField("action", "model_reload").WithError(err).
+				Error("could not release the subword vectors of the replaced model")
+			released = false
+		}
+	}
+
+	if released {
+		s.logger.WithField("action", "model_reload").Info("released the replaced model")
+	}
+}
+
+// closeContextionary releases the mmapped files of contextionaries which
+// have any
+func closeContextionary(c core.Contextionary) error {
+	closer, ok := c.(io.Closer)
+	if !ok {
+		return nil
+	}
+
+	return closer.Close()
+}
+
+// reloadModel loads the model from the configured files and swaps it in. The
+// current model stays in place if the new one can't be loaded.
+func (s *server) reloadModel(l *language) error {
+	l.reload.Lock()
+	defer l.reload.Unlock()
+
+	m, err := s.loadModel(l)
+	if err != nil {
+		return err
+	}
+
+	old := l.models.swap(m)
+	s.logger.WithField("action", "model_reload").
+		WithField("language", l.config.Language).
+		WithField("word_count", m.combined.GetNumberOfItems()).
+		Info("swapped in the new model")
+
+	if old != nil {
+		go s.retire(old)
+	}
+
+	return nil
+}
+
+type modelFileState struct {
+	size    int64
+	modTime time.Time
+
+	// info identifies the file, which stays the same if it is overwritten
+	// in place instead of being replaced
+	info os.FileInfo
+}
+
+func modelFileStates(cfg config.LanguageConfig) ([]modelFileState, error) {
+	var states []modelFileState
+	for _, path := range []string{cfg.KNNFile, cfg.IDXFile} {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+
+		states = append(states, modelFileState{size: info.Size(), modTime: info.ModTime(), info: info})
+	}
+
+	return states, nil
+}
+
+func sameModelFileStates(a, b []modelFileState) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i].size != b[i].size || !a[i].modTime.Equal(b[i].modTime) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// overwrittenInPlace is true if a changed file is still the file which was
+// loaded, rather than a new one which was renamed into place
+func overwrittenInPlace(loaded, current []modelFileState) bool {
+	for i := range loaded {
+		if i >= len(current) || loaded[i].info == nil || current[i].info == nil {
+			continue
+		}
+
+		changed := loaded[i].size != current[i].size || !loaded[i].modTime.Equal(current[i].modTime)
+		if changed && os.SameFile(loaded[i].info, current[i].info) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// watchModel polls the KNN_FILE and IDX_FILE of a language and reloads the
+// model when they change. A change is only picked up once the files have
+// been stable for a full interval, so that a model which is still being
+// written isn't loaded. If the new files can't be loaded, the current model
+// stays in place until the files change again.
+//
+// The files are mmapped, so new files must be renamed into place. Overwriting
+// the loaded files in place, e.g. with cp, truncates them under the mapping,
+// which crashes in-flight requests with SIGBUS. This can't be prevented, but
+// it is logged as an error.
+func (s *server) watchModel(l *language, interval time.Duration, stop <-chan struct{}) {
+	logger := s.logger.WithField("language", l.config.Language)
+	loaded, err := modelFileStates(l.config)
+	if err != nil {
+		logger.WithField("action", "model_watch").WithError(err).
+			Error("cannot watch model files")
+		return
+	}
+
+	var pending []modelFileState
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		current, err := modelFileStates(l.config)
+		if err != nil {
+			// the files might be replaced right now
+			pending = nil
+			continue
+		}
+
+		if sameModelFileStates(current, loaded) {
+			pending = nil
+			continue
+		}
+
+		if !sameModelFileStates(current, pending) {
+			if overwrittenInPlace(loaded, current) {
+				logger.WithField("action", "model_watch").
+					Error("model files were overwritten in place, which corrupts the loaded " +
+						"model and can crash in-flight requests, rename new files into place instead")
+			}
+			logger.WithField("action", "model_watch").
+				Info("model files changed, waiting for them to be stable before reloading")
+			pending = current
+			continue
+		}
+
+		loaded = current
+		pending = nil
+		if err := s.reloadModel(l); err != nil {
+			logger.WithField("action", "model_reload").WithError(err).
+				Error("could not load the changed model files, keeping the current model")
+		}
+	}
+}
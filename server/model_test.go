@@ -0,0 +1,216 @@
+package main
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weaviate/contextionary/contextionary/core/subword"
+	"github.com/weaviate/contextionary/server/config"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type closableC11y struct {
+	fakeC11y
+	closed chan struct{}
+}
+
+func (c *closableC11y) GetNumberOfItems() int {
+	return 0
+}
+
+func (c *closableC11y) Close() error {
+	close(c.closed)
+	return nil
+}
+
+func TestSwappingModels(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	s := &server{logger: logger}
+	l := &language{}
+
+	oldC11y := &closableC11y{closed: make(chan struct{})}
+	old := &model{raw: oldC11y, combined: oldC11y}
+	l.models.swap(old)
+
+	inFlight, release := l.models.acquire()
+	assert.Equal(t, old, inFlight)
+
+	newC11y := &closableC11y{closed: make(chan struct{})}
+	replaced := l.models.swap(&model{raw: newC11y, combined: newC11y})
+	assert.Equal(t, old, replaced)
+	go s.retire(replaced)
+
+	t.Run("new requests use the new model", func(t *testing.T) {
+		m, release := l.models.acquire()
+		defer release()
+		assert.Equal(t, newC11y, m.raw)
+	})
+
+	t.Run("the old model stays open while requests are in flight", func(t *testing.T) {
+		select {
+		case <-oldC11y.closed:
+			t.Fatal("old model was closed while a request was still using it")
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+
+	t.Run("the old model is closed once the requests finished", func(t *testing.T) {
+		release()
+		select {
+		case <-oldC11y.closed:
+		case <-time.After(time.Second):
+			t.Fatal("old model wasn't closed")
+		}
+	})
+}
+
+func TestRetiringModelsWhichShareFiles(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	s := &server{logger: logger}
+	l := &language{normalizer: &fakeNormalizer{}}
+
+	raw := &closableC11y{closed: make(chan struct{})}
+	l.models.swap(&model{raw: raw, combined: raw, files: newSharedFiles()})
+	_, release := l.models.acquire()
+
+	// the schema model shares the files of the acquired one, both are
+	// replaced by a reloaded model
+	require.Nil(t, s.applySchema(l))
+	reloaded := &closableC11y{closed: make(chan struct{})}
+	s.retire(l.models.swap(&model{raw: reloaded, combined: reloaded, files: newSharedFiles()}))
+
+	select {
+	case <-raw.closed:
+		t.Fatal("files were closed while a request was still using a model which shares them")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	release()
+	select {
+	case <-raw.closed:
+	case <-time.After(time.Second):
+		t.Fatal("files weren't closed once all models which share them were retired")
+	}
+}
+
+type failingClosableC11y struct {
+	fakeC11y
+}
+
+func (c *failingClosableC11y) Close() error {
+	return errors.New("munmap failed")
+}
+
+func TestRetiringAModelWhichCantBeClosed(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	s := &server{logger: logger}
+
+	// unmapping the subwords fails as well, as they were never mapped
+	s.retire(&model{raw: &failingClosableC11y{}, subwords: &subword.Model{}})
+
+	require.Len(t, hook.AllEntries(), 2)
+	assert.Equal(t, "could not release the replaced model", hook.AllEntries()[0].Message)
+	assert.Equal(t, "could not release the subword vectors of the replaced model",
+		hook.AllEntries()[1].Message)
+}
+
+func TestComparingModelFileStates(t *testing.T) {
+	now := time.Now()
+	state := func(size int64, modTime time.Time) modelFileState {
+		return modelFileState{size: size, modTime: modTime}
+	}
+	a := []modelFileState{state(10, now), state(20, now)}
+
+	assert.True(t, sameModelFileStates(a, []modelFileState{state(10, now), state(20, now)}))
+	assert.False(t, sameModelFileStates(a, []modelFileState{state(10, now), state(21, now)}))
+	assert.False(t, sameModelFileStates(a, []modelFileState{state(10, now), state(20, now.Add(time.Second))}))
+	assert.False(t, sameModelFileStates(a, nil))
+}
+
+func TestDetectingModelFilesWhichWereOverwrittenInPlace(t *testing.T) {
+	dir, err := ioutil.TempDir("", "model")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	cfg := config.LanguageConfig{
+		KNNFile: filepath.Join(dir, "contextionary.knn"),
+		IDXFile: filepath.Join(dir, "contextionary.idx"),
+	}
+	require.Nil(t, ioutil.WriteFile(cfg.KNNFile, []byte("knn"), 0644))
+	require.Nil(t, ioutil.WriteFile(cfg.IDXFile, []byte("idx"), 0644))
+	loaded, err := modelFileStates(cfg)
+	require.Nil(t, err)
+
+	t.Run("renamed into place", func(t *testing.T) {
+		replacement := filepath.Join(dir, "contextionary.knn.new")
+		require.Nil(t, ioutil.WriteFile(replacement, []byte("new knn"), 0644))
+		require.Nil(t, os.Rename(replacement, cfg.KNNFile))
+
+		current, err := modelFileStates(cfg)
+		require.Nil(t, err)
+		assert.False(t, sameModelFileStates(loaded, current))
+		assert.False(t, overwrittenInPlace(loaded, current))
+		loaded = current
+	})
+
+	t.Run("overwritten in place", func(t *testing.T) {
+		require.Nil(t, ioutil.WriteFile(cfg.IDXFile, []byte("new idx"), 0644))
+
+		current, err := modelFileStates(cfg)
+		require.Nil(t, err)
+		assert.True(t, overwrittenInPlace(loaded, current))
+	})
+}
+
+func TestSelectingLanguages(t *testing.T) {
+	s := &server{
+		config: &config.Config{
+			Languages:                []config.LanguageConfig{{Language: "en"}, {Language: "de"}},
+			DefaultLanguage:          "en",
+			ExtensionsLegacyLanguage: "en",
+		},
+		languages: map[string]*language{
+			"en": {config: config.LanguageConfig{Language: "en"}},
+			"de": {config: config.LanguageConfig{Language: "de"}},
+		},
+	}
+
+	t.Run("an empty language is the default language", func(t *testing.T) {
+		l, err := s.language("")
+		require.Nil(t, err)
+		assert.Equal(t, "en", l.config.Language)
+		assert.Equal(t, "", s.extensionsNamespace(l))
+	})
+
+	t.Run("selecting another language", func(t *testing.T) {
+		l, err := s.language("de")
+		require.Nil(t, err)
+		assert.Equal(t, "de", l.config.Language)
+		assert.Equal(t, "de", s.extensionsNamespace(l))
+	})
+
+	t.Run("the unprefixed extensions don't follow the default language", func(t *testing.T) {
+		cfg := *s.config
+		cfg.DefaultLanguage = "de"
+		s := &server{config: &cfg, languages: s.languages}
+
+		assert.Equal(t, "", s.extensionsNamespace(s.languages["en"]))
+		assert.Equal(t, "de", s.extensionsNamespace(s.languages["de"]))
+
+		cfg.ExtensionsLegacyLanguage = ""
+		assert.Equal(t, "en", s.extensionsNamespace(s.languages["en"]))
+	})
+
+	t.Run("selecting a language which isn't served", func(t *testing.T) {
+		_, err := s.language("fr")
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
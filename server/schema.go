@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+
+	core "github.com/weaviate/contextionary/contextionary/core"
+	schema "github.com/weaviate/contextionary/contextionary/schema"
+)
+
+// schemaHolder holds the most recently retrieved weaviate schema, every
+// model is combined with it
+type schemaHolder struct {
+	sync.Mutex
+	classes []schema.Class
+}
+
+func (h *schemaHolder) get() []schema.Class {
+	h.Lock()
+	defer h.Unlock()
+
+	return h.classes
+}
+
+func (h *schemaHolder) set(classes []schema.Class) {
+	h.Lock()
+	defer h.Unlock()
+
+	h.classes = classes
+}
+
+// buildContextionary combines the raw contextionary with the centroids of
+// the classes and properties of the current schema, which is what the
+// schema search looks for. Any time the schema changes the contextionary
+// needs to be rebuilt.
+func (s *server) buildContextionary(l *language, raw core.Contextionary) (core.Contextionary, error) {
+	classes := s.schema.get()
+	if len(classes) == 0 {
+		return raw, nil
+	}
+
+	index, errs := schema.NewWithNormalizer(raw, l.normalizer.Normalize).BuildIndex(classes)
+	for _, err := range errs {
+		s.logger.WithField("action", "schema_index").WithError(err).
+			Warn("left out of the schema search")
+	}
+
+	if index == nil {
+		return raw, nil
+	}
+
+	combined, err := core.CombineVectorIndices([]core.Contextionary{raw, index})
+	if err != nil {
+		return nil, fmt.Errorf("combine contextionary with schema: %v", err)
+	}
+
+	return combined, nil
+}
+
+// watchSchema rebuilds the contextionaries of all languages whenever the
+// schema changes
+func (s *server) watchSchema(updates <-chan []schema.Class, stop <-chan struct{}) {
+	for {
+		var classes []schema.Class
+		select {
+		case <-stop:
+			return
+		case classes = <-updates:
+		}
+
+		s.schema.set(classes)
+		for _, l := range s.languages {
+			if err := s.applySchema(l); err != nil {
+				s.logger.WithField("action", "schema_update").
+					WithField("language", l.config.Language).
+					WithError(err).
+					Error("could not combine the contextionary with the new schema, keeping the current one")
+			}
+		}
+	}
+}
+
+// applySchema replaces the current model of the language with one that is
+// combined with the current schema
+func (s *server) applySchema(l *language) error {
+	l.reload.Lock()
+	defer l.reload.Unlock()
+
+	current, release := l.models.acquire()
+	combined, err := s.buildContextionary(l, current.raw)
+	release()
+	if err != nil {
+		return err
+	}
+
+	// The new model shares the files of the current one, they are only closed
+	// once both are retired
+	replaced := l.models.swap(&model{
+		raw:        current.raw,
+		combined:   combined,
+		vectorizer: current.vectorizer,
+		subwords:   current.subwords,
+		files:      current.files.share(),
+	})
+	go s.retire(replaced)
+
+	s.logger.WithField("action", "schema_update").
+		WithField("language", l.config.Language).
+		WithField("word_count", combined.GetNumberOfItems()).
+		Info("combined the contextionary with the new schema")
+
+	return nil
+}
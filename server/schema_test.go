@@ -0,0 +1,53 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	core "github.com/weaviate/contextionary/contextionary/core"
+	"github.com/weaviate/contextionary/contextionary/core/subword"
+	schema "github.com/weaviate/contextionary/contextionary/schema"
+)
+
+func TestApplyingSchema(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+	s := &server{logger: logger}
+
+	builder := core.InMemoryBuilder(3)
+	builder.AddWord("city", core.NewVector([]float32{5, 5, 5}))
+	builder.AddWord("population", core.NewVector([]float32{1, 5, 1}))
+	raw := builder.Build(3)
+
+	l := &language{normalizer: &fakeNormalizer{}}
+	subwords := &subword.Model{}
+	l.models.swap(&model{raw: raw, combined: raw, subwords: subwords, files: newSharedFiles()})
+
+	t.Run("without a schema the raw contextionary is used", func(t *testing.T) {
+		require.Nil(t, s.applySchema(l))
+
+		m, release := l.models.acquire()
+		defer release()
+		assert.Equal(t, core.Contextionary(raw), m.combined)
+	})
+
+	t.Run("with a schema the centroids are added", func(t *testing.T) {
+		s.schema.set([]schema.Class{{
+			Name:       "City",
+			Properties: []schema.Property{{Name: "population"}},
+		}})
+		require.Nil(t, s.applySchema(l))
+
+		m, release := l.models.acquire()
+		defer release()
+		assert.Equal(t, core.Contextionary(raw), m.raw)
+		assert.Equal(t, subwords, m.subwords)
+		assert.Equal(t, 4, m.combined.GetNumberOfItems())
+
+		for _, word := range []string{"city", "$OBJECT[City]", "$OBJECT[City][population]"} {
+			i := m.combined.WordToItemIndex(word)
+			assert.True(t, i.IsPresent(), "%s should be in the combined contextionary", word)
+		}
+	})
+}
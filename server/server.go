@@ -6,11 +6,8 @@ import (
 	"os"
 
 	"github.com/sirupsen/logrus"
-	pb "github.com/weaviate/contextionary/contextionary"
-	core "github.com/weaviate/contextionary/contextionary/core"
-	"github.com/weaviate/contextionary/extensions"
 	"github.com/weaviate/contextionary/server/config"
-	grpc "google.golang.org/grpc"
+	"google.golang.org/grpc/health"
 )
 
 // Version is filled through a build arg
@@ -19,35 +16,62 @@ var Version string
 func main() {
 	server := new()
 	server.logger.WithField("version", Version).Info()
-	grpcServer := grpc.NewServer()
-	pb.RegisterContextionaryServer(grpcServer, server)
+	grpcServer := server.newGRPCServer()
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", server.config.ServerPort))
 	if err != nil {
 		server.logger.Errorf("can't listen on port: %s", err)
 		os.Exit(1)
 	}
 
-	grpcServer.Serve(lis)
+	if server.config.MetricsPort > 0 {
+		go server.serveMetrics()
+	}
+
+	// the health service reports NOT_SERVING while the languages are loaded
+	go func() {
+		err := server.init()
+		if err != nil {
+			server.logger.
+				WithError(err).
+				Errorf("cannot start up")
+			os.Exit(1)
+		}
+
+		server.becomeReady()
+	}()
+
+	go server.shutdownOnSignal(grpcServer)
+
+	if err := grpcServer.Serve(lis); err != nil {
+		server.logger.WithError(err).Errorf("can't serve")
+		os.Exit(1)
+	}
+	server.logger.WithField("action", "shutdown").Info("stopped")
 }
 
 type server struct {
-	// to be used to serve rpc requests, combination of the raw contextionary
-	// and the schema
-	combinedContextionary core.Contextionary
+	// every served language by its name
+	languages map[string]*language
 
-	// initialized at startup, to be used to build the
-	// schema contextionary
-	rawContextionary core.Contextionary
+	// the weaviate schema, retrieved from SCHEMA_PROVIDER_URL
+	schema schemaHolder
 
 	config *config.Config
 
 	logger logrus.FieldLogger
 
-	// ucs
-	extensionStorer      *extensions.Storer
-	extensionLookerUpper extensionLookerUpper
-	stopwordDetector     stopwordDetector
-	vectorizer           *Vectorizer
+	// metrics of the server, nil in tests, in which case nothing is counted
+	metrics *metrics
+
+	// health reports NOT_SERVING until the server is ready
+	health *health.Server
+
+	// ready is set to 1 once the languages are loaded and their extensions
+	// were received, requests to the contextionary are refused before
+	ready int32
+
+	// closed when the server shuts down
+	stop chan struct{}
 }
 
 // new gRPC server to serve the contextionary
@@ -75,15 +99,10 @@ func new() *server {
 	s := &server{
 		config: cfg,
 		logger: logger,
+		stop:   make(chan struct{}),
+		health: newHealthServer(),
 	}
-
-	err = s.init()
-	if err != nil {
-		logger.
-			WithError(err).
-			Errorf("cannot start up")
-		os.Exit(1)
-	}
+	s.metrics = newMetrics(s)
 
 	return s
 }
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	pb "github.com/weaviate/contextionary/contextionary"
+)
+
+// VectorizeStream vectorizes documents as they arrive and sends their
+// vectors back as soon as they are done. At most STREAM_WORKERS documents are
+// vectorized at a time. No further documents are received while all workers
+// are busy, and the workers wait while the client doesn't read the
+// responses, so gRPC's flow control slows down a client which sends faster
+// than the documents can be vectorized.
+func (s *server) VectorizeStream(stream pb.Contextionary_VectorizeStreamServer) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	requests := make(chan *pb.VectorizeRequest)
+	responses := make(chan *pb.VectorizeResponse)
+
+	workers := &sync.WaitGroup{}
+	for i := 0; i < s.config.StreamWorkers; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for req := range requests {
+				select {
+				case responses <- s.vectorizeStreamItem(ctx, req):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		workers.Wait()
+		close(responses)
+	}()
+
+	// only a single goroutine may receive and a single one send at a time
+	recvErr := make(chan error, 1)
+	go func() {
+		defer close(requests)
+		for {
+			req, err := stream.Recv()
+			if err == io.EOF {
+				recvErr <- nil
+				return
+			}
+			if err != nil {
+				recvErr <- err
+				cancel()
+				return
+			}
+
+			select {
+			case requests <- req:
+			case <-ctx.Done():
+				recvErr <- ctx.Err()
+				return
+			}
+		}
+	}()
+
+	for res := range responses {
+		if err := stream.Send(res); err != nil {
+			s.logger.WithField("action", "vectorize_stream").
+				WithError(err).Debug("could not send response, closing stream")
+			return err
+		}
+	}
+
+	return <-recvErr
+}
+
+func (s *server) vectorizeStreamItem(ctx context.Context, req *pb.VectorizeRequest) *pb.VectorizeResponse {
+	if req.Corpi == nil {
+		req.Corpi = &pb.Corpi{}
+	}
+
+	vector, err := s.vectorForCorpi(ctx, req.Corpi)
+	if err != nil {
+		return &pb.VectorizeResponse{Id: req.Id, Error: itemErrorToProto(err)}
+	}
+
+	return &pb.VectorizeResponse{Id: req.Id, Vector: vector}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	pb "github.com/weaviate/contextionary/contextionary"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+type fakeVectorizeStream struct {
+	grpc.ServerStream
+	ctx context.Context
+	sync.Mutex
+	requests  []*pb.VectorizeRequest
+	responses []*pb.VectorizeResponse
+}
+
+func (f *fakeVectorizeStream) Context() context.Context {
+	return f.ctx
+}
+
+func (f *fakeVectorizeStream) Recv() (*pb.VectorizeRequest, error) {
+	f.Lock()
+	defer f.Unlock()
+	if len(f.requests) == 0 {
+		return nil, io.EOF
+	}
+
+	req := f.requests[0]
+	f.requests = f.requests[1:]
+	return req, nil
+}
+
+func (f *fakeVectorizeStream) Send(res *pb.VectorizeResponse) error {
+	f.Lock()
+	defer f.Unlock()
+	f.responses = append(f.responses, res)
+	return nil
+}
+
+func TestVectorizeStream(t *testing.T) {
+	s := newTestServer(t, &fakeC11y{})
+
+	stream := &fakeVectorizeStream{
+		ctx: context.Background(),
+		requests: []*pb.VectorizeRequest{
+			{Id: "car", Corpi: &pb.Corpi{Corpi: []string{"car"}}},
+			{Id: "mercedes", Corpi: &pb.Corpi{Corpi: []string{"mercedes"}}},
+			{Id: "empty", Corpi: &pb.Corpi{}},
+			{Id: "french", Corpi: &pb.Corpi{Corpi: []string{"voiture"}, Language: "fr"}},
+			{Id: "no corpi"},
+		},
+	}
+	require.Nil(t, s.VectorizeStream(stream))
+
+	byID := map[string]*pb.VectorizeResponse{}
+	for _, res := range stream.responses {
+		byID[res.Id] = res
+	}
+	require.Len(t, byID, 5)
+
+	t.Run("vectorized documents", func(t *testing.T) {
+		require.Nil(t, byID["car"].Error)
+		car := vectorFromProto(byID["car"].Vector)
+		assert.Equal(t, []float32{1, 2, 0, 0}, car.ToArray())
+
+		require.Nil(t, byID["mercedes"].Error)
+		mercedes := vectorFromProto(byID["mercedes"].Vector)
+		assert.Equal(t, mercedesVector, mercedes.ToArray())
+	})
+
+	t.Run("documents which fail don't affect the others", func(t *testing.T) {
+		for _, id := range []string{"empty", "french", "no corpi"} {
+			assert.Nil(t, byID[id].Vector, id)
+			require.NotNil(t, byID[id].Error, id)
+			assert.Equal(t, uint32(codes.InvalidArgument), byID[id].Error.Code, id)
+		}
+	})
+}
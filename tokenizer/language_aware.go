@@ -0,0 +1,89 @@
+package tokenizer
+
+import (
+	"unicode"
+)
+
+// LanguageAwareTokenizer splits like the UnicodeWordTokenizer. Scripts which
+// don't separate words by spaces, such as Chinese and Japanese, would turn
+// whole sentences into single tokens that way, so runs of such characters are
+// split into overlapping bigrams instead.
+type LanguageAwareTokenizer struct{}
+
+func NewLanguageAware() *LanguageAwareTokenizer {
+	return &LanguageAwareTokenizer{}
+}
+
+func (t *LanguageAwareTokenizer) Tokenize(corpus string) []string {
+	var tokens []string
+	var word []rune
+	var run []rune
+
+	flushWord := func() {
+		if len(word) > 0 {
+			tokens = append(tokens, string(word))
+			word = nil
+		}
+	}
+
+	flushRun := func() {
+		tokens = append(tokens, bigrams(run)...)
+		run = nil
+	}
+
+	for _, r := range corpus {
+		switch {
+		case isUnsegmented(r):
+			flushWord()
+			run = append(run, r)
+		case isSeparator(r):
+			flushWord()
+			flushRun()
+		default:
+			flushRun()
+			word = append(word, r)
+		}
+	}
+	flushWord()
+	flushRun()
+
+	return tokens
+}
+
+// Compound doesn't combine bigrams, they overlap and are no words of their
+// own
+func (t *LanguageAwareTokenizer) Compound(tokens []string) (string, bool) {
+	if len(tokens) == 1 {
+		return tokens[0], true
+	}
+
+	for _, token := range tokens {
+		for _, r := range token {
+			if isUnsegmented(r) {
+				return "", false
+			}
+		}
+	}
+
+	return compound(tokens), true
+}
+
+func bigrams(run []rune) []string {
+	if len(run) == 1 {
+		return []string{string(run)}
+	}
+
+	var out []string
+	for i := 0; i+1 < len(run); i++ {
+		out = append(out, string(run[i:i+2]))
+	}
+
+	return out
+}
+
+// isUnsegmented is true for scripts which are written without spaces
+// between words
+func isUnsegmented(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Thai,
+		unicode.Lao, unicode.Khmer, unicode.Myanmar)
+}
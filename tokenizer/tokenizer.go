@@ -0,0 +1,62 @@
+// Package tokenizer splits corpi into the words which are looked up in the
+// contextionary. Which tokenizer works best depends on the language and on
+// the kind of text that is vectorized, so they can be selected per language
+// and per request by name.
+package tokenizer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Tokenizer splits a corpus into tokens
+type Tokenizer interface {
+	// Tokenize splits the corpus into the words which are looked up in the
+	// contextionary
+	Tokenize(corpus string) []string
+
+	// Compound joins consecutive tokens into the word under which the
+	// contextionary stores them as a compound word. It returns false if the
+	// tokens can't form a compound word.
+	Compound(tokens []string) (string, bool)
+}
+
+const (
+	// UnicodeWord splits on every rune that is neither a letter nor a number
+	UnicodeWord = "unicode-word"
+	// Whitespace splits on whitespace only and trims punctuation from the
+	// tokens, so that "e-mail", "C++" and "3.5" stay intact
+	Whitespace = "whitespace"
+	// LanguageAware splits like UnicodeWord, but splits scripts which don't
+	// separate words by spaces, such as Chinese and Japanese, into
+	// overlapping bigrams
+	LanguageAware = "language-aware"
+	// URLPreserving splits like UnicodeWord, but keeps URLs and email
+	// addresses as single tokens
+	URLPreserving = "url-preserving"
+)
+
+// Names of all tokenizers which can be created through New
+var Names = []string{UnicodeWord, Whitespace, LanguageAware, URLPreserving}
+
+// New creates the tokenizer with the specified name
+func New(name string) (Tokenizer, error) {
+	switch name {
+	case UnicodeWord:
+		return NewUnicodeWord(), nil
+	case Whitespace:
+		return NewWhitespace(), nil
+	case LanguageAware:
+		return NewLanguageAware(), nil
+	case URLPreserving:
+		return NewURLPreserving(), nil
+	default:
+		return nil, fmt.Errorf("unknown tokenizer '%s', must be one of '%s'",
+			name, strings.Join(Names, "', '"))
+	}
+}
+
+// compound is how the contextionary stores compound words
+func compound(tokens []string) string {
+	return strings.Join(tokens, "_")
+}
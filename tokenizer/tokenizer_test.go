@@ -0,0 +1,212 @@
+package tokenizer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_UnicodeWord(t *testing.T) {
+	type testcase struct {
+		name   string
+		input  string
+		output []string
+	}
+
+	tests := []testcase{
+		testcase{
+			name:   "single word",
+			input:  "single",
+			output: []string{"single"},
+		},
+		testcase{
+			name:   "words separated by space",
+			input:  "hello my name is John",
+			output: []string{"hello", "my", "name", "is", "John"},
+		},
+		testcase{
+			name:   "multiple spaces in between words",
+			input:  "hello     John",
+			output: []string{"hello", "John"},
+		},
+
+		testcase{
+			name:   "words with numbers",
+			input:  "foo1 foo2",
+			output: []string{"foo1", "foo2"},
+		},
+
+		testcase{
+			name:   "hyphenated words",
+			input:  "r2-d2",
+			output: []string{"r2", "d2"},
+		},
+
+		testcase{
+			name:   "on commas (with and without spaces)",
+			input:  "jane, john,anna",
+			output: []string{"jane", "john", "anna"},
+		},
+
+		testcase{
+			name:   "on other characters",
+			input:  "foobar baz#(*@@baq",
+			output: []string{"foobar", "baz", "baq"},
+		},
+
+		testcase{
+			name:   "words containing umlauts (upper and lower)",
+			input:  "Ölpreis über 80 dollar!",
+			output: []string{"Ölpreis", "über", "80", "dollar"},
+		},
+
+		testcase{
+			name:   "words containing turkish characters",
+			input:  "Ölpreis über 80 dollar!",
+			output: []string{"Ölpreis", "über", "80", "dollar"},
+		},
+
+		testcase{
+			name:   "words containing turkish characters",
+			input:  "Weaviate ayrıca Türkçe konuşabilir",
+			output: []string{"Weaviate", "ayrıca", "Türkçe", "konuşabilir"},
+		},
+
+		testcase{
+			name:   "mixed characters including a '<'",
+			input:  "car, car#of,,,,brand<mercedes, color!!blue",
+			output: []string{"car", "car", "of", "brand", "mercedes", "color", "blue"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			out := NewUnicodeWord().Tokenize(test.input)
+			assert.Equal(t, test.output, out, "output matches expected output")
+		})
+
+	}
+}
+
+type tokenizerTest struct {
+	name   string
+	input  string
+	output []string
+}
+
+func runTokenizerTests(t *testing.T, tokenizer Tokenizer, tests []tokenizerTest) {
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			out := tokenizer.Tokenize(test.input)
+			assert.Equal(t, test.output, out, "output matches expected output")
+		})
+	}
+}
+
+func Test_Whitespace(t *testing.T) {
+	runTokenizerTests(t, NewWhitespace(), []tokenizerTest{
+		{
+			name:   "words separated by spaces",
+			input:  "hello   my name is\tJohn",
+			output: []string{"hello", "my", "name", "is", "John"},
+		},
+		{
+			name:   "punctuation around words",
+			input:  "hello, \"John\"!",
+			output: []string{"hello", "John"},
+		},
+		{
+			name:   "punctuation inside of words",
+			input:  "send an e-mail about C++ 3.5",
+			output: []string{"send", "an", "e-mail", "about", "C++", "3.5"},
+		},
+		{
+			name:   "only punctuation",
+			input:  "a - b",
+			output: []string{"a", "b"},
+		},
+	})
+}
+
+func Test_LanguageAware(t *testing.T) {
+	runTokenizerTests(t, NewLanguageAware(), []tokenizerTest{
+		{
+			name:   "words separated by spaces",
+			input:  "hello my name is John",
+			output: []string{"hello", "my", "name", "is", "John"},
+		},
+		{
+			name:   "chinese text",
+			input:  "我爱北京",
+			output: []string{"我爱", "爱北", "北京"},
+		},
+		{
+			name:   "a single chinese character",
+			input:  "car 车",
+			output: []string{"car", "车"},
+		},
+		{
+			name:   "mixed scripts",
+			input:  "東京はTokyo, 大阪",
+			output: []string{"東京", "京は", "Tokyo", "大阪"},
+		},
+	})
+
+	t.Run("compounds", func(t *testing.T) {
+		c, ok := NewLanguageAware().Compound([]string{"new", "york"})
+		assert.True(t, ok)
+		assert.Equal(t, "new_york", c)
+
+		c, ok = NewLanguageAware().Compound([]string{"北京"})
+		assert.True(t, ok)
+		assert.Equal(t, "北京", c)
+
+		_, ok = NewLanguageAware().Compound([]string{"我爱", "爱北"})
+		assert.False(t, ok)
+	})
+}
+
+func Test_URLPreserving(t *testing.T) {
+	runTokenizerTests(t, NewURLPreserving(), []tokenizerTest{
+		{
+			name:   "no urls",
+			input:  "car, car#of brand",
+			output: []string{"car", "car", "of", "brand"},
+		},
+		{
+			name:   "a url",
+			input:  "see https://weaviate.io/developers?page=1 for details",
+			output: []string{"see", "https://weaviate.io/developers?page=1", "for", "details"},
+		},
+		{
+			name:   "a url at the end of a sentence",
+			input:  "visit www.weaviate.io.",
+			output: []string{"visit", "www.weaviate.io"},
+		},
+		{
+			name:   "an email address",
+			input:  "mail hello@weaviate.io, please",
+			output: []string{"mail", "hello@weaviate.io", "please"},
+		},
+	})
+
+	t.Run("compounds", func(t *testing.T) {
+		_, ok := NewURLPreserving().Compound([]string{"mail", "hello@weaviate.io"})
+		assert.False(t, ok)
+
+		c, ok := NewURLPreserving().Compound([]string{"hello@weaviate.io"})
+		assert.True(t, ok)
+		assert.Equal(t, "hello@weaviate.io", c)
+	})
+}
+
+func Test_New(t *testing.T) {
+	for _, name := range Names {
+		tokenizer, err := New(name)
+		assert.Nil(t, err)
+		assert.NotNil(t, tokenizer)
+	}
+
+	_, err := New("sentencepiece")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,26 @@
+package tokenizer
+
+import (
+	"strings"
+	"unicode"
+)
+
+// UnicodeWordTokenizer splits on every rune that is neither a letter nor a
+// number
+type UnicodeWordTokenizer struct{}
+
+func NewUnicodeWord() *UnicodeWordTokenizer {
+	return &UnicodeWordTokenizer{}
+}
+
+func (t *UnicodeWordTokenizer) Tokenize(corpus string) []string {
+	return strings.FieldsFunc(corpus, isSeparator)
+}
+
+func (t *UnicodeWordTokenizer) Compound(tokens []string) (string, bool) {
+	return compound(tokens), true
+}
+
+func isSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+}
@@ -0,0 +1,48 @@
+package tokenizer
+
+import (
+	"regexp"
+	"strings"
+)
+
+// URLPreservingTokenizer splits like the UnicodeWordTokenizer, but keeps
+// URLs and email addresses as single tokens
+type URLPreservingTokenizer struct {
+	words *UnicodeWordTokenizer
+}
+
+var urlOrEmail = regexp.MustCompile(
+	`(?i)\b(?:(?:https?|ftp)://[^\s<>"']+|www\.[^\s<>"']+|[a-z0-9._%+\-]+@[a-z0-9.\-]+\.[a-z]{2,})`)
+
+func NewURLPreserving() *URLPreservingTokenizer {
+	return &URLPreservingTokenizer{words: NewUnicodeWord()}
+}
+
+func (t *URLPreservingTokenizer) Tokenize(corpus string) []string {
+	var tokens []string
+	pos := 0
+	for _, match := range urlOrEmail.FindAllStringIndex(corpus, -1) {
+		tokens = append(tokens, t.words.Tokenize(corpus[pos:match[0]])...)
+		// sentence punctuation directly after a URL isn't part of it
+		tokens = append(tokens, strings.TrimRight(corpus[match[0]:match[1]], ".,;:!?)"))
+		pos = match[1]
+	}
+	tokens = append(tokens, t.words.Tokenize(corpus[pos:])...)
+
+	return tokens
+}
+
+// Compound doesn't combine URLs and email addresses with other tokens
+func (t *URLPreservingTokenizer) Compound(tokens []string) (string, bool) {
+	if len(tokens) == 1 {
+		return tokens[0], true
+	}
+
+	for _, token := range tokens {
+		if urlOrEmail.MatchString(token) {
+			return "", false
+		}
+	}
+
+	return compound(tokens), true
+}
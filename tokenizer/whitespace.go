@@ -0,0 +1,34 @@
+package tokenizer
+
+import (
+	"strings"
+	"unicode"
+)
+
+// WhitespaceTokenizer splits on whitespace only. Punctuation is trimmed from
+// both ends of a token, so that "hello," becomes "hello", whereas "e-mail",
+// "C++" and "3.5" stay intact.
+type WhitespaceTokenizer struct{}
+
+func NewWhitespace() *WhitespaceTokenizer {
+	return &WhitespaceTokenizer{}
+}
+
+func (t *WhitespaceTokenizer) Tokenize(corpus string) []string {
+	fields := strings.Fields(corpus)
+	tokens := make([]string, 0, len(fields))
+	for _, field := range fields {
+		token := strings.TrimFunc(field, unicode.IsPunct)
+		if token == "" {
+			continue
+		}
+
+		tokens = append(tokens, token)
+	}
+
+	return tokens
+}
+
+func (t *WhitespaceTokenizer) Compound(tokens []string) (string, bool) {
+	return compound(tokens), true
+}
@@ -7,7 +7,7 @@ MODEL_VERSION=0.16.0
 
 #Build the server
 VERSION=1.2.0
-CGO_ENABLED=1 go build -o ./contextionary-server -a -tags netgo -ldflags "-w -X main.Version=$VERSION" ./server
+CGO_ENABLED=0 go build -o ./contextionary-server -a -ldflags "-w -X main.Version=$VERSION" ./server
 
 #Generate contextionary
 tools/dev/gen_simple_contextionary.sh